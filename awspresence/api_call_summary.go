@@ -0,0 +1,119 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// apiCallStats holds the counters recorded for a single API operation by the
+// session handlers, across all the requests made by the provider process.
+type apiCallStats struct {
+	Requests  int
+	Attempts  int
+	Throttles int
+	Errors    int
+}
+
+var apiCallMetrics = struct {
+	sync.Mutex
+	enabled bool
+	ops     map[string]*apiCallStats
+}{ops: make(map[string]*apiCallStats)}
+
+// enableApiCallSummary turns on the summary logged by LogApiCallSummary.
+func enableApiCallSummary() {
+	apiCallMetrics.Lock()
+	apiCallMetrics.enabled = true
+	apiCallMetrics.Unlock()
+}
+
+// addApiCallSummaryHandlers records every request made with handlers under
+// the service and operation it calls, e.g. "elasticloadbalancing:CreateRule".
+func addApiCallSummaryHandlers(handlers *request.Handlers) {
+	handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{
+		Name: "awspresence.ApiCallSummaryAttempt",
+		Fn: func(r *request.Request) {
+			recordApiCall(r, func(stats *apiCallStats) {
+				stats.Attempts++
+				if isAdaptiveRetryThrottle(r) {
+					stats.Throttles++
+				}
+			})
+		},
+	})
+
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "awspresence.ApiCallSummaryRequest",
+		Fn: func(r *request.Request) {
+			recordApiCall(r, func(stats *apiCallStats) {
+				stats.Requests++
+				if r.Error != nil {
+					stats.Errors++
+				}
+			})
+		},
+	})
+}
+
+func recordApiCall(r *request.Request, fn func(stats *apiCallStats)) {
+	if r.Operation == nil {
+		return
+	}
+	op := r.ClientInfo.ServiceName + ":" + r.Operation.Name
+
+	apiCallMetrics.Lock()
+	defer apiCallMetrics.Unlock()
+
+	stats, ok := apiCallMetrics.ops[op]
+	if !ok {
+		stats = &apiCallStats{}
+		apiCallMetrics.ops[op] = stats
+	}
+	fn(stats)
+}
+
+// apiCallSummary formats the recorded counters, one operation per line in
+// alphabetical order.
+func apiCallSummary() string {
+	apiCallMetrics.Lock()
+	defer apiCallMetrics.Unlock()
+
+	ops := make([]string, 0, len(apiCallMetrics.ops))
+	for op := range apiCallMetrics.ops {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	var b strings.Builder
+	for _, op := range ops {
+		stats := apiCallMetrics.ops[op]
+		fmt.Fprintf(&b, "  %s: %d request(s), %d attempt(s), %d throttled, %d failed\n",
+			op, stats.Requests, stats.Attempts, stats.Throttles, stats.Errors)
+	}
+
+	return b.String()
+}
+
+// LogApiCallSummary logs the API calls made by the provider process when the
+// api_call_summary provider argument is set. It is called once the plugin
+// server shuts down, at the end of a Terraform run.
+func LogApiCallSummary() {
+	apiCallMetrics.Lock()
+	enabled := apiCallMetrics.enabled
+	apiCallMetrics.Unlock()
+
+	if !enabled {
+		return
+	}
+
+	summary := apiCallSummary()
+	if summary == "" {
+		summary = "  no API calls made\n"
+	}
+	log.Printf("[INFO] AWS API call summary:\n%s", summary)
+}
@@ -0,0 +1,61 @@
+package awspresence
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+func TestAddApiCallSummaryHandlers(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Region:      aws.String("us-west-2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addApiCallSummaryHandlers(&sess.Handlers)
+
+	conn := elbv2.New(sess)
+	conn.Handlers.Send.Clear()
+	conn.Handlers.Unmarshal.Clear()
+	conn.Handlers.UnmarshalMeta.Clear()
+	conn.Handlers.ValidateResponse.Clear()
+	conn.Handlers.UnmarshalError.Clear()
+	conn.Handlers.AfterRetry.Clear()
+	conn.Handlers.AfterRetry.PushBack(func(r *request.Request) {
+		r.Retryable = aws.Bool(r.RetryCount < 1)
+		if aws.BoolValue(r.Retryable) {
+			r.RetryCount++
+			r.Error = nil
+		}
+	})
+
+	throttle := true
+	conn.Handlers.Send.PushBack(func(r *request.Request) {
+		if throttle {
+			r.Error = awserr.New("Throttling", "Rate exceeded", nil)
+		}
+	})
+
+	// Throttled twice, exhausting the single retry.
+	if _, err := conn.DescribeRules(&elbv2.DescribeRulesInput{}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	throttle = false
+	if _, err := conn.DescribeRules(&elbv2.DescribeRulesInput{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "  elasticloadbalancing:DescribeRules: 2 request(s), 3 attempt(s), 2 throttled, 1 failed\n"
+	if summary := apiCallSummary(); !strings.Contains(summary, expected) {
+		t.Fatalf("expected summary to contain %q, got:\n%s", expected, summary)
+	}
+}
@@ -1,18 +1,70 @@
 package awspresence
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 )
 
+// lbNotFoundErrCodes are the error codes the ELB and ELBv2 APIs return for a
+// load balancer, or one of its listeners, rules, target groups or policies,
+// that no longer exists.
+var lbNotFoundErrCodes = []string{
+	elbv2.ErrCodeListenerNotFoundException,
+	elbv2.ErrCodeLoadBalancerNotFoundException,
+	elbv2.ErrCodeRuleNotFoundException,
+	elbv2.ErrCodeTargetGroupNotFoundException,
+	elb.ErrCodePolicyNotFoundException,
+}
+
 // Returns true if the error matches all these conditions:
 //  * err is of type awserr.Error
 //  * Error.Code() matches code
 //  * Error.Message() contains message
+//
+// New code should use isAWSErrCode or isAWSErrMessage, which also match
+// wrapped errors.
 func isAWSErr(err error, code string, message string) bool {
 	if err, ok := err.(awserr.Error); ok {
 		return err.Code() == code && strings.Contains(err.Message(), message)
 	}
 	return false
 }
+
+// isAWSErrCode reports whether err, or an error it wraps, is an awserr.Error
+// with one of codes.
+func isAWSErrCode(err error, codes ...string) bool {
+	return isAWSErrMessage(err, "", codes...)
+}
+
+// isAWSErrMessage reports whether err, or an error it wraps, is an
+// awserr.Error with one of codes and a message containing message, ignoring
+// case.
+func isAWSErrMessage(err error, message string, codes ...string) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+
+	if !strings.Contains(strings.ToLower(awsErr.Message()), strings.ToLower(message)) {
+		return false
+	}
+
+	for _, code := range codes {
+		if awsErr.Code() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isLbNotFoundErr reports whether err says the LB-family resource an API call
+// targeted, or the resource it belongs to, is already gone. Delete functions
+// treat such errors as success, so that removing resources deleted out of
+// band never fails an apply.
+func isLbNotFoundErr(err error) bool {
+	return isAWSErrCode(err, lbNotFoundErrCodes...)
+}
@@ -0,0 +1,79 @@
+package awspresence
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestIsAWSErrCode(t *testing.T) {
+	notFound := awserr.New("RuleNotFound", "One or more rules not found", nil)
+
+	cases := []struct {
+		Name     string
+		Err      error
+		Codes    []string
+		Expected bool
+	}{
+		{"nil", nil, []string{"RuleNotFound"}, false},
+		{"not an AWS error", errors.New("RuleNotFound"), []string{"RuleNotFound"}, false},
+		{"matching code", notFound, []string{"RuleNotFound"}, true},
+		{"other code", notFound, []string{"ListenerNotFound"}, false},
+		{"one of codes", notFound, []string{"ListenerNotFound", "RuleNotFound"}, true},
+		{"no codes", notFound, nil, false},
+		{"wrapped", fmt.Errorf("Error deleting rule: %w", notFound), []string{"RuleNotFound"}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := isAWSErrCode(tc.Err, tc.Codes...); got != tc.Expected {
+				t.Errorf("expected %t, got %t", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestIsAWSErrMessage(t *testing.T) {
+	err := awserr.New("ValidationError", "Listener protocol 'HTTP' is not supported", nil)
+
+	cases := []struct {
+		Name     string
+		Err      error
+		Message  string
+		Expected bool
+	}{
+		{"exact case", err, "is not supported", true},
+		{"other case", err, "listener PROTOCOL", true},
+		{"wrapped", fmt.Errorf("Error creating listener: %w", err), "not supported", true},
+		{"other message", err, "not found", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := isAWSErrMessage(tc.Err, tc.Message, "ValidationError"); got != tc.Expected {
+				t.Errorf("expected %t, got %t", tc.Expected, got)
+			}
+		})
+	}
+
+	if isAWSErrMessage(err, "not supported", "InvalidConfigurationRequest") {
+		t.Error("expected no match for a different code")
+	}
+}
+
+func TestIsLbNotFoundErr(t *testing.T) {
+	for _, code := range []string{"RuleNotFound", "ListenerNotFound", "LoadBalancerNotFound", "TargetGroupNotFound", "PolicyNotFound"} {
+		if !isLbNotFoundErr(awserr.New(code, "not found", nil)) {
+			t.Errorf("expected %s to be a not found error", code)
+		}
+	}
+
+	if isLbNotFoundErr(awserr.New("ResourceInUse", "Target group is currently in use by a listener or a rule", nil)) {
+		t.Error("expected ResourceInUse not to be a not found error")
+	}
+	if isLbNotFoundErr(nil) {
+		t.Error("expected nil not to be a not found error")
+	}
+}
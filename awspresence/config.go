@@ -3,11 +3,18 @@ package awspresence
 import (
 	"fmt"
 	"log"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/wafregional"
 	awsbase "github.com/hashicorp/aws-sdk-go-base"
 	"github.com/hashicorp/terraform/helper/logging"
 	"github.com/hashicorp/terraform/terraform"
@@ -21,6 +28,10 @@ type Config struct {
 	Token         string
 	Region        string
 	MaxRetries    int
+	RetryMode     string
+
+	ApiCallSummary bool
+	MetricsFile    string
 
 	AssumeRoleARN         string
 	AssumeRoleExternalID  string
@@ -39,20 +50,41 @@ type Config struct {
 	SkipRequestingAccountId bool
 	SkipMetadataApiCheck    bool
 	S3ForcePathStyle        bool
+
+	LocalstackEndpoint string
+
+	UserAgentModule    string
+	UserAgentWorkspace string
 }
 
 type AWSClient struct {
 	accountid          string
+	acmconn            *acm.ACM
+	cloudtrailconn     *cloudtrail.CloudTrail
 	ec2conn            *ec2.EC2
 	elbconn            *elb.ELB
 	elbv2conn          *elbv2.ELBV2
+	iamconn            *iam.IAM
+	secretsmanagerconn *secretsmanager.SecretsManager
+	wafregionalconn    *wafregional.WAFRegional
 	partition          string
 	region             string
 	supportedplatforms []string
+
+	session    *session.Session
+	endpoints  map[string]string
+	localstack bool
+
+	regionalClientsMu sync.Mutex
+	regionalClients   map[string]*AWSClient
 }
 
 // Client configures and returns a fully initialized AWSClient
 func (c *Config) Client() (interface{}, error) {
+	if c.LocalstackEndpoint != "" {
+		c.applyLocalstack(c.LocalstackEndpoint)
+	}
+
 	// Get the auth and region. This can fail if keys/regions were not
 	// specified and we're attempting to use the environment.
 	if !c.SkipRegionValidation {
@@ -87,8 +119,9 @@ func (c *Config) Client() (interface{}, error) {
 			{Name: "Terraform", Version: terraform.VersionString()},
 		},
 	}
+	awsbaseConfig.UserAgentProducts = append(awsbaseConfig.UserAgentProducts, userAgentModuleProducts(c.UserAgentModule, c.UserAgentWorkspace)...)
 
-	sess, accountID, _, err := awsbase.GetSessionWithAccountIDAndPartition(awsbaseConfig)
+	sess, accountID, partition, err := awsbase.GetSessionWithAccountIDAndPartition(awsbaseConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -101,15 +134,73 @@ func (c *Config) Client() (interface{}, error) {
 		return nil, err
 	}
 
-	client := &AWSClient{
-		ec2conn:   ec2.New(sess.Copy(&aws.Config{Endpoint: aws.String(c.Endpoints["ec2"])})),
-		elbconn:   elb.New(sess.Copy(&aws.Config{Endpoint: aws.String(c.Endpoints["elb"])})),
-		elbv2conn: elbv2.New(sess.Copy(&aws.Config{Endpoint: aws.String(c.Endpoints["elb"])})),
+	if c.RetryMode == retryModeAdaptive {
+		log.Printf("[INFO] Using %s retry mode", c.RetryMode)
+		addAdaptiveRetryHandlers(&sess.Handlers)
+	}
+
+	if c.ApiCallSummary {
+		enableApiCallSummary()
 	}
+	// The metrics file counts the retries of the ELB API calls.
+	if c.ApiCallSummary || c.MetricsFile != "" {
+		addApiCallSummaryHandlers(&sess.Handlers)
+	}
+
+	client := newAWSClient(sess, c.Endpoints)
+	client.accountid = accountID
+	client.partition = partition
+	client.region = c.Region
+	client.localstack = c.LocalstackEndpoint != ""
 
 	return client, nil
 }
 
+func newAWSClient(sess *session.Session, endpoints map[string]string) *AWSClient {
+	return &AWSClient{
+		acmconn:            acm.New(sess.Copy(&aws.Config{Endpoint: aws.String(endpoints["acm"])})),
+		cloudtrailconn:     cloudtrail.New(sess.Copy(&aws.Config{Endpoint: aws.String(endpoints["cloudtrail"])})),
+		ec2conn:            ec2.New(sess.Copy(&aws.Config{Endpoint: aws.String(endpoints["ec2"])})),
+		elbconn:            elb.New(sess.Copy(&aws.Config{Endpoint: aws.String(endpoints["elb"])})),
+		elbv2conn:          elbv2.New(sess.Copy(&aws.Config{Endpoint: aws.String(endpoints["elb"])})),
+		iamconn:            iam.New(sess.Copy(&aws.Config{Endpoint: aws.String(endpoints["iam"])})),
+		secretsmanagerconn: secretsmanager.New(sess.Copy(&aws.Config{Endpoint: aws.String(endpoints["secretsmanager"])})),
+		wafregionalconn:    wafregional.New(sess.Copy(&aws.Config{Endpoint: aws.String(endpoints["wafregional"])})),
+		session:            sess,
+		endpoints:          endpoints,
+	}
+}
+
+// regionalClient returns a client for region, building it from the provider
+// session on first use and caching it for the rest of the run. The provider's
+// own client is returned when region is empty or matches the provider region.
+func (c *AWSClient) regionalClient(region string) *AWSClient {
+	if region == "" || region == c.region || c.session == nil {
+		return c
+	}
+
+	c.regionalClientsMu.Lock()
+	defer c.regionalClientsMu.Unlock()
+
+	if client, ok := c.regionalClients[region]; ok {
+		return client
+	}
+
+	log.Printf("[DEBUG] Building AWS client for region %s", region)
+	client := newAWSClient(c.session.Copy(&aws.Config{Region: aws.String(region)}), c.endpoints)
+	client.accountid = c.accountid
+	client.partition = c.partition
+	client.region = region
+	client.localstack = c.localstack
+
+	if c.regionalClients == nil {
+		c.regionalClients = make(map[string]*AWSClient)
+	}
+	c.regionalClients[region] = client
+
+	return client
+}
+
 func hasEc2Classic(platforms []string) bool {
 	for _, p := range platforms {
 		if p == "EC2" {
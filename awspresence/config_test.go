@@ -4,6 +4,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	awsbase "github.com/hashicorp/aws-sdk-go-base"
 )
@@ -40,6 +43,40 @@ func TestGetSupportedEC2Platforms(t *testing.T) {
 	}
 }
 
+func TestAWSClientRegionalClient(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Region:      aws.String("us-west-2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newAWSClient(sess, map[string]string{})
+	client.region = "us-west-2"
+
+	if got := client.regionalClient(""); got != client {
+		t.Fatalf("Expected provider client for empty region")
+	}
+	if got := client.regionalClient("us-west-2"); got != client {
+		t.Fatalf("Expected provider client for provider region")
+	}
+
+	east := client.regionalClient("us-east-1")
+	if east == client {
+		t.Fatalf("Expected a separate client for us-east-1")
+	}
+	if east.region != "us-east-1" {
+		t.Fatalf("Expected region us-east-1, received: %s", east.region)
+	}
+	if region := aws.StringValue(east.elbv2conn.Config.Region); region != "us-east-1" {
+		t.Fatalf("Expected elbv2 client in us-east-1, received: %s", region)
+	}
+	if got := client.regionalClient("us-east-1"); got != east {
+		t.Fatalf("Expected cached client to be reused")
+	}
+}
+
 var test_ec2_describeAccountAttributes_response = `<DescribeAccountAttributesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
   <requestId>7a62c49f-347e-4fc4-9331-6e8eEXAMPLE</requestId>
   <accountAttributeSet>
@@ -0,0 +1,177 @@
+package awspresence
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const ipRangesDefaultUrl = "https://ip-ranges.amazonaws.com/ip-ranges.json"
+
+type dataSourceAwsIPRangesResult struct {
+	CreateDate   string
+	IPv6Prefixes []dataSourceAwsIPRangesIPv6Prefix `json:"ipv6_prefixes"`
+	Prefixes     []dataSourceAwsIPRangesPrefix
+	SyncToken    string
+}
+
+type dataSourceAwsIPRangesPrefix struct {
+	IpPrefix string `json:"ip_prefix"`
+	Region   string
+	Service  string
+}
+
+type dataSourceAwsIPRangesIPv6Prefix struct {
+	IpPrefix string `json:"ipv6_prefix"`
+	Region   string
+	Service  string
+}
+
+func dataSourceAwsIPRanges() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAwsIPRangesRead,
+
+		Schema: map[string]*schema.Schema{
+			"cidr_blocks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ipv6_cidr_blocks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"create_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"regions": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+			},
+			"services": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"sync_token": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  ipRangesDefaultUrl,
+			},
+		},
+	}
+}
+
+func dataSourceAwsIPRangesRead(d *schema.ResourceData, meta interface{}) error {
+	conn := cleanhttp.DefaultClient()
+	url := d.Get("url").(string)
+
+	log.Printf("[DEBUG] Reading IP ranges from %s", url)
+
+	res, err := conn.Get(url)
+	if err != nil {
+		return fmt.Errorf("Error listing IP ranges from %s: %s", url, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return fmt.Errorf("Error listing IP ranges from %s: unexpected status %s", url, res.Status)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("Error reading response body from %s: %s", url, err)
+	}
+
+	result := new(dataSourceAwsIPRangesResult)
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("Error parsing result from %s: %s", url, err)
+	}
+
+	syncToken, err := strconv.Atoi(result.SyncToken)
+	if err != nil {
+		return fmt.Errorf("Error while converting sync token: %s", err)
+	}
+
+	d.SetId(result.SyncToken)
+	d.Set("create_date", result.CreateDate)
+	d.Set("sync_token", syncToken)
+
+	regions := expandIpRangesFilter(d.Get("regions").(*schema.Set))
+	services := expandIpRangesFilter(d.Get("services").(*schema.Set))
+
+	ipPrefixes, ipv6Prefixes := filterIpRanges(result, regions, services)
+
+	if len(ipPrefixes) == 0 && len(ipv6Prefixes) == 0 {
+		return fmt.Errorf("No IP ranges result from filters")
+	}
+
+	if err := d.Set("cidr_blocks", ipPrefixes); err != nil {
+		return fmt.Errorf("Error setting cidr_blocks: %s", err)
+	}
+	if err := d.Set("ipv6_cidr_blocks", ipv6Prefixes); err != nil {
+		return fmt.Errorf("Error setting ipv6_cidr_blocks: %s", err)
+	}
+
+	return nil
+}
+
+// filterIpRanges returns the sorted IPv4 and IPv6 prefixes of result in one
+// of regions, or any region when regions is empty, and of one of services.
+// Regions and services are matched case-insensitively.
+func filterIpRanges(result *dataSourceAwsIPRangesResult, regions, services []string) ([]string, []string) {
+	matches := func(region, service string) bool {
+		return (len(regions) == 0 || stringInSlice(strings.ToLower(region), regions)) &&
+			stringInSlice(strings.ToLower(service), services)
+	}
+
+	ipPrefixes := []string{}
+	for _, e := range result.Prefixes {
+		if matches(e.Region, e.Service) {
+			ipPrefixes = append(ipPrefixes, e.IpPrefix)
+		}
+	}
+
+	ipv6Prefixes := []string{}
+	for _, e := range result.IPv6Prefixes {
+		if matches(e.Region, e.Service) {
+			ipv6Prefixes = append(ipv6Prefixes, e.IpPrefix)
+		}
+	}
+
+	sort.Strings(ipPrefixes)
+	sort.Strings(ipv6Prefixes)
+
+	return ipPrefixes, ipv6Prefixes
+}
+
+func expandIpRangesFilter(set *schema.Set) []string {
+	var values []string
+	for _, v := range set.List() {
+		values = append(values, strings.ToLower(v.(string)))
+	}
+	return values
+}
+
+func stringInSlice(v string, values []string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
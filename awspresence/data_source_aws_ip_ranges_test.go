@@ -0,0 +1,71 @@
+package awspresence
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestFilterIpRanges(t *testing.T) {
+	result := &dataSourceAwsIPRangesResult{
+		Prefixes: []dataSourceAwsIPRangesPrefix{
+			{IpPrefix: "52.95.245.0/24", Region: "us-east-1", Service: "AMAZON"},
+			{IpPrefix: "13.32.0.0/15", Region: "GLOBAL", Service: "CLOUDFRONT"},
+			{IpPrefix: "130.176.0.0/18", Region: "GLOBAL", Service: "CLOUDFRONT_ORIGIN_FACING"},
+			{IpPrefix: "120.52.22.96/27", Region: "GLOBAL", Service: "CLOUDFRONT_ORIGIN_FACING"},
+			{IpPrefix: "3.5.140.0/22", Region: "ap-northeast-2", Service: "EC2"},
+		},
+		IPv6Prefixes: []dataSourceAwsIPRangesIPv6Prefix{
+			{IpPrefix: "2600:9000:ddd::/48", Region: "GLOBAL", Service: "CLOUDFRONT_ORIGIN_FACING"},
+			{IpPrefix: "2600:1f14::/35", Region: "us-west-2", Service: "EC2"},
+		},
+	}
+
+	ipPrefixes, ipv6Prefixes := filterIpRanges(result, nil, []string{"cloudfront_origin_facing"})
+	if expected := []string{"120.52.22.96/27", "130.176.0.0/18"}; !reflect.DeepEqual(ipPrefixes, expected) {
+		t.Fatalf("expected %q, got %q", expected, ipPrefixes)
+	}
+	if expected := []string{"2600:9000:ddd::/48"}; !reflect.DeepEqual(ipv6Prefixes, expected) {
+		t.Fatalf("expected %q, got %q", expected, ipv6Prefixes)
+	}
+
+	ipPrefixes, ipv6Prefixes = filterIpRanges(result, []string{"us-west-2"}, []string{"ec2", "amazon"})
+	if expected := []string{}; !reflect.DeepEqual(ipPrefixes, expected) {
+		t.Fatalf("expected %q, got %q", expected, ipPrefixes)
+	}
+	if expected := []string{"2600:1f14::/35"}; !reflect.DeepEqual(ipv6Prefixes, expected) {
+		t.Fatalf("expected %q, got %q", expected, ipv6Prefixes)
+	}
+}
+
+func TestAccAWSIPRanges_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSIPRangesConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("data.aws_ip_ranges.cloudfront", "cidr_blocks.#", regexp.MustCompile(`^[1-9][0-9]*$`)),
+					resource.TestMatchResourceAttr("data.aws_ip_ranges.cloudfront", "create_date", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2}$`)),
+					resource.TestMatchResourceAttr("data.aws_ip_ranges.cloudfront", "sync_token", regexp.MustCompile(`^\d+$`)),
+					resource.TestMatchResourceAttr("data.aws_ip_ranges.ec2", "cidr_blocks.#", regexp.MustCompile(`^[1-9][0-9]*$`)),
+					resource.TestMatchResourceAttr("data.aws_ip_ranges.ec2", "ipv6_cidr_blocks.#", regexp.MustCompile(`^[1-9][0-9]*$`)),
+				),
+			},
+		},
+	})
+}
+
+const testAccAWSIPRangesConfig = `
+data "aws_ip_ranges" "cloudfront" {
+  services = ["cloudfront_origin_facing"]
+}
+
+data "aws_ip_ranges" "ec2" {
+  regions  = ["eu-west-1", "eu-central-1"]
+  services = ["ec2"]
+}
+`
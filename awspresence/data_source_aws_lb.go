@@ -71,6 +71,39 @@ func dataSourceAwsLb() *schema.Resource {
 				},
 			},
 
+			"availability_zone_addresses": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"availability_zone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"subnet_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dns_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"network_interface_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"private_ipv4_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"public_ipv4_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
 			"access_logs": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -98,6 +131,31 @@ func dataSourceAwsLb() *schema.Resource {
 				Computed: true,
 			},
 
+			"enable_zonal_shift": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"preserve_host_header": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"enable_xff_client_port": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"enable_tls_version_and_cipher_suite_headers": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"xff_header_processing_mode": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
 			"idle_timeout": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -119,6 +177,11 @@ func dataSourceAwsLb() *schema.Resource {
 			},
 
 			"tags": tagsSchemaComputed(),
+
+			"region": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -0,0 +1,357 @@
+package awspresence
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceAwsLbDependencyGraph() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAwsLbDependencyGraphRead,
+		Schema: map[string]*schema.Schema{
+			"load_balancer_arn": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"graph": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"duplicate_rules": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rule_arn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"duplicate_of_rule_arn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"shadowed_rules": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rule_arn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"shadowed_by_rule_arn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAwsLbDependencyGraphRead(d *schema.ResourceData, meta interface{}) error {
+	elbconn := meta.(*AWSClient).elbv2conn
+	lbArn := d.Get("load_balancer_arn").(string)
+
+	graph, err := collectLbDependencyGraph(elbconn, lbArn)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(graph)
+	if err != nil {
+		return fmt.Errorf("Error encoding dependency graph of LB %q: %s", lbArn, err)
+	}
+
+	d.SetId(lbArn)
+	d.Set("graph", string(data))
+	if err := d.Set("duplicate_rules", lbDependencyGraphDuplicateRules(graph)); err != nil {
+		return fmt.Errorf("error setting duplicate_rules: %s", err)
+	}
+	if err := d.Set("shadowed_rules", lbDependencyGraphShadowedRules(graph)); err != nil {
+		return fmt.Errorf("error setting shadowed_rules: %s", err)
+	}
+
+	return nil
+}
+
+// lbDependencyGraph is the listeners, rules and target groups of a load
+// balancer, collected in a single pass and shared by the analyses below.
+type lbDependencyGraph struct {
+	LoadBalancerArn string                          `json:"load_balancer_arn"`
+	Listeners       []*lbDependencyGraphListener    `json:"listeners"`
+	TargetGroups    []*lbDependencyGraphTargetGroup `json:"target_groups"`
+}
+
+type lbDependencyGraphListener struct {
+	ListenerArn            string                   `json:"listener_arn"`
+	Port                   int64                    `json:"port"`
+	Protocol               string                   `json:"protocol"`
+	DefaultTargetGroupArns []string                 `json:"default_target_group_arns"`
+	Rules                  []*lbDependencyGraphRule `json:"rules"`
+}
+
+type lbDependencyGraphRule struct {
+	RuleArn              string   `json:"rule_arn"`
+	Priority             string   `json:"priority"`
+	ConditionFingerprint string   `json:"condition_fingerprint"`
+	TargetGroupArns      []string `json:"target_group_arns"`
+
+	conditions []*elbv2.RuleCondition
+}
+
+type lbDependencyGraphTargetGroup struct {
+	TargetGroupArn string `json:"target_group_arn"`
+	Targets        int    `json:"targets"`
+	HealthyTargets int    `json:"healthy_targets"`
+}
+
+// collectLbDependencyGraph describes the listeners of the load balancer, their
+// rules, and the health of the targets of every target group they forward to.
+func collectLbDependencyGraph(conn *elbv2.ELBV2, lbArn string) (*lbDependencyGraph, error) {
+	graph := &lbDependencyGraph{
+		LoadBalancerArn: lbArn,
+		Listeners:       []*lbDependencyGraphListener{},
+		TargetGroups:    []*lbDependencyGraphTargetGroup{},
+	}
+	targetGroupArns := make(map[string]bool)
+
+	var listeners []*elbv2.Listener
+	err := paginate("DescribeListeners", func(marker *string) (*string, error) {
+		out, err := conn.DescribeListeners(&elbv2.DescribeListenersInput{
+			LoadBalancerArn: aws.String(lbArn),
+			Marker:          marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, out.Listeners...)
+		return out.NextMarker, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving listeners for LB %q: %s", lbArn, err)
+	}
+	sort.Slice(listeners, func(i, j int) bool {
+		return aws.Int64Value(listeners[i].Port) < aws.Int64Value(listeners[j].Port)
+	})
+
+	for _, listener := range listeners {
+		listenerArn := aws.StringValue(listener.ListenerArn)
+		node := &lbDependencyGraphListener{
+			ListenerArn:            listenerArn,
+			Port:                   aws.Int64Value(listener.Port),
+			Protocol:               aws.StringValue(listener.Protocol),
+			DefaultTargetGroupArns: lbDependencyGraphActionTargetGroupArns(listener.DefaultActions),
+			Rules:                  []*lbDependencyGraphRule{},
+		}
+
+		rules, err := describeLbListenerRules(conn, listenerArn)
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving rules for listener %q: %s", listenerArn, err)
+		}
+		sortLbListenerRulesByPriority(rules)
+
+		for _, rule := range rules {
+			node.Rules = append(node.Rules, &lbDependencyGraphRule{
+				RuleArn:              aws.StringValue(rule.RuleArn),
+				Priority:             aws.StringValue(rule.Priority),
+				ConditionFingerprint: lbListenerRuleConditionFingerprint(rule.Conditions),
+				TargetGroupArns:      lbDependencyGraphActionTargetGroupArns(rule.Actions),
+				conditions:           rule.Conditions,
+			})
+		}
+
+		for _, arn := range node.DefaultTargetGroupArns {
+			targetGroupArns[arn] = true
+		}
+		for _, rule := range node.Rules {
+			for _, arn := range rule.TargetGroupArns {
+				targetGroupArns[arn] = true
+			}
+		}
+
+		graph.Listeners = append(graph.Listeners, node)
+	}
+
+	for arn := range targetGroupArns {
+		resp, err := conn.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(arn),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error reading Target Health of %q: %s", arn, err)
+		}
+
+		node := &lbDependencyGraphTargetGroup{
+			TargetGroupArn: arn,
+			Targets:        len(resp.TargetHealthDescriptions),
+		}
+		for _, description := range resp.TargetHealthDescriptions {
+			if description.TargetHealth != nil && aws.StringValue(description.TargetHealth.State) == elbv2.TargetHealthStateEnumHealthy {
+				node.HealthyTargets++
+			}
+		}
+		graph.TargetGroups = append(graph.TargetGroups, node)
+	}
+	sort.Slice(graph.TargetGroups, func(i, j int) bool {
+		return graph.TargetGroups[i].TargetGroupArn < graph.TargetGroups[j].TargetGroupArn
+	})
+
+	return graph, nil
+}
+
+func lbDependencyGraphActionTargetGroupArns(actions []*elbv2.Action) []string {
+	arns := []string{}
+	for _, action := range actions {
+		if arn := aws.StringValue(action.TargetGroupArn); arn != "" {
+			arns = append(arns, arn)
+		}
+	}
+	return arns
+}
+
+// lbDependencyGraphDuplicateRules returns the rules with the same conditions
+// as a rule of higher priority on the same listener, which never match.
+func lbDependencyGraphDuplicateRules(graph *lbDependencyGraph) []interface{} {
+	duplicates := []interface{}{}
+	for _, listener := range graph.Listeners {
+		first := make(map[string]string)
+		for _, rule := range listener.Rules {
+			if arn, ok := first[rule.ConditionFingerprint]; ok {
+				duplicates = append(duplicates, map[string]interface{}{
+					"rule_arn":              rule.RuleArn,
+					"duplicate_of_rule_arn": arn,
+				})
+				continue
+			}
+			first[rule.ConditionFingerprint] = rule.RuleArn
+		}
+	}
+	return duplicates
+}
+
+// lbDependencyGraphShadowedRules returns the rules that only match requests a
+// rule of higher priority on the same listener matches too, so they never
+// match. Duplicates are left to lbDependencyGraphDuplicateRules.
+func lbDependencyGraphShadowedRules(graph *lbDependencyGraph) []interface{} {
+	shadowed := []interface{}{}
+	for _, listener := range graph.Listeners {
+		for i, rule := range listener.Rules {
+			for _, earlier := range listener.Rules[:i] {
+				if earlier.ConditionFingerprint == rule.ConditionFingerprint {
+					break
+				}
+				if lbListenerRuleConditionsCover(earlier.conditions, rule.conditions) {
+					shadowed = append(shadowed, map[string]interface{}{
+						"rule_arn":             rule.RuleArn,
+						"shadowed_by_rule_arn": earlier.RuleArn,
+					})
+					break
+				}
+			}
+		}
+	}
+	return shadowed
+}
+
+// lbListenerRuleConditionsCover reports whether every request matching the
+// conditions b also matches the conditions a. The check is conservative: it
+// can miss a cover, but never reports one that does not hold.
+func lbListenerRuleConditionsCover(a, b []*elbv2.RuleCondition) bool {
+	bValues := make(map[string][]string)
+	for _, condition := range b {
+		key, values := lbListenerRuleConditionKeyValues(condition)
+		bValues[key] = values
+	}
+
+	for _, condition := range a {
+		key, values := lbListenerRuleConditionKeyValues(condition)
+		narrower, ok := bValues[key]
+		if !ok {
+			return false
+		}
+
+		caseInsensitive := aws.StringValue(condition.Field) == "host-header"
+		for _, v := range narrower {
+			if !lbListenerRuleConditionValueCovered(values, v, caseInsensitive) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// lbListenerRuleConditionValueCovered reports whether the requests matching
+// value match one of patterns, where "*" matches any characters and "?" any
+// single character.
+func lbListenerRuleConditionValueCovered(patterns []string, value string, caseInsensitive bool) bool {
+	if caseInsensitive {
+		value = strings.ToLower(value)
+	}
+
+	for _, pattern := range patterns {
+		if caseInsensitive {
+			pattern = strings.ToLower(pattern)
+		}
+
+		if pattern == value {
+			return true
+		}
+
+		if !strings.ContainsAny(value, "*?") {
+			if lbListenerRuleWildcardMatch(pattern, value) {
+				return true
+			}
+			continue
+		}
+
+		// A pattern with a single trailing "*" covers any pattern that
+		// starts with its prefix.
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern && !strings.ContainsAny(prefix, "*?") && strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lbListenerRuleWildcardMatch reports whether s matches pattern, where "*"
+// matches any characters, including none, and "?" exactly one character.
+func lbListenerRuleWildcardMatch(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	star, match := -1, 0
+	i, j := 0, 0
+	for j < len(str) {
+		switch {
+		case i < len(p) && (p[i] == '?' || p[i] == str[j]):
+			i++
+			j++
+		case i < len(p) && p[i] == '*':
+			star, match = i, j
+			i++
+		case star != -1:
+			i = star + 1
+			match++
+			j = match
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+	return i == len(p)
+}
@@ -0,0 +1,180 @@
+package awspresence
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestLbListenerRuleWildcardMatch(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		s        string
+		expected bool
+	}{
+		{"/static/*", "/static/app.js", true},
+		{"/static/*", "/static/", true},
+		{"/static/*", "/api/", false},
+		{"*.example.com", "www.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"/v?/users", "/v1/users", true},
+		{"/v?/users", "/v10/users", false},
+		{"*", "", true},
+	}
+
+	for _, tc := range cases {
+		if actual := lbListenerRuleWildcardMatch(tc.pattern, tc.s); actual != tc.expected {
+			t.Errorf("%q against %q: expected %t, got %t", tc.s, tc.pattern, tc.expected, actual)
+		}
+	}
+}
+
+func TestLbListenerRuleConditionsCover(t *testing.T) {
+	path := func(values ...string) *elbv2.RuleCondition {
+		return &elbv2.RuleCondition{
+			Field:             aws.String("path-pattern"),
+			PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice(values)},
+		}
+	}
+	host := func(values ...string) *elbv2.RuleCondition {
+		return &elbv2.RuleCondition{
+			Field:            aws.String("host-header"),
+			HostHeaderConfig: &elbv2.HostHeaderConditionConfig{Values: aws.StringSlice(values)},
+		}
+	}
+
+	cases := []struct {
+		name     string
+		a        []*elbv2.RuleCondition
+		b        []*elbv2.RuleCondition
+		expected bool
+	}{
+		{"same", []*elbv2.RuleCondition{path("/api/*")}, []*elbv2.RuleCondition{path("/api/*")}, true},
+		{"narrower pattern", []*elbv2.RuleCondition{path("/api/*")}, []*elbv2.RuleCondition{path("/api/v1/*")}, true},
+		{"literal", []*elbv2.RuleCondition{path("/api/*")}, []*elbv2.RuleCondition{path("/api/health")}, true},
+		{"all values", []*elbv2.RuleCondition{path("/api/*")}, []*elbv2.RuleCondition{path("/api/v1/*", "/static/*")}, false},
+		{"extra condition", []*elbv2.RuleCondition{path("/api/*")}, []*elbv2.RuleCondition{path("/api/*"), host("example.com")}, true},
+		{"missing condition", []*elbv2.RuleCondition{path("/api/*"), host("example.com")}, []*elbv2.RuleCondition{path("/api/*")}, false},
+		{"host case", []*elbv2.RuleCondition{host("*.Example.com")}, []*elbv2.RuleCondition{host("www.example.COM")}, true},
+		{"question mark", []*elbv2.RuleCondition{path("/a?")}, []*elbv2.RuleCondition{path("/a*")}, false},
+	}
+
+	for _, tc := range cases {
+		if actual := lbListenerRuleConditionsCover(tc.a, tc.b); actual != tc.expected {
+			t.Errorf("%s: expected %t, got %t", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestLbDependencyGraphAnalyses(t *testing.T) {
+	rule := func(arn string, values ...string) *lbDependencyGraphRule {
+		conditions := []*elbv2.RuleCondition{{
+			Field:             aws.String("path-pattern"),
+			PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice(values)},
+		}}
+		return &lbDependencyGraphRule{
+			RuleArn:              arn,
+			ConditionFingerprint: lbListenerRuleConditionFingerprint(conditions),
+			conditions:           conditions,
+		}
+	}
+
+	graph := &lbDependencyGraph{
+		Listeners: []*lbDependencyGraphListener{
+			{
+				Rules: []*lbDependencyGraphRule{
+					rule("rule-1", "/api/*"),
+					rule("rule-2", "/static/*"),
+					rule("rule-3", "/api/v1/*"),
+					rule("rule-4", "/static/*"),
+					rule("rule-5", "/health"),
+				},
+			},
+			{
+				Rules: []*lbDependencyGraphRule{
+					rule("rule-6", "/api/*"),
+				},
+			},
+		},
+	}
+
+	duplicates := lbDependencyGraphDuplicateRules(graph)
+	expectedDuplicates := []interface{}{
+		map[string]interface{}{"rule_arn": "rule-4", "duplicate_of_rule_arn": "rule-2"},
+	}
+	if !reflect.DeepEqual(duplicates, expectedDuplicates) {
+		t.Fatalf("expected duplicates %v, got %v", expectedDuplicates, duplicates)
+	}
+
+	shadowed := lbDependencyGraphShadowedRules(graph)
+	expectedShadowed := []interface{}{
+		map[string]interface{}{"rule_arn": "rule-3", "shadowed_by_rule_arn": "rule-1"},
+	}
+	if !reflect.DeepEqual(shadowed, expectedShadowed) {
+		t.Fatalf("expected shadowed rules %v, got %v", expectedShadowed, shadowed)
+	}
+}
+
+func TestAccDataSourceAWSLBDependencyGraph_basic(t *testing.T) {
+	lbName := fmt.Sprintf("testrule-graph-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	dataSourceName := "data.aws_lb_dependency_graph.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAWSLBDependencyGraphConfig(lbName, targetGroupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBDependencyGraph(dataSourceName),
+					resource.TestCheckResourceAttr(dataSourceName, "duplicate_rules.#", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "shadowed_rules.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSLBDependencyGraph(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		var graph lbDependencyGraph
+		if err := json.Unmarshal([]byte(rs.Primary.Attributes["graph"]), &graph); err != nil {
+			return fmt.Errorf("Error decoding graph: %s", err)
+		}
+
+		if len(graph.Listeners) != 1 {
+			return fmt.Errorf("expected 1 listener, got %d", len(graph.Listeners))
+		}
+		if len(graph.Listeners[0].Rules) != 1 {
+			return fmt.Errorf("expected 1 rule, got %d", len(graph.Listeners[0].Rules))
+		}
+		if len(graph.TargetGroups) != 1 {
+			return fmt.Errorf("expected 1 target group, got %d", len(graph.TargetGroups))
+		}
+
+		return nil
+	}
+}
+
+func testAccDataSourceAWSLBDependencyGraphConfig(lbName, targetGroupName string) string {
+	return testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName) + `
+data "aws_lb_dependency_graph" "test" {
+  load_balancer_arn = "${aws_lb.alb_test.arn}"
+
+  depends_on = ["aws_lb_listener_rule.static"]
+}
+`
+}
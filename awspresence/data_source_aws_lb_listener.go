@@ -214,6 +214,11 @@ func dataSourceAwsLbListener() *schema.Resource {
 					},
 				},
 			},
+
+			"region": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
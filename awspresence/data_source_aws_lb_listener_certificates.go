@@ -0,0 +1,212 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceAwsLbListenerCertificates() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAwsLbListenerCertificatesRead,
+		Schema: map[string]*schema.Schema{
+			"listener_arn": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"domain_name": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				StateFunc: acmCertificateDomainNameStateFunc,
+			},
+
+			"certificate_arns": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"certificates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"certificate_arn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_default": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"domain_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"subject_alternative_names": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"not_after": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAwsLbListenerCertificatesRead(d *schema.ResourceData, meta interface{}) error {
+	elbconn := meta.(*AWSClient).elbv2conn
+	acmconn := meta.(*AWSClient).acmconn
+	listenerArn := d.Get("listener_arn").(string)
+	domainName := acmCertificateDomainNameStateFunc(d.Get("domain_name"))
+
+	var listenerCertificates []*elbv2.Certificate
+	err := paginate("DescribeListenerCertificates", func(marker *string) (*string, error) {
+		resp, err := elbconn.DescribeListenerCertificates(&elbv2.DescribeListenerCertificatesInput{
+			ListenerArn: aws.String(listenerArn),
+			Marker:      marker,
+			PageSize:    aws.Int64(400),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		listenerCertificates = append(listenerCertificates, resp.Certificates...)
+
+		return resp.NextMarker, nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error retrieving certificates for listener %q: %s", listenerArn, err)
+	}
+
+	var certificateArns []string
+	var certificates []map[string]interface{}
+	for _, listenerCertificate := range dedupeLbListenerCertificates(listenerCertificates) {
+		certificateArn := aws.StringValue(listenerCertificate.CertificateArn)
+
+		certificate := map[string]interface{}{
+			"certificate_arn": certificateArn,
+			"is_default":      aws.BoolValue(listenerCertificate.IsDefault),
+		}
+
+		detail, err := describeLbListenerAcmCertificate(acmconn, certificateArn)
+		if err != nil {
+			return fmt.Errorf("Error describing certificate %q of listener %q: %s", certificateArn, listenerArn, err)
+		}
+
+		if detail != nil {
+			certificate["domain_name"] = aws.StringValue(detail.DomainName)
+			certificate["subject_alternative_names"] = aws.StringValueSlice(detail.SubjectAlternativeNames)
+			certificate["status"] = aws.StringValue(detail.Status)
+			certificate["type"] = aws.StringValue(detail.Type)
+			if detail.NotAfter != nil {
+				certificate["not_after"] = aws.TimeValue(detail.NotAfter).Format(time.RFC3339)
+			}
+		}
+
+		if domainName != "" && (detail == nil || !acmCertificateCoversDomain(detail, domainName)) {
+			continue
+		}
+
+		certificateArns = append(certificateArns, certificateArn)
+		certificates = append(certificates, certificate)
+	}
+
+	d.SetId(listenerArn)
+	if err := d.Set("certificate_arns", certificateArns); err != nil {
+		return fmt.Errorf("error setting certificate_arns: %s", err)
+	}
+	if err := d.Set("certificates", certificates); err != nil {
+		return fmt.Errorf("error setting certificates: %s", err)
+	}
+
+	return nil
+}
+
+// dedupeLbListenerCertificates returns each certificate of a listener once,
+// in the order first listed. The default certificate is listed twice when it
+// is also added to the certificate list of the listener, and is returned as
+// the default certificate.
+func dedupeLbListenerCertificates(certificates []*elbv2.Certificate) []*elbv2.Certificate {
+	var result []*elbv2.Certificate
+	index := make(map[string]int, len(certificates))
+	for _, certificate := range certificates {
+		certificateArn := aws.StringValue(certificate.CertificateArn)
+		if i, ok := index[certificateArn]; ok {
+			if aws.BoolValue(certificate.IsDefault) {
+				result[i] = certificate
+			}
+			continue
+		}
+		index[certificateArn] = len(result)
+		result = append(result, certificate)
+	}
+	return result
+}
+
+// describeLbListenerAcmCertificate returns the ACM details of a listener
+// certificate. IAM server certificates and ACM certificates that have been
+// deleted since they were attached return nil.
+func describeLbListenerAcmCertificate(conn *acm.ACM, certificateArn string) (*acm.CertificateDetail, error) {
+	parsed, err := arn.Parse(certificateArn)
+	if err != nil || parsed.Service != "acm" {
+		return nil, nil
+	}
+
+	resp, err := conn.DescribeCertificate(&acm.DescribeCertificateInput{
+		CertificateArn: aws.String(certificateArn),
+	})
+	if isAWSErrCode(err, acm.ErrCodeResourceNotFoundException) {
+		log.Printf("[WARN] ACM certificate (%s) not found", certificateArn)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Certificate, nil
+}
+
+// acmCertificateCoversDomain reports whether domain is the domain name or one
+// of the subject alternative names of certificate. A wildcard name covers a
+// single label, as it does during the TLS handshake.
+func acmCertificateCoversDomain(certificate *acm.CertificateDetail, domain string) bool {
+	names := append([]string{aws.StringValue(certificate.DomainName)}, aws.StringValueSlice(certificate.SubjectAlternativeNames)...)
+	domain = strings.ToLower(domain)
+
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if name == domain {
+			return true
+		}
+
+		if strings.HasPrefix(name, "*.") {
+			if i := strings.Index(domain, "."); i > 0 && domain[i+1:] == name[2:] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
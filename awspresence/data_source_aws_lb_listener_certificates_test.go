@@ -0,0 +1,96 @@
+package awspresence
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAcmCertificateCoversDomain(t *testing.T) {
+	certificate := &acm.CertificateDetail{
+		DomainName:              aws.String("example.com"),
+		SubjectAlternativeNames: aws.StringSlice([]string{"example.com", "*.Example.org"}),
+	}
+
+	cases := map[string]bool{
+		"example.com":         true,
+		"EXAMPLE.com":         true,
+		"www.example.com":     false,
+		"www.example.org":     true,
+		"example.org":         false,
+		"a.b.example.org":     false,
+		"www.example.org.com": false,
+	}
+
+	for domain, expected := range cases {
+		if actual := acmCertificateCoversDomain(certificate, domain); actual != expected {
+			t.Fatalf("%s: expected %t, got %t", domain, expected, actual)
+		}
+	}
+}
+
+func TestDedupeLbListenerCertificates(t *testing.T) {
+	certificates := []*elbv2.Certificate{
+		{CertificateArn: aws.String("arn:sni"), IsDefault: aws.Bool(false)},
+		{CertificateArn: aws.String("arn:default"), IsDefault: aws.Bool(false)},
+		{CertificateArn: aws.String("arn:default"), IsDefault: aws.Bool(true)},
+		{CertificateArn: aws.String("arn:sni"), IsDefault: aws.Bool(false)},
+	}
+
+	got := dedupeLbListenerCertificates(certificates)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 certificates, got %d", len(got))
+	}
+	if aws.StringValue(got[0].CertificateArn) != "arn:sni" || aws.BoolValue(got[0].IsDefault) {
+		t.Errorf("expected arn:sni first and not default, got %s", got[0])
+	}
+	if aws.StringValue(got[1].CertificateArn) != "arn:default" || !aws.BoolValue(got[1].IsDefault) {
+		t.Errorf("expected arn:default second and default, got %s", got[1])
+	}
+}
+
+func TestAccDataSourceAWSLBListenerCertificates_basic(t *testing.T) {
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAWSLBListenerCertificatesConfig(key, certificate),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aws_lb_listener_certificates.all", "certificates.#", "1"),
+					resource.TestCheckResourceAttrPair("data.aws_lb_listener_certificates.all", "certificates.0.certificate_arn", "aws_acm_certificate.test", "arn"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_certificates.all", "certificates.0.is_default", "true"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_certificates.all", "certificates.0.domain_name", "example.com"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_certificates.all", "certificates.0.type", acm.CertificateTypeImported),
+					resource.TestCheckResourceAttrSet("data.aws_lb_listener_certificates.all", "certificates.0.not_after"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_certificates.matching", "certificate_arns.#", "1"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_certificates.other", "certificate_arns.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAWSLBListenerCertificatesConfig(key, certificate string) string {
+	return testAccAcmCertificateConfigListener(key, certificate) + `
+data "aws_lb_listener_certificates" "all" {
+  listener_arn = "${aws_lb_listener.test.arn}"
+}
+
+data "aws_lb_listener_certificates" "matching" {
+  listener_arn = "${aws_lb_listener.test.arn}"
+  domain_name  = "example.com"
+}
+
+data "aws_lb_listener_certificates" "other" {
+  listener_arn = "${aws_lb_listener.test.arn}"
+  domain_name  = "example.org"
+}
+`
+}
@@ -0,0 +1,90 @@
+package awspresence
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceAwsLbListenerRuleExists() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAwsLbListenerRuleExistsRead,
+		Schema: map[string]*schema.Schema{
+			"listener_arn": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			// The conditions are written as in the import ID of a rule.
+			"conditions": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validate.LbListenerRuleConditionsSpec,
+			},
+
+			"exists": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"count_of_existing": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"arns": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceAwsLbListenerRuleExistsRead(d *schema.ResourceData, meta interface{}) error {
+	elbconn := meta.(*AWSClient).elbv2conn
+	listenerArn := d.Get("listener_arn").(string)
+	spec := d.Get("conditions").(string)
+
+	fingerprint, err := lbListenerRuleImportFingerprint(spec)
+	if err != nil {
+		return fmt.Errorf("Error parsing conditions %q: %s", spec, err)
+	}
+
+	rules, err := describeLbListenerRules(elbconn, listenerArn)
+	if err != nil {
+		return fmt.Errorf("Error retrieving rules for listener %q: %s", listenerArn, err)
+	}
+
+	matches, err := findLbListenerRulesByFingerprint(elbconn, rules, fingerprint)
+	if err != nil {
+		return fmt.Errorf("Error retrieving tags of rules for listener %q: %s", listenerArn, err)
+	}
+	sortLbListenerRulesByPriority(matches)
+
+	arns := make([]string, len(matches))
+	for i, rule := range matches {
+		arns[i] = aws.StringValue(rule.RuleArn)
+	}
+
+	d.SetId(listenerArn + "?" + spec)
+	d.Set("exists", len(arns) > 0)
+	d.Set("count_of_existing", len(arns))
+	if len(arns) > 0 {
+		d.Set("arn", arns[0])
+	} else {
+		d.Set("arn", "")
+	}
+	if err := d.Set("arns", arns); err != nil {
+		return fmt.Errorf("error setting arns: %s", err)
+	}
+
+	return nil
+}
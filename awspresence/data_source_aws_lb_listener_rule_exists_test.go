@@ -0,0 +1,48 @@
+package awspresence
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceAWSLBListenerRuleExists_basic(t *testing.T) {
+	lbName := fmt.Sprintf("testrule-exists-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAWSLBListenerRuleExistsConfig(lbName, targetGroupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_exists.static", "exists", "true"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_exists.static", "count_of_existing", "1"),
+					resource.TestCheckResourceAttrPair("data.aws_lb_listener_rule_exists.static", "arn", "aws_lb_listener_rule.static", "arn"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_exists.static", "arns.#", "1"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_exists.missing", "exists", "false"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_exists.missing", "count_of_existing", "0"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_exists.missing", "arn", ""),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_exists.missing", "arns.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAWSLBListenerRuleExistsConfig(lbName, targetGroupName string) string {
+	return testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName) + `
+data "aws_lb_listener_rule_exists" "static" {
+  listener_arn = "${aws_lb_listener_rule.static.listener_arn}"
+  conditions   = "path-pattern=/static/*"
+}
+
+data "aws_lb_listener_rule_exists" "missing" {
+  listener_arn = "${aws_lb_listener_rule.static.listener_arn}"
+  conditions   = "path-pattern=/missing/*"
+}
+`
+}
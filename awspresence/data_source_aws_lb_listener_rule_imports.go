@@ -0,0 +1,104 @@
+package awspresence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceAwsLbListenerRuleImports() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAwsLbListenerRuleImportsRead,
+		Schema: map[string]*schema.Schema{
+			"listener_arn": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"managed_rule_arns": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"resource_address": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "awspresence_lb_listener_rule.imported",
+			},
+
+			"unmanaged_rule_arns": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"import_blocks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceAwsLbListenerRuleImportsRead(d *schema.ResourceData, meta interface{}) error {
+	elbconn := meta.(*AWSClient).elbv2conn
+	listenerArn := d.Get("listener_arn").(string)
+
+	rules, err := describeLbListenerRules(elbconn, listenerArn)
+	if err != nil {
+		return fmt.Errorf("Error retrieving rules for listener %q: %s", listenerArn, err)
+	}
+
+	managed := make(map[string]bool)
+	for _, arn := range d.Get("managed_rule_arns").(*schema.Set).List() {
+		managed[arn.(string)] = true
+	}
+
+	var unmanaged []*elbv2.Rule
+	for _, rule := range rules {
+		if !managed[aws.StringValue(rule.RuleArn)] {
+			unmanaged = append(unmanaged, rule)
+		}
+	}
+	sortLbListenerRulesByPriority(unmanaged)
+
+	address := d.Get("resource_address").(string)
+	ruleArns := make([]string, len(unmanaged))
+	importBlocks := make([]string, len(unmanaged))
+	for i, rule := range unmanaged {
+		ruleArns[i] = aws.StringValue(rule.RuleArn)
+		importBlocks[i] = lbListenerRuleImportBlock(address, aws.StringValue(rule.Priority), ruleArns[i])
+	}
+
+	d.SetId(listenerArn)
+	if err := d.Set("unmanaged_rule_arns", ruleArns); err != nil {
+		return fmt.Errorf("error setting unmanaged_rule_arns: %s", err)
+	}
+	if err := d.Set("import_blocks", importBlocks); err != nil {
+		return fmt.Errorf("error setting import_blocks: %s", err)
+	}
+
+	return nil
+}
+
+// lbListenerRuleImportBlock renders an import block for a rule, keyed by its
+// priority so the target resource can be declared with for_each.
+func lbListenerRuleImportBlock(address, priority, ruleArn string) string {
+	return fmt.Sprintf("import {\n  to = %s[%q]\n  id = %q\n}\n", address, priority, ruleArn)
+}
+
+// sortLbListenerRulesByPriority sorts rules by numeric priority.
+func sortLbListenerRulesByPriority(rules []*elbv2.Rule) {
+	sort.Slice(rules, func(i, j int) bool {
+		pi, _ := strconv.Atoi(aws.StringValue(rules[i].Priority))
+		pj, _ := strconv.Atoi(aws.StringValue(rules[j].Priority))
+		return pi < pj
+	})
+}
@@ -0,0 +1,74 @@
+package awspresence
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestLbListenerRuleImportBlock(t *testing.T) {
+	expected := `import {
+  to = awspresence_lb_listener_rule.imported["10"]
+  id = "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener-rule/app/test/1/2/3"
+}
+`
+	actual := lbListenerRuleImportBlock("awspresence_lb_listener_rule.imported", "10", "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener-rule/app/test/1/2/3")
+	if actual != expected {
+		t.Fatalf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestSortLbListenerRulesByPriority(t *testing.T) {
+	rules := []*elbv2.Rule{
+		{Priority: aws.String("100")},
+		{Priority: aws.String("9")},
+		{Priority: aws.String("20")},
+	}
+
+	sortLbListenerRulesByPriority(rules)
+
+	for i, expected := range []string{"9", "20", "100"} {
+		if actual := aws.StringValue(rules[i].Priority); actual != expected {
+			t.Fatalf("Expected priority %s at index %d, got %s", expected, i, actual)
+		}
+	}
+}
+
+func TestAccDataSourceAWSLBListenerRuleImports_basic(t *testing.T) {
+	lbName := fmt.Sprintf("testrule-imports-%s", acctest.RandStringFromCharSet(12, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAWSLBListenerRuleImportsConfig(lbName, targetGroupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_imports.all", "unmanaged_rule_arns.#", "1"),
+					resource.TestCheckResourceAttrPair("data.aws_lb_listener_rule_imports.all", "unmanaged_rule_arns.0", "aws_lb_listener_rule.static", "arn"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_imports.all", "import_blocks.#", "1"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_imports.managed", "unmanaged_rule_arns.#", "0"),
+					resource.TestCheckResourceAttr("data.aws_lb_listener_rule_imports.managed", "import_blocks.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAWSLBListenerRuleImportsConfig(lbName, targetGroupName string) string {
+	return testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName) + `
+data "aws_lb_listener_rule_imports" "all" {
+  listener_arn = "${aws_lb_listener_rule.static.listener_arn}"
+}
+
+data "aws_lb_listener_rule_imports" "managed" {
+  listener_arn      = "${aws_lb_listener_rule.static.listener_arn}"
+  managed_rule_arns = ["${aws_lb_listener_rule.static.arn}"]
+}
+`
+}
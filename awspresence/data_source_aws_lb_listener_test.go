@@ -73,13 +73,15 @@ func TestAccDataSourceAWSLBListener_BackwardsCompatibility(t *testing.T) {
 func TestAccDataSourceAWSLBListener_https(t *testing.T) {
 	lbName := fmt.Sprintf("testlistener-https-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
 	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
 
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProvidersWithTLS,
+		Providers: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccDataSourceAWSLBListenerConfigHTTPS(lbName, targetGroupName),
+				Config: testAccDataSourceAWSLBListenerConfigHTTPS(lbName, targetGroupName, key, certificate),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("data.aws_lb_listener.front_end", "load_balancer_arn"),
 					resource.TestCheckResourceAttrSet("data.aws_lb_listener.front_end", "arn"),
@@ -331,7 +333,7 @@ data "aws_alb_listener" "from_lb_and_port" {
 `, lbName, targetGroupName)
 }
 
-func testAccDataSourceAWSLBListenerConfigHTTPS(lbName, targetGroupName string) string {
+func testAccDataSourceAWSLBListenerConfigHTTPS(lbName, targetGroupName, key, certificate string) string {
 	return fmt.Sprintf(`
 resource "aws_lb_listener" "front_end" {
   load_balancer_arn = "${aws_lb.alb_test.id}"
@@ -442,30 +444,8 @@ resource "aws_security_group" "alb_test" {
 
 resource "aws_iam_server_certificate" "test_cert" {
   name             = "terraform-test-cert-%d"
-  certificate_body = "${tls_self_signed_cert.example.cert_pem}"
-  private_key      = "${tls_private_key.example.private_key_pem}"
-}
-
-resource "tls_private_key" "example" {
-  algorithm = "RSA"
-}
-
-resource "tls_self_signed_cert" "example" {
-  key_algorithm   = "RSA"
-  private_key_pem = "${tls_private_key.example.private_key_pem}"
-
-  subject {
-    common_name  = "example.com"
-    organization = "ACME Examples, Inc"
-  }
-
-  validity_period_hours = 12
-
-  allowed_uses = [
-    "key_encipherment",
-    "digital_signature",
-    "server_auth",
-  ]
+  certificate_body = "%s"
+  private_key      = "%s"
 }
 
 data "aws_lb_listener" "front_end" {
@@ -476,5 +456,5 @@ data "aws_lb_listener" "from_lb_and_port" {
   load_balancer_arn = "${aws_lb.alb_test.arn}"
   port              = "${aws_lb_listener.front_end.port}"
 }
-`, lbName, targetGroupName, acctest.RandInt())
+`, lbName, targetGroupName, acctest.RandInt(), tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key))
 }
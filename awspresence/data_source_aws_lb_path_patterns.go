@@ -0,0 +1,89 @@
+package awspresence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceAwsLbPathPatterns() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAwsLbPathPatternsRead,
+
+		Schema: map[string]*schema.Schema{
+			"patterns": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validate.LbPathPattern,
+				},
+			},
+
+			"normalized_patterns": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"wildcard_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAwsLbPathPatternsRead(d *schema.ResourceData, meta interface{}) error {
+	// The patterns are checked again here, as interpolated values are not
+	// known yet when the configuration is validated.
+	var patterns []string
+	var errs []string
+	for i, v := range d.Get("patterns").([]interface{}) {
+		pattern, _ := v.(string)
+		if _, es := validate.LbPathPattern(pattern, fmt.Sprintf("patterns.%d", i)); len(es) > 0 {
+			for _, err := range es {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("Invalid path patterns:\n%s", strings.Join(errs, "\n"))
+	}
+
+	normalized := normalizeLbPathPatterns(patterns)
+	wildcards := 0
+	for _, pattern := range normalized {
+		wildcards += validate.LbPathPatternWildcardCount(pattern)
+	}
+
+	d.SetId(fmt.Sprintf("%d", hashcode.String(strings.Join(normalized, "\n"))))
+	if err := d.Set("normalized_patterns", normalized); err != nil {
+		return fmt.Errorf("error setting normalized_patterns: %s", err)
+	}
+	d.Set("wildcard_count", wildcards)
+
+	return nil
+}
+
+// normalizeLbPathPatterns normalizes each path pattern and drops the
+// duplicates, keeping the first occurrence of each.
+func normalizeLbPathPatterns(patterns []string) []string {
+	seen := make(map[string]bool, len(patterns))
+	result := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = validate.NormalizeLbPathPattern(pattern)
+		if seen[pattern] {
+			continue
+		}
+		seen[pattern] = true
+		result = append(result, pattern)
+	}
+	return result
+}
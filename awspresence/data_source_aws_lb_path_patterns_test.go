@@ -0,0 +1,61 @@
+package awspresence
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestNormalizeLbPathPatterns(t *testing.T) {
+	patterns := []string{" /static/* ", "/img/**/*.png", "/static/*", "/img/*/*.png", "/api"}
+	expected := []string{"/static/*", "/img/*/*.png", "/api"}
+
+	if got := normalizeLbPathPatterns(patterns); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestAccDataSourceAWSLBPathPatterns_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAWSLBPathPatternsConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aws_lb_path_patterns.test", "normalized_patterns.#", "2"),
+					resource.TestCheckResourceAttr("data.aws_lb_path_patterns.test", "normalized_patterns.0", "/static/*"),
+					resource.TestCheckResourceAttr("data.aws_lb_path_patterns.test", "normalized_patterns.1", "/img/*.png"),
+					resource.TestCheckResourceAttr("data.aws_lb_path_patterns.test", "wildcard_count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAWSLBPathPatterns_invalid(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceAWSLBPathPatternsConfig_invalid,
+				ExpectError: regexp.MustCompile(`illegal characters`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceAWSLBPathPatternsConfig = `
+data "aws_lb_path_patterns" "test" {
+  patterns = [" /static/* ", "/img/**.png", "/static/*"]
+}
+`
+
+const testAccDataSourceAWSLBPathPatternsConfig_invalid = `
+data "aws_lb_path_patterns" "test" {
+  patterns = ["/users/{id}"]
+}
+`
@@ -0,0 +1,178 @@
+package awspresence
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// lbRuleChangeEventNames are the CloudTrail events of the ELBv2 API calls that
+// change an existing rule.
+var lbRuleChangeEventNames = map[string]bool{
+	"ModifyRule":        true,
+	"SetRulePriorities": true,
+}
+
+func dataSourceAwsLbRuleChangeHistory() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAwsLbRuleChangeHistoryRead,
+		Schema: map[string]*schema.Schema{
+			"rule_arn": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validate.Arn,
+			},
+
+			"start_time": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRFC3339TimeString,
+			},
+
+			"end_time": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRFC3339TimeString,
+			},
+
+			"events": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"event_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"event_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"event_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"principal_arn": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"access_key_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source_ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"user_agent": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAwsLbRuleChangeHistoryRead(d *schema.ResourceData, meta interface{}) error {
+	ruleArn := d.Get("rule_arn").(string)
+
+	// CloudTrail records events in the region of the rule.
+	client := meta.(*AWSClient)
+	if parsed, err := arn.Parse(ruleArn); err == nil {
+		client = client.regionalClient(parsed.Region)
+	}
+	conn := client.cloudtrailconn
+
+	input := &cloudtrail.LookupEventsInput{
+		LookupAttributes: []*cloudtrail.LookupAttribute{
+			{
+				AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyResourceName),
+				AttributeValue: aws.String(ruleArn),
+			},
+		},
+	}
+	if v, ok := d.GetOk("start_time"); ok {
+		t, _ := time.Parse(time.RFC3339, v.(string))
+		input.StartTime = aws.Time(t)
+	}
+	if v, ok := d.GetOk("end_time"); ok {
+		t, _ := time.Parse(time.RFC3339, v.(string))
+		input.EndTime = aws.Time(t)
+	}
+
+	events := []interface{}{}
+	err := paginate("LookupEvents", func(marker *string) (*string, error) {
+		input.NextToken = marker
+		resp, err := conn.LookupEvents(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range resp.Events {
+			if !lbRuleChangeEventNames[aws.StringValue(event.EventName)] {
+				continue
+			}
+			events = append(events, flattenLbRuleChangeHistoryEvent(event))
+		}
+
+		return resp.NextToken, nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error looking up CloudTrail events of LB Listener Rule %q: %s", ruleArn, err)
+	}
+
+	d.SetId(ruleArn)
+	if err := d.Set("events", events); err != nil {
+		return fmt.Errorf("error setting events: %s", err)
+	}
+
+	return nil
+}
+
+// lbRuleChangeHistoryRecord holds the fields of a CloudTrail event record
+// that LookupEvents does not return on its own.
+type lbRuleChangeHistoryRecord struct {
+	UserIdentity struct {
+		Arn string `json:"arn"`
+	} `json:"userIdentity"`
+	SourceIPAddress string `json:"sourceIPAddress"`
+	UserAgent       string `json:"userAgent"`
+}
+
+func flattenLbRuleChangeHistoryEvent(event *cloudtrail.Event) map[string]interface{} {
+	m := map[string]interface{}{
+		"event_id":      aws.StringValue(event.EventId),
+		"event_name":    aws.StringValue(event.EventName),
+		"username":      aws.StringValue(event.Username),
+		"access_key_id": aws.StringValue(event.AccessKeyId),
+	}
+	if event.EventTime != nil {
+		m["event_time"] = aws.TimeValue(event.EventTime).UTC().Format(time.RFC3339)
+	}
+
+	var record lbRuleChangeHistoryRecord
+	if v := aws.StringValue(event.CloudTrailEvent); v != "" {
+		if err := json.Unmarshal([]byte(v), &record); err != nil {
+			log.Printf("[WARN] Unable to parse CloudTrail event %s: %s", aws.StringValue(event.EventId), err)
+		}
+	}
+	m["principal_arn"] = record.UserIdentity.Arn
+	m["source_ip_address"] = record.SourceIPAddress
+	m["user_agent"] = record.UserAgent
+
+	return m
+}
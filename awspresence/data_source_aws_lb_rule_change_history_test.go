@@ -0,0 +1,133 @@
+package awspresence
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestFlattenLbRuleChangeHistoryEvent(t *testing.T) {
+	event := &cloudtrail.Event{
+		AccessKeyId: aws.String("ASIAEXAMPLE"),
+		CloudTrailEvent: aws.String(`{
+  "eventVersion": "1.05",
+  "userIdentity": {
+    "type": "AssumedRole",
+    "arn": "arn:aws:sts::123456789012:assumed-role/deploy/ci"
+  },
+  "eventName": "ModifyRule",
+  "sourceIPAddress": "203.0.113.10",
+  "userAgent": "APN/1.0 HashiCorp/1.0 Terraform/0.12.6 tf-module/module.edge"
+}`),
+		EventId:   aws.String("3f0e2d4c-6e0a-4d5b-9a4f-1d2c3b4a5f60"),
+		EventName: aws.String("ModifyRule"),
+		EventTime: aws.Time(time.Date(2019, 8, 1, 12, 30, 0, 0, time.UTC)),
+		Username:  aws.String("ci"),
+	}
+
+	expected := map[string]interface{}{
+		"event_id":          "3f0e2d4c-6e0a-4d5b-9a4f-1d2c3b4a5f60",
+		"event_name":        "ModifyRule",
+		"event_time":        "2019-08-01T12:30:00Z",
+		"username":          "ci",
+		"principal_arn":     "arn:aws:sts::123456789012:assumed-role/deploy/ci",
+		"access_key_id":     "ASIAEXAMPLE",
+		"source_ip_address": "203.0.113.10",
+		"user_agent":        "APN/1.0 HashiCorp/1.0 Terraform/0.12.6 tf-module/module.edge",
+	}
+
+	if got := flattenLbRuleChangeHistoryEvent(event); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, got)
+	}
+
+	event.CloudTrailEvent = aws.String("not json")
+	got := flattenLbRuleChangeHistoryEvent(event)
+	if got["principal_arn"] != "" || got["event_name"] != "ModifyRule" {
+		t.Fatalf("expected lookup fields only for an unparsable record, got %#v", got)
+	}
+}
+
+func TestAccDataSourceAWSLBRuleChangeHistory_basic(t *testing.T) {
+	var rule elbv2.Rule
+	lbName := fmt.Sprintf("testrule-history-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	startTime := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	dataSourceName := "data.aws_lb_rule_change_history.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName),
+			},
+			{
+				Config: testAccDataSourceAWSLBRuleChangeHistoryConfig_modified(lbName, targetGroupName),
+				Check:  testAccCheckAWSLBListenerRuleExists("aws_lb_listener_rule.static", &rule),
+			},
+			{
+				PreConfig: func() { testAccWaitForLbRuleChangeHistory(t, &rule) },
+				Config:    testAccDataSourceAWSLBRuleChangeHistoryConfig(lbName, targetGroupName, startTime),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "aws_lb_listener_rule.static", "arn"),
+					resource.TestCheckResourceAttr(dataSourceName, "events.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "events.0.event_name", "ModifyRule"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "events.0.event_id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "events.0.event_time"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "events.0.principal_arn"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "events.0.user_agent"),
+				),
+			},
+		},
+	})
+}
+
+// testAccWaitForLbRuleChangeHistory waits for CloudTrail, which delivers
+// events several minutes after the API call, to return a change of rule.
+func testAccWaitForLbRuleChangeHistory(t *testing.T, rule *elbv2.Rule) {
+	conn := testAccProvider.Meta().(*AWSClient).cloudtrailconn
+
+	err := resource.Retry(20*time.Minute, func() *resource.RetryError {
+		resp, err := conn.LookupEvents(&cloudtrail.LookupEventsInput{
+			LookupAttributes: []*cloudtrail.LookupAttribute{
+				{
+					AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyResourceName),
+					AttributeValue: rule.RuleArn,
+				},
+			},
+		})
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		for _, event := range resp.Events {
+			if lbRuleChangeEventNames[aws.StringValue(event.EventName)] {
+				return nil
+			}
+		}
+		return resource.RetryableError(fmt.Errorf("no change of LB Listener Rule %s recorded yet", aws.StringValue(rule.RuleArn)))
+	})
+	if err != nil {
+		t.Fatalf("error waiting for CloudTrail events: %s", err)
+	}
+}
+
+func testAccDataSourceAWSLBRuleChangeHistoryConfig_modified(lbName, targetGroupName string) string {
+	return strings.Replace(testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName), `"/static/*"`, `"/assets/*"`, 1)
+}
+
+func testAccDataSourceAWSLBRuleChangeHistoryConfig(lbName, targetGroupName, startTime string) string {
+	return testAccDataSourceAWSLBRuleChangeHistoryConfig_modified(lbName, targetGroupName) + fmt.Sprintf(`
+data "aws_lb_rule_change_history" "test" {
+  rule_arn   = "${aws_lb_listener_rule.static.arn}"
+  start_time = %q
+}
+`, startTime)
+}
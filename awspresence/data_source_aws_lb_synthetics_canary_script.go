@@ -0,0 +1,282 @@
+package awspresence
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+const (
+	// lbSyntheticsCanaryHandler is the handler of the generated script, in
+	// the file layout expected by the Node.js Synthetics runtimes.
+	lbSyntheticsCanaryHandler    = "index.handler"
+	lbSyntheticsCanaryScriptPath = "nodejs/node_modules/index.js"
+)
+
+var lbSyntheticsCanaryScriptTemplate = template.Must(template.New("canary").Parse(`const synthetics = require('Synthetics');
+const log = require('SyntheticsLogger');
+const client = require('{{ .Module }}');
+
+const options = {{ .Options }};
+const expectedStatusCodes = {{ .ExpectedStatusCodes }};
+const timeoutMs = {{ .TimeoutMs }};
+
+const checkLoadBalancer = async function () {
+  options.headers['User-Agent'] = synthetics.getCanaryUserAgentString();
+
+  return new Promise((resolve, reject) => {
+    const req = client.request(options, (res) => {
+      res.resume();
+      log.info('Status code: ' + res.statusCode);
+      if (!expectedStatusCodes.includes(res.statusCode)) {
+        reject(new Error('Unexpected status code ' + res.statusCode));
+        return;
+      }
+      resolve();
+    });
+    req.on('error', reject);
+    req.setTimeout(timeoutMs, () => req.destroy(new Error('Request timed out')));
+    req.end();
+  });
+};
+
+exports.handler = async () => {
+  return await synthetics.executeStep('checkLoadBalancer', checkLoadBalancer);
+};
+`))
+
+func dataSourceAwsLbSyntheticsCanaryScript() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAwsLbSyntheticsCanaryScriptRead,
+
+		Schema: map[string]*schema.Schema{
+			"dns_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"host_header": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"path": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "/",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^/`), "must begin with a forward slash"),
+			},
+
+			"protocol": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "HTTPS",
+				StateFunc: func(v interface{}) string {
+					return strings.ToUpper(v.(string))
+				},
+				ValidateFunc: validation.StringInSlice([]string{"HTTP", "HTTPS"}, true),
+			},
+
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+
+			"expected_status_codes": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntBetween(100, 599),
+				},
+			},
+
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(1, 840),
+			},
+
+			"output_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"script": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"handler": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"output_base64sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAwsLbSyntheticsCanaryScriptRead(d *schema.ResourceData, meta interface{}) error {
+	var expectedStatusCodes []int
+	for _, v := range d.Get("expected_status_codes").([]interface{}) {
+		expectedStatusCodes = append(expectedStatusCodes, v.(int))
+	}
+
+	script, err := lbSyntheticsCanaryScript(&lbSyntheticsCanaryCheck{
+		DnsName:             d.Get("dns_name").(string),
+		HostHeader:          d.Get("host_header").(string),
+		Path:                d.Get("path").(string),
+		Protocol:            strings.ToUpper(d.Get("protocol").(string)),
+		Port:                d.Get("port").(int),
+		ExpectedStatusCodes: expectedStatusCodes,
+		Timeout:             time.Duration(d.Get("timeout").(int)) * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("Error generating canary script: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", hashcode.String(script)))
+	d.Set("script", script)
+	d.Set("handler", lbSyntheticsCanaryHandler)
+
+	if v, ok := d.GetOk("output_path"); ok {
+		archive, err := lbSyntheticsCanaryArchive(script)
+		if err != nil {
+			return fmt.Errorf("Error archiving canary script: %s", err)
+		}
+
+		outputPath := v.(string)
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return fmt.Errorf("Error creating directory for %q: %s", outputPath, err)
+		}
+		if err := ioutil.WriteFile(outputPath, archive, 0644); err != nil {
+			return fmt.Errorf("Error writing canary archive %q: %s", outputPath, err)
+		}
+
+		sum := sha256.Sum256(archive)
+		d.Set("output_base64sha256", base64.StdEncoding.EncodeToString(sum[:]))
+	} else {
+		d.Set("output_base64sha256", "")
+	}
+
+	return nil
+}
+
+// lbSyntheticsCanaryCheck describes the request a canary makes to a load
+// balancer.
+type lbSyntheticsCanaryCheck struct {
+	DnsName             string
+	HostHeader          string
+	Path                string
+	Protocol            string
+	Port                int
+	ExpectedStatusCodes []int
+	Timeout             time.Duration
+}
+
+// lbSyntheticsCanaryScript renders a Node.js canary that requests the load
+// balancer DNS name directly, sending the host header the listener rules
+// route on. Over HTTPS the host header is also used for SNI so the listener
+// selects the matching certificate.
+func lbSyntheticsCanaryScript(check *lbSyntheticsCanaryCheck) (string, error) {
+	module := "https"
+	port := 443
+	if check.Protocol == "HTTP" {
+		module = "http"
+		port = 80
+	}
+	if check.Port != 0 {
+		port = check.Port
+	}
+
+	expectedStatusCodes := check.ExpectedStatusCodes
+	if len(expectedStatusCodes) == 0 {
+		expectedStatusCodes = []int{200}
+	}
+
+	host := check.HostHeader
+	if host == "" {
+		host = check.DnsName
+	}
+
+	options := map[string]interface{}{
+		"hostname": check.DnsName,
+		"port":     port,
+		"path":     check.Path,
+		"method":   "GET",
+		"headers": map[string]string{
+			"Host": host,
+		},
+	}
+	if module == "https" {
+		options["servername"] = host
+	}
+
+	optionsJson, err := json.MarshalIndent(options, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	expectedStatusCodesJson, err := json.Marshal(expectedStatusCodes)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	err = lbSyntheticsCanaryScriptTemplate.Execute(&b, map[string]interface{}{
+		"Module":              module,
+		"Options":             string(optionsJson),
+		"ExpectedStatusCodes": string(expectedStatusCodesJson),
+		"TimeoutMs":           int64(check.Timeout / time.Millisecond),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// lbSyntheticsCanaryArchive returns a zip archive holding script. Timestamps
+// are fixed so that the archive, and its hash, only change with the script.
+func lbSyntheticsCanaryArchive(script string) ([]byte, error) {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+
+	header := &zip.FileHeader{
+		Name:     lbSyntheticsCanaryScriptPath,
+		Method:   zip.Deflate,
+		Modified: time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	f, err := w.CreateHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write([]byte(script)); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+package awspresence
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestLbSyntheticsCanaryScript(t *testing.T) {
+	script, err := lbSyntheticsCanaryScript(&lbSyntheticsCanaryCheck{
+		DnsName:    "internal-test-1234567890.us-west-2.elb.amazonaws.com",
+		HostHeader: "www.example.com",
+		Path:       "/health",
+		Protocol:   "HTTPS",
+		Timeout:    5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, expected := range []string{
+		"require('https')",
+		`"hostname": "internal-test-1234567890.us-west-2.elb.amazonaws.com"`,
+		`"Host": "www.example.com"`,
+		`"servername": "www.example.com"`,
+		`"path": "/health"`,
+		`"port": 443`,
+		"const expectedStatusCodes = [200];",
+		"const timeoutMs = 5000;",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Fatalf("expected script to contain %q:\n%s", expected, script)
+		}
+	}
+
+	script, err = lbSyntheticsCanaryScript(&lbSyntheticsCanaryCheck{
+		DnsName:             "test.elb.amazonaws.com",
+		Path:                "/",
+		Protocol:            "HTTP",
+		Port:                8080,
+		ExpectedStatusCodes: []int{200, 301},
+		Timeout:             10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, expected := range []string{
+		"require('http')",
+		`"Host": "test.elb.amazonaws.com"`,
+		`"port": 8080`,
+		"const expectedStatusCodes = [200,301];",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Fatalf("expected script to contain %q:\n%s", expected, script)
+		}
+	}
+	if strings.Contains(script, "servername") {
+		t.Fatalf("expected no servername for HTTP:\n%s", script)
+	}
+}
+
+func TestLbSyntheticsCanaryArchive(t *testing.T) {
+	first, err := lbSyntheticsCanaryArchive("script")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := lbSyntheticsCanaryArchive("script")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatal("expected archives of the same script to be identical")
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(first), int64(len(first)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(r.File) != 1 || r.File[0].Name != lbSyntheticsCanaryScriptPath {
+		t.Fatalf("expected a single %s file in the archive", lbSyntheticsCanaryScriptPath)
+	}
+
+	f, err := r.File[0].Open()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer f.Close()
+
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(content) != "script" {
+		t.Fatalf("expected archived script, got %q", content)
+	}
+}
+
+func TestAccDataSourceAWSLBSyntheticsCanaryScript_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAWSLBSyntheticsCanaryScriptConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aws_lb_synthetics_canary_script.test", "handler", lbSyntheticsCanaryHandler),
+					resource.TestCheckResourceAttr("data.aws_lb_synthetics_canary_script.test", "protocol", "HTTPS"),
+					resource.TestCheckResourceAttrSet("data.aws_lb_synthetics_canary_script.test", "script"),
+					resource.TestCheckResourceAttr("data.aws_lb_synthetics_canary_script.test", "output_base64sha256", ""),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceAWSLBSyntheticsCanaryScriptConfig = `
+data "aws_lb_synthetics_canary_script" "test" {
+  dns_name    = "internal-test-1234567890.us-west-2.elb.amazonaws.com"
+  host_header = "www.example.com"
+  path        = "/health"
+}
+`
@@ -146,7 +146,37 @@ func dataSourceAwsLbTargetGroup() *schema.Resource {
 				},
 			},
 
+			"security_group_ingress_requirements": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"port": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"traffic": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"health_check": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+
 			"tags": tagsSchemaComputed(),
+
+			"region": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
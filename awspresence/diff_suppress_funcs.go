@@ -3,8 +3,10 @@ package awspresence
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -41,6 +43,52 @@ func suppressMissingOptionalConfigurationBlock(k, old, new string, d *schema.Res
 	return old == "1" && new == "0"
 }
 
+// lbAuthenticateDefaultExtraParams are the authentication_request_extra_params
+// AWS adds to LB authenticate actions in some partitions.
+var lbAuthenticateDefaultExtraParams = map[string]string{
+	"prompt": "login",
+}
+
+// suppressLbAuthenticateDefaultExtraParams suppresses the differences in the
+// authentication_request_extra_params map of LB authenticate actions caused by
+// the params AWS adds by default, as long as the configuration does not set
+// them. Any other difference, such as removing a configured param, is kept.
+func suppressLbAuthenticateDefaultExtraParams(k, old, new string, d *schema.ResourceData) bool {
+	const attr = "authentication_request_extra_params"
+
+	i := strings.Index(k, attr)
+	if i == -1 {
+		return false
+	}
+
+	key := strings.TrimPrefix(k[i+len(attr):], ".")
+	if key != "%" {
+		// A param missing from the configuration diffs to an empty value.
+		v, ok := lbAuthenticateDefaultExtraParams[key]
+		return ok && old == v && new == ""
+	}
+
+	// The number of params may only shrink by the default params in the state.
+	oldCount, err := strconv.Atoi(old)
+	if err != nil {
+		return false
+	}
+	newCount, err := strconv.Atoi(new)
+	if err != nil {
+		return false
+	}
+
+	o, _ := d.GetChange(k[:i+len(attr)])
+	existing, _ := o.(map[string]interface{})
+	defaults := 0
+	for name, value := range existing {
+		if v, ok := lbAuthenticateDefaultExtraParams[name]; ok && fmt.Sprintf("%v", value) == v {
+			defaults++
+		}
+	}
+	return newCount < oldCount && oldCount-newCount <= defaults
+}
+
 // Suppresses minor version changes to the db_instance engine_version attribute
 func suppressAwsDbEngineVersionDiffs(k, old, new string, d *schema.ResourceData) bool {
 	// First check if the old/new values are nil.
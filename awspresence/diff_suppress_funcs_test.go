@@ -1,9 +1,14 @@
 package awspresence
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 )
 
 func TestSuppressEquivalentJsonDiffsWhitespaceAndNoWhitespace(t *testing.T) {
@@ -320,3 +325,105 @@ func TestSuppressRoute53ZoneNameWithTrailingDot(t *testing.T) {
 		}
 	}
 }
+
+func TestSuppressLbAuthenticateDefaultExtraParams(t *testing.T) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"authenticate_oidc": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"authentication_request_extra_params": {
+							Type:             schema.TypeMap,
+							Optional:         true,
+							DiffSuppressFunc: suppressLbAuthenticateDefaultExtraParams,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	const prefix = "authenticate_oidc.0.authentication_request_extra_params."
+	testCases := []struct {
+		name       string
+		state      map[string]string
+		configured map[string]interface{}
+		changed    []string
+	}{
+		{
+			name:  "default param not configured",
+			state: map[string]string{"prompt": "login"},
+		},
+		{
+			name:       "default param configured",
+			state:      map[string]string{"prompt": "login"},
+			configured: map[string]interface{}{"prompt": "login"},
+		},
+		{
+			name:       "default param next to configured params",
+			state:      map[string]string{"display": "page", "prompt": "login"},
+			configured: map[string]interface{}{"display": "page"},
+		},
+		{
+			name:    "configured param removed",
+			state:   map[string]string{"display": "page"},
+			changed: []string{prefix + "%", prefix + "display"},
+		},
+		{
+			name:    "configured param removed next to default param",
+			state:   map[string]string{"display": "page", "prompt": "login"},
+			changed: []string{prefix + "%", prefix + "display"},
+		},
+		{
+			name:    "param with a non-default value removed",
+			state:   map[string]string{"prompt": "consent"},
+			changed: []string{prefix + "%", prefix + "prompt"},
+		},
+		{
+			name:       "configured param changed",
+			state:      map[string]string{"display": "page", "prompt": "login"},
+			configured: map[string]interface{}{"display": "popup"},
+			changed:    []string{prefix + "display"},
+		},
+	}
+
+	for _, tc := range testCases {
+		attributes := map[string]string{
+			"authenticate_oidc.#": "1",
+			prefix + "%":          fmt.Sprintf("%d", len(tc.state)),
+		}
+		for k, v := range tc.state {
+			attributes[prefix+k] = v
+		}
+
+		block := map[string]interface{}{}
+		if tc.configured != nil {
+			block["authentication_request_extra_params"] = tc.configured
+		}
+		c, err := config.NewRawConfig(map[string]interface{}{
+			"authenticate_oidc": []interface{}{block},
+		})
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+
+		diff, err := r.Diff(&terraform.InstanceState{ID: "test", Attributes: attributes}, terraform.NewResourceConfig(c), nil)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+
+		var changed []string
+		if diff != nil {
+			for k := range diff.Attributes {
+				changed = append(changed, k)
+			}
+		}
+		sort.Strings(changed)
+
+		if !reflect.DeepEqual(changed, tc.changed) {
+			t.Errorf("%s: expected changes %q, got %q", tc.name, tc.changed, changed)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package awspresence
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// The vendored EC2 client predates customer managed prefix lists, so the two
+// read operations used to resolve them are declared here. The shapes mirror
+// the EC2 2016-11-15 API, which the client already targets.
+
+type ec2DescribeManagedPrefixListsInput struct {
+	_ struct{} `type:"structure"`
+
+	Filters []*ec2.Filter `locationName:"Filter" locationNameList:"Filter" type:"list"`
+
+	MaxResults *int64 `type:"integer"`
+
+	NextToken *string `type:"string"`
+
+	PrefixListIds []*string `locationName:"PrefixListId" locationNameList:"item" type:"list"`
+}
+
+type ec2DescribeManagedPrefixListsOutput struct {
+	_ struct{} `type:"structure"`
+
+	NextToken *string `locationName:"nextToken" type:"string"`
+
+	PrefixLists []*ec2ManagedPrefixList `locationName:"prefixListSet" locationNameList:"item" type:"list"`
+}
+
+type ec2ManagedPrefixList struct {
+	_ struct{} `type:"structure"`
+
+	PrefixListId *string `locationName:"prefixListId" type:"string"`
+
+	PrefixListName *string `locationName:"prefixListName" type:"string"`
+
+	State *string `locationName:"state" type:"string"`
+
+	Version *int64 `locationName:"version" type:"long"`
+}
+
+type ec2GetManagedPrefixListEntriesInput struct {
+	_ struct{} `type:"structure"`
+
+	MaxResults *int64 `type:"integer"`
+
+	NextToken *string `type:"string"`
+
+	PrefixListId *string `type:"string" required:"true"`
+
+	TargetVersion *int64 `type:"long"`
+}
+
+type ec2GetManagedPrefixListEntriesOutput struct {
+	_ struct{} `type:"structure"`
+
+	Entries []*ec2PrefixListEntry `locationName:"entrySet" locationNameList:"item" type:"list"`
+
+	NextToken *string `locationName:"nextToken" type:"string"`
+}
+
+type ec2PrefixListEntry struct {
+	_ struct{} `type:"structure"`
+
+	Cidr *string `locationName:"cidr" type:"string"`
+
+	Description *string `locationName:"description" type:"string"`
+}
+
+func ec2DescribeManagedPrefixLists(conn *ec2.EC2, input *ec2DescribeManagedPrefixListsInput) (*ec2DescribeManagedPrefixListsOutput, error) {
+	output := &ec2DescribeManagedPrefixListsOutput{}
+	req := conn.NewRequest(&request.Operation{
+		Name:       "DescribeManagedPrefixLists",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}, input, output)
+	return output, req.Send()
+}
+
+func ec2GetManagedPrefixListEntries(conn *ec2.EC2, input *ec2GetManagedPrefixListEntriesInput) (*ec2GetManagedPrefixListEntriesOutput, error) {
+	output := &ec2GetManagedPrefixListEntriesOutput{}
+	req := conn.NewRequest(&request.Operation{
+		Name:       "GetManagedPrefixListEntries",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}, input, output)
+	return output, req.Send()
+}
+
+// managedPrefixListVersions returns the current version of each of the
+// prefix lists, keyed by prefix list ID.
+func managedPrefixListVersions(conn *ec2.EC2, prefixListIds []string) (map[string]int64, error) {
+	versions := make(map[string]int64)
+	if len(prefixListIds) == 0 {
+		return versions, nil
+	}
+
+	err := paginate("DescribeManagedPrefixLists", func(marker *string) (*string, error) {
+		resp, err := ec2DescribeManagedPrefixLists(conn, &ec2DescribeManagedPrefixListsInput{
+			PrefixListIds: aws.StringSlice(prefixListIds),
+			NextToken:     marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, prefixList := range resp.PrefixLists {
+			versions[aws.StringValue(prefixList.PrefixListId)] = aws.Int64Value(prefixList.Version)
+		}
+
+		return resp.NextToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range prefixListIds {
+		if _, ok := versions[id]; !ok {
+			return nil, fmt.Errorf("prefix list %q not found", id)
+		}
+	}
+
+	return versions, nil
+}
+
+// managedPrefixListCidrs returns the CIDRs of version of a prefix list, in
+// the order EC2 returns them.
+func managedPrefixListCidrs(conn *ec2.EC2, prefixListId string, version int64) ([]string, error) {
+	var cidrs []string
+	err := paginate("GetManagedPrefixListEntries", func(marker *string) (*string, error) {
+		resp, err := ec2GetManagedPrefixListEntries(conn, &ec2GetManagedPrefixListEntriesInput{
+			PrefixListId:  aws.String(prefixListId),
+			TargetVersion: aws.Int64(version),
+			NextToken:     marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range resp.Entries {
+			cidrs = append(cidrs, aws.StringValue(entry.Cidr))
+		}
+
+		return resp.NextToken, nil
+	})
+
+	return cidrs, err
+}
+
+// managedPrefixListIdByName returns the ID of the prefix list named name,
+// such as the AWS managed com.amazonaws.global.cloudfront.origin-facing.
+func managedPrefixListIdByName(conn *ec2.EC2, name string) (string, error) {
+	resp, err := ec2DescribeManagedPrefixLists(conn, &ec2DescribeManagedPrefixListsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("prefix-list-name"),
+				Values: aws.StringSlice([]string{name}),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.PrefixLists) != 1 {
+		return "", fmt.Errorf("expected 1 prefix list named %q, found %d", name, len(resp.PrefixLists))
+	}
+
+	return aws.StringValue(resp.PrefixLists[0].PrefixListId), nil
+}
@@ -0,0 +1,85 @@
+package awspresence
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awsbase "github.com/hashicorp/aws-sdk-go-base"
+)
+
+func TestManagedPrefixListVersionsAndCidrs(t *testing.T) {
+	ec2Endpoints := []*awsbase.MockEndpoint{
+		{
+			Request: &awsbase.MockRequest{
+				Method: "POST",
+				Uri:    "/",
+				Body:   "Action=DescribeManagedPrefixLists&PrefixListId.1=pl-0123456789abcdef0&Version=2016-11-15",
+			},
+			Response: &awsbase.MockResponse{
+				StatusCode:  200,
+				Body:        test_ec2_describeManagedPrefixLists_response,
+				ContentType: "text/xml",
+			},
+		},
+		{
+			Request: &awsbase.MockRequest{
+				Method: "POST",
+				Uri:    "/",
+				Body:   "Action=GetManagedPrefixListEntries&PrefixListId=pl-0123456789abcdef0&TargetVersion=3&Version=2016-11-15",
+			},
+			Response: &awsbase.MockResponse{
+				StatusCode:  200,
+				Body:        test_ec2_getManagedPrefixListEntries_response,
+				ContentType: "text/xml",
+			},
+		},
+	}
+	closeFunc, sess, err := awsbase.GetMockedAwsApiSession("EC2", ec2Endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFunc()
+	conn := ec2.New(sess)
+
+	versions, err := managedPrefixListVersions(conn, []string{"pl-0123456789abcdef0"})
+	if err != nil {
+		t.Fatalf("Expected no error, received: %s", err)
+	}
+	if expected := map[string]int64{"pl-0123456789abcdef0": 3}; !reflect.DeepEqual(versions, expected) {
+		t.Fatalf("Received versions: %v\nExpected: %v", versions, expected)
+	}
+
+	cidrs, err := managedPrefixListCidrs(conn, "pl-0123456789abcdef0", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, received: %s", err)
+	}
+	if expected := []string{"192.0.2.0/24", "198.51.100.17/32"}; !reflect.DeepEqual(cidrs, expected) {
+		t.Fatalf("Received CIDRs: %q\nExpected: %q", cidrs, expected)
+	}
+}
+
+var test_ec2_describeManagedPrefixLists_response = `<DescribeManagedPrefixListsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>7a62c49f-347e-4fc4-9331-6e8eEXAMPLE</requestId>
+  <prefixListSet>
+    <item>
+      <prefixListId>pl-0123456789abcdef0</prefixListId>
+      <prefixListName>office</prefixListName>
+      <state>modify-complete</state>
+      <version>3</version>
+    </item>
+  </prefixListSet>
+</DescribeManagedPrefixListsResponse>`
+
+var test_ec2_getManagedPrefixListEntries_response = `<GetManagedPrefixListEntriesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>7a62c49f-347e-4fc4-9331-6e8eEXAMPLE</requestId>
+  <entrySet>
+    <item>
+      <cidr>192.0.2.0/24</cidr>
+      <description>London office</description>
+    </item>
+    <item>
+      <cidr>198.51.100.17/32</cidr>
+    </item>
+  </entrySet>
+</GetManagedPrefixListEntriesResponse>`
@@ -0,0 +1,241 @@
+// Package flatten converts the listeners and listener rules returned by the
+// ELBv2 API into the schema representation of the listener and listener rule
+// resources, so that both write their actions to state the same way.
+package flatten
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// DefaultRulePriority is the priority of the default rule of a listener in
+// state. The API reports it as "default".
+const DefaultRulePriority = 99999
+
+// ClientSecretFunc returns the client secret of the authenticate-oidc action
+// at index i. The API never returns client secrets, so the resources pass
+// through the value already in state.
+type ClientSecretFunc func(i int) string
+
+// Listener flattens a listener into the attributes of the listener resource.
+// The default actions are sorted by order. certificate_arn is only set when
+// the listener has a single certificate.
+func Listener(listener *elbv2.Listener, clientSecret ClientSecretFunc) map[string]interface{} {
+	m := map[string]interface{}{
+		"arn":               aws.StringValue(listener.ListenerArn),
+		"load_balancer_arn": aws.StringValue(listener.LoadBalancerArn),
+		"port":              aws.Int64Value(listener.Port),
+		"protocol":          aws.StringValue(listener.Protocol),
+		"ssl_policy":        aws.StringValue(listener.SslPolicy),
+	}
+
+	if len(listener.Certificates) == 1 && listener.Certificates[0] != nil {
+		m["certificate_arn"] = aws.StringValue(listener.Certificates[0].CertificateArn)
+	}
+
+	defaultActions := make([]*elbv2.Action, len(listener.DefaultActions))
+	copy(defaultActions, listener.DefaultActions)
+	sort.Slice(defaultActions, func(i, j int) bool {
+		return aws.Int64Value(defaultActions[i].Order) < aws.Int64Value(defaultActions[j].Order)
+	})
+	m["default_action"] = Actions(defaultActions, clientSecret)
+
+	return m
+}
+
+// Rule flattens a rule into the attributes of the listener rule resource. The
+// actions and conditions keep the order of the rule.
+func Rule(rule *elbv2.Rule, clientSecret ClientSecretFunc) (map[string]interface{}, error) {
+	priority := DefaultRulePriority
+	if p := aws.StringValue(rule.Priority); p != "default" {
+		var err error
+		if priority, err = strconv.Atoi(p); err != nil {
+			return nil, fmt.Errorf("Cannot convert rule priority %q to int: %s", p, err)
+		}
+	}
+
+	return map[string]interface{}{
+		"arn":       aws.StringValue(rule.RuleArn),
+		"priority":  priority,
+		"action":    Actions(rule.Actions, clientSecret),
+		"condition": Conditions(rule.Conditions),
+	}, nil
+}
+
+// Actions flattens actions into action blocks, in the order given.
+func Actions(actions []*elbv2.Action, clientSecret ClientSecretFunc) []interface{} {
+	result := make([]interface{}, len(actions))
+	for i, action := range actions {
+		secret := ""
+		if clientSecret != nil && aws.StringValue(action.Type) == elbv2.ActionTypeEnumAuthenticateOidc {
+			secret = clientSecret(i)
+		}
+		result[i] = Action(action, secret)
+	}
+	return result
+}
+
+// Action flattens an action into an action block.
+func Action(action *elbv2.Action, clientSecret string) map[string]interface{} {
+	m := map[string]interface{}{
+		"type":  aws.StringValue(action.Type),
+		"order": aws.Int64Value(action.Order),
+	}
+
+	switch aws.StringValue(action.Type) {
+	case elbv2.ActionTypeEnumForward:
+		m["target_group_arn"] = aws.StringValue(action.TargetGroupArn)
+
+	case elbv2.ActionTypeEnumRedirect:
+		if config := action.RedirectConfig; config != nil {
+			m["redirect"] = []interface{}{
+				map[string]interface{}{
+					"host":        aws.StringValue(config.Host),
+					"path":        aws.StringValue(config.Path),
+					"port":        aws.StringValue(config.Port),
+					"protocol":    aws.StringValue(config.Protocol),
+					"query":       aws.StringValue(config.Query),
+					"status_code": aws.StringValue(config.StatusCode),
+				},
+			}
+		}
+
+	case elbv2.ActionTypeEnumFixedResponse:
+		if config := action.FixedResponseConfig; config != nil {
+			m["fixed_response"] = []interface{}{
+				map[string]interface{}{
+					"content_type": aws.StringValue(config.ContentType),
+					"message_body": aws.StringValue(config.MessageBody),
+					"status_code":  aws.StringValue(config.StatusCode),
+				},
+			}
+		}
+
+	case elbv2.ActionTypeEnumAuthenticateCognito:
+		if config := action.AuthenticateCognitoConfig; config != nil {
+			m["authenticate_cognito"] = []interface{}{
+				map[string]interface{}{
+					"authentication_request_extra_params": stringMap(config.AuthenticationRequestExtraParams),
+					"on_unauthenticated_request":          aws.StringValue(config.OnUnauthenticatedRequest),
+					"scope":                               aws.StringValue(config.Scope),
+					"session_cookie_name":                 aws.StringValue(config.SessionCookieName),
+					"session_timeout":                     aws.Int64Value(config.SessionTimeout),
+					"user_pool_arn":                       aws.StringValue(config.UserPoolArn),
+					"user_pool_client_id":                 aws.StringValue(config.UserPoolClientId),
+					"user_pool_domain":                    aws.StringValue(config.UserPoolDomain),
+				},
+			}
+		}
+
+	case elbv2.ActionTypeEnumAuthenticateOidc:
+		if config := action.AuthenticateOidcConfig; config != nil {
+			m["authenticate_oidc"] = []interface{}{
+				map[string]interface{}{
+					"authentication_request_extra_params": stringMap(config.AuthenticationRequestExtraParams),
+					"authorization_endpoint":              aws.StringValue(config.AuthorizationEndpoint),
+					"client_id":                           aws.StringValue(config.ClientId),
+					"client_secret":                       clientSecret,
+					"issuer":                              aws.StringValue(config.Issuer),
+					"on_unauthenticated_request":          aws.StringValue(config.OnUnauthenticatedRequest),
+					"scope":                               aws.StringValue(config.Scope),
+					"session_cookie_name":                 aws.StringValue(config.SessionCookieName),
+					"session_timeout":                     aws.Int64Value(config.SessionTimeout),
+					"token_endpoint":                      aws.StringValue(config.TokenEndpoint),
+					"user_info_endpoint":                  aws.StringValue(config.UserInfoEndpoint),
+				},
+			}
+		}
+	}
+
+	return m
+}
+
+// Conditions flattens rule conditions into condition blocks, in the order
+// given.
+func Conditions(conditions []*elbv2.RuleCondition) []interface{} {
+	result := make([]interface{}, len(conditions))
+	for i, condition := range conditions {
+		result[i] = Condition(condition)
+	}
+	return result
+}
+
+// Condition flattens a rule condition into a condition block. The deprecated
+// values attribute is set from the legacy values of the condition.
+func Condition(condition *elbv2.RuleCondition) map[string]interface{} {
+	m := map[string]interface{}{
+		"field": aws.StringValue(condition.Field),
+		// Deprecated: remove in next major version of provider
+		"values": aws.StringValueSlice(condition.Values),
+	}
+
+	if config := condition.HostHeaderConfig; config != nil {
+		m["host_header"] = []interface{}{
+			map[string]interface{}{
+				"values": aws.StringValueSlice(config.Values),
+			},
+		}
+	}
+
+	if config := condition.HttpHeaderConfig; config != nil {
+		m["http_header"] = []interface{}{
+			map[string]interface{}{
+				"http_header_name": aws.StringValue(config.HttpHeaderName),
+				"values":           aws.StringValueSlice(config.Values),
+			},
+		}
+	}
+
+	if config := condition.HttpRequestMethodConfig; config != nil {
+		m["http_request_method"] = []interface{}{
+			map[string]interface{}{
+				"values": aws.StringValueSlice(config.Values),
+			},
+		}
+	}
+
+	if config := condition.PathPatternConfig; config != nil {
+		m["path_pattern"] = []interface{}{
+			map[string]interface{}{
+				"values": aws.StringValueSlice(config.Values),
+			},
+		}
+	}
+
+	if config := condition.QueryStringConfig; config != nil {
+		values := make([]interface{}, len(config.Values))
+		for i, value := range config.Values {
+			values[i] = map[string]interface{}{
+				"key":   aws.StringValue(value.Key),
+				"value": aws.StringValue(value.Value),
+			}
+		}
+		m["query_string"] = []interface{}{
+			map[string]interface{}{
+				"values": values,
+			},
+		}
+	}
+
+	if config := condition.SourceIpConfig; config != nil {
+		m["source_ip"] = []interface{}{
+			map[string]interface{}{
+				"values": aws.StringValueSlice(config.Values),
+			},
+		}
+	}
+
+	return m
+}
+
+func stringMap(m map[string]*string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = aws.StringValue(v)
+	}
+	return result
+}
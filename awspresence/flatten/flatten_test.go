@@ -0,0 +1,109 @@
+package flatten
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// The fixtures in testdata are DescribeRules and DescribeListeners outputs,
+// as printed by the AWS CLI, and each has a .golden file holding its
+// flattened JSON. Run the tests with -update to rewrite the golden files.
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func testClientSecret(i int) string {
+	return fmt.Sprintf("client-secret-%d", i)
+}
+
+func TestRules(t *testing.T) {
+	testGoldenFiles(t, "rules_*.json", func(data []byte) (interface{}, error) {
+		var out elbv2.DescribeRulesOutput
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, err
+		}
+
+		result := make([]interface{}, len(out.Rules))
+		for i, rule := range out.Rules {
+			m, err := Rule(rule, testClientSecret)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = m
+		}
+		return result, nil
+	})
+}
+
+func TestListeners(t *testing.T) {
+	testGoldenFiles(t, "listeners_*.json", func(data []byte) (interface{}, error) {
+		var out elbv2.DescribeListenersOutput
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, err
+		}
+
+		result := make([]interface{}, len(out.Listeners))
+		for i, listener := range out.Listeners {
+			result[i] = Listener(listener, testClientSecret)
+		}
+		return result, nil
+	})
+}
+
+func TestRule_invalidPriority(t *testing.T) {
+	_, err := Rule(&elbv2.Rule{Priority: aws.String("first")}, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func testGoldenFiles(t *testing.T, pattern string, fn func([]byte) (interface{}, error)) {
+	files, err := filepath.Glob(filepath.Join("testdata", pattern))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no fixtures match testdata/%s", pattern)
+	}
+
+	for _, file := range files {
+		input, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := fn(input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", file, err)
+			continue
+		}
+		actual, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual = append(actual, '\n')
+
+		golden := strings.TrimSuffix(file, ".json") + ".golden"
+		if *update {
+			if err := ioutil.WriteFile(golden, actual, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("%s: flattened result differs from %s, got:\n%s", file, golden, actual)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// lbListenerRuleChanges is a set of changes to the rules of one listener,
+// applied together by applyLbListenerRuleChanges.
+type lbListenerRuleChanges struct {
+	// Create holds the priority, conditions and actions of each new rule.
+	Create []*elbv2.Rule
+	// Modify holds the ARN, conditions and actions of each changed rule.
+	Modify []*elbv2.Rule
+	// Delete holds the ARN of each rule to delete.
+	Delete []*elbv2.Rule
+}
+
+// Empty reports whether there is no change to apply.
+func (c *lbListenerRuleChanges) Empty() bool {
+	return len(c.Create) == 0 && len(c.Modify) == 0 && len(c.Delete) == 0
+}
+
+// applyLbListenerRuleChanges applies changes to the rules of a listener. Rules
+// are deleted first, so that their priorities can be reused by the rules that
+// are created last. It returns the ARNs of the rules it created, which are
+// also returned when a later change fails.
+func applyLbListenerRuleChanges(conn *elbv2.ELBV2, listenerArn string, changes *lbListenerRuleChanges) ([]string, error) {
+	for _, rule := range changes.Delete {
+		log.Printf("[DEBUG] Deleting LB Listener Rule %s", aws.StringValue(rule.RuleArn))
+		_, err := conn.DeleteRule(&elbv2.DeleteRuleInput{
+			RuleArn: rule.RuleArn,
+		})
+		if err != nil && !isLbNotFoundErr(err) {
+			return nil, fmt.Errorf("Error deleting LB Listener Rule %s: %s", aws.StringValue(rule.RuleArn), err)
+		}
+	}
+
+	for _, rule := range changes.Modify {
+		log.Printf("[DEBUG] Modifying LB Listener Rule %s", aws.StringValue(rule.RuleArn))
+		_, err := conn.ModifyRule(&elbv2.ModifyRuleInput{
+			RuleArn:    rule.RuleArn,
+			Actions:    rule.Actions,
+			Conditions: rule.Conditions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error modifying LB Listener Rule %s: %s", aws.StringValue(rule.RuleArn), err)
+		}
+	}
+
+	var created []string
+	for _, rule := range changes.Create {
+		priority, err := strconv.ParseInt(aws.StringValue(rule.Priority), 10, 64)
+		if err != nil {
+			return created, fmt.Errorf("Error parsing priority %q of LB Listener Rule: %s", aws.StringValue(rule.Priority), err)
+		}
+
+		log.Printf("[DEBUG] Creating LB Listener Rule at priority %d on %s", priority, listenerArn)
+		resp, err := conn.CreateRule(&elbv2.CreateRuleInput{
+			ListenerArn: aws.String(listenerArn),
+			Priority:    aws.Int64(priority),
+			Actions:     rule.Actions,
+			Conditions:  rule.Conditions,
+		})
+		if err != nil {
+			return created, fmt.Errorf("Error creating LB Listener Rule at priority %d: %s", priority, err)
+		}
+		for _, r := range resp.Rules {
+			created = append(created, aws.StringValue(r.RuleArn))
+		}
+	}
+
+	return created, nil
+}
@@ -0,0 +1,181 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// expandLbListenerRuleSourceIpPrefixLists resolves the prefix_list_ids of the
+// source-ip conditions to CIDRs and adds them to the values of the matching
+// elbConditions, which lbListenerRuleConditions expanded from conditions.
+// The resolved prefix lists are returned for source_ip_prefix_lists.
+func expandLbListenerRuleSourceIpPrefixLists(conn *ec2.EC2, conditions []interface{}, elbConditions []*elbv2.RuleCondition) ([]interface{}, error) {
+	var prefixListIds []string
+	seen := make(map[string]bool)
+	for _, condition := range conditions {
+		for _, id := range lbListenerRuleSourceIpPrefixListIds(condition.(map[string]interface{})) {
+			if !seen[id] {
+				seen[id] = true
+				prefixListIds = append(prefixListIds, id)
+			}
+		}
+	}
+	if len(prefixListIds) == 0 {
+		return []interface{}{}, nil
+	}
+	sort.Strings(prefixListIds)
+
+	versions, err := managedPrefixListVersions(conn, prefixListIds)
+	if err != nil {
+		return nil, fmt.Errorf("Error describing prefix lists: %s", err)
+	}
+
+	cidrs := make(map[string][]string)
+	prefixLists := make([]interface{}, 0, len(prefixListIds))
+	for _, id := range prefixListIds {
+		cidrs[id], err = managedPrefixListCidrs(conn, id, versions[id])
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving entries of prefix list %q: %s", id, err)
+		}
+
+		prefixLists = append(prefixLists, map[string]interface{}{
+			"prefix_list_id": id,
+			"version":        int(versions[id]),
+			"cidrs":          cidrs[id],
+		})
+	}
+
+	for i, condition := range conditions {
+		conditionMap := condition.(map[string]interface{})
+		ids := lbListenerRuleSourceIpPrefixListIds(conditionMap)
+		if len(ids) == 0 {
+			continue
+		}
+
+		values := aws.StringValueSlice(elbConditions[i].SourceIpConfig.Values)
+		elbConditions[i].SourceIpConfig.Values = aws.StringSlice(lbListenerRuleSourceIpValues(values, ids, cidrs))
+	}
+
+	return prefixLists, nil
+}
+
+// flattenLbListenerRuleSourceIp returns the source_ip block of a source-ip
+// condition with values. When the values are those of a condition in state
+// once its prefix lists are resolved to the CIDRs recorded in prefixLists,
+// that condition is returned as is, so resolved CIDRs do not show up as a
+// diff against the configuration.
+func flattenLbListenerRuleSourceIp(values []string, stateConditions []interface{}, prefixLists []interface{}) map[string]interface{} {
+	cidrs := make(map[string][]string)
+	for _, v := range prefixLists {
+		prefixList := v.(map[string]interface{})
+		cidrs[prefixList["prefix_list_id"].(string)] = aws.StringValueSlice(expandStringList(prefixList["cidrs"].([]interface{})))
+	}
+
+	for _, condition := range stateConditions {
+		conditionMap := condition.(map[string]interface{})
+		ids := lbListenerRuleSourceIpPrefixListIds(conditionMap)
+		if len(ids) == 0 {
+			continue
+		}
+
+		configured := aws.StringValueSlice(expandStringList(conditionMap["source_ip"].([]interface{})[0].(map[string]interface{})["values"].([]interface{})))
+		if stringSlicesEqualIgnoreOrder(lbListenerRuleSourceIpValues(configured, ids, cidrs), values) {
+			return map[string]interface{}{
+				"values":          configured,
+				"prefix_list_ids": ids,
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"values": values,
+	}
+}
+
+// lbListenerRuleSourceIpPrefixListsStale reports whether any of the prefix
+// lists recorded in prefixLists has a new version since it was resolved.
+func lbListenerRuleSourceIpPrefixListsStale(conn *ec2.EC2, prefixLists []interface{}) (bool, error) {
+	recorded := make(map[string]int64)
+	var prefixListIds []string
+	for _, v := range prefixLists {
+		prefixList := v.(map[string]interface{})
+		id := prefixList["prefix_list_id"].(string)
+		recorded[id] = int64(prefixList["version"].(int))
+		prefixListIds = append(prefixListIds, id)
+	}
+
+	versions, err := managedPrefixListVersions(conn, prefixListIds)
+	if err != nil {
+		return false, err
+	}
+
+	for id, version := range recorded {
+		if versions[id] != version {
+			log.Printf("[DEBUG] Prefix list %s changed from version %d to %d", id, version, versions[id])
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// lbListenerRuleSourceIpValues returns values followed by the CIDRs of the
+// prefix lists, without duplicates.
+func lbListenerRuleSourceIpValues(values []string, prefixListIds []string, cidrs map[string][]string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool)
+	add := func(v string) {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+
+	for _, v := range values {
+		add(v)
+	}
+	for _, id := range prefixListIds {
+		for _, v := range cidrs[id] {
+			add(v)
+		}
+	}
+
+	return result
+}
+
+func lbListenerRuleSourceIpPrefixListIds(conditionMap map[string]interface{}) []string {
+	if conditionMap["field"].(string) != "source-ip" {
+		return nil
+	}
+
+	sourceIp := conditionMap["source_ip"].([]interface{})
+	if len(sourceIp) == 0 || sourceIp[0] == nil {
+		return nil
+	}
+
+	return aws.StringValueSlice(expandStringList(sourceIp[0].(map[string]interface{})["prefix_list_ids"].([]interface{})))
+}
+
+func stringSlicesEqualIgnoreOrder(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[string]int)
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		counts[v]--
+		if counts[v] < 0 {
+			return false
+		}
+	}
+
+	return true
+}
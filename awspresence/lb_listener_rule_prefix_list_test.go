@@ -0,0 +1,62 @@
+package awspresence
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLbListenerRuleSourceIpValues(t *testing.T) {
+	cidrs := map[string][]string{
+		"pl-1": {"192.0.2.0/24", "198.51.100.0/24"},
+		"pl-2": {"198.51.100.0/24", "203.0.113.0/24"},
+	}
+
+	actual := lbListenerRuleSourceIpValues([]string{"10.0.0.0/8", "192.0.2.0/24"}, []string{"pl-1", "pl-2"}, cidrs)
+	expected := []string{"10.0.0.0/8", "192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestFlattenLbListenerRuleSourceIp(t *testing.T) {
+	prefixLists := []interface{}{
+		map[string]interface{}{
+			"prefix_list_id": "pl-1",
+			"version":        2,
+			"cidrs":          []interface{}{"192.0.2.0/24", "198.51.100.0/24"},
+		},
+	}
+	stateConditions := []interface{}{
+		map[string]interface{}{
+			"field": "path-pattern",
+		},
+		map[string]interface{}{
+			"field": "source-ip",
+			"source_ip": []interface{}{
+				map[string]interface{}{
+					"values":          []interface{}{"10.0.0.0/8"},
+					"prefix_list_ids": []interface{}{"pl-1"},
+				},
+			},
+		},
+	}
+
+	// The resolved CIDRs fold back into the prefix list.
+	actual := flattenLbListenerRuleSourceIp([]string{"198.51.100.0/24", "10.0.0.0/8", "192.0.2.0/24"}, stateConditions, prefixLists)
+	expected := map[string]interface{}{
+		"values":          []string{"10.0.0.0/8"},
+		"prefix_list_ids": []string{"pl-1"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	// Values changed outside of Terraform are returned as they are.
+	actual = flattenLbListenerRuleSourceIp([]string{"10.0.0.0/8", "192.0.2.0/24"}, stateConditions, prefixLists)
+	expected = map[string]interface{}{
+		"values": []string{"10.0.0.0/8", "192.0.2.0/24"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
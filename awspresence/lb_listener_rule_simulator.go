@@ -0,0 +1,142 @@
+package awspresence
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// lbListenerRuleWildcardFill replaces the * and ? wildcards of condition
+// values. A single character satisfies both, * matching any number of
+// characters and ? exactly one.
+const lbListenerRuleWildcardFill = "x"
+
+// lbListenerRuleRequestSample describes an HTTP request that matches every
+// condition of a listener rule.
+type lbListenerRuleRequestSample struct {
+	Method   string
+	Host     string
+	Path     string
+	Query    string
+	Headers  map[string]string
+	SourceIp string
+}
+
+// simulateLbListenerRuleRequest derives a request matching conditions. Each
+// condition is satisfied by its first value; fields without a condition keep
+// neutral defaults.
+func simulateLbListenerRuleRequest(conditions []*elbv2.RuleCondition) *lbListenerRuleRequestSample {
+	sample := &lbListenerRuleRequestSample{
+		Method:  "GET",
+		Path:    "/",
+		Headers: make(map[string]string),
+	}
+
+	var query []string
+	for _, condition := range conditions {
+		switch aws.StringValue(condition.Field) {
+		case "host-header":
+			values := condition.Values
+			if condition.HostHeaderConfig != nil {
+				values = condition.HostHeaderConfig.Values
+			}
+			if len(values) > 0 {
+				sample.Host = strings.ToLower(fillLbListenerRuleWildcards(aws.StringValue(values[0])))
+			}
+
+		case "path-pattern":
+			values := condition.Values
+			if condition.PathPatternConfig != nil {
+				values = condition.PathPatternConfig.Values
+			}
+			if len(values) > 0 {
+				path := fillLbListenerRuleWildcards(aws.StringValue(values[0]))
+				if !strings.HasPrefix(path, "/") {
+					path = "/" + path
+				}
+				sample.Path = path
+			}
+
+		case "http-header":
+			if c := condition.HttpHeaderConfig; c != nil && len(c.Values) > 0 {
+				sample.Headers[aws.StringValue(c.HttpHeaderName)] = fillLbListenerRuleWildcards(aws.StringValue(c.Values[0]))
+			}
+
+		case "http-request-method":
+			if c := condition.HttpRequestMethodConfig; c != nil && len(c.Values) > 0 {
+				sample.Method = aws.StringValue(c.Values[0])
+			}
+
+		case "query-string":
+			if c := condition.QueryStringConfig; c != nil && len(c.Values) > 0 {
+				pair := c.Values[0]
+				// A pair without a key matches the value of any parameter.
+				key := lbListenerRuleWildcardFill
+				if pair.Key != nil {
+					key = fillLbListenerRuleQueryWildcards(aws.StringValue(pair.Key))
+				}
+				value := fillLbListenerRuleQueryWildcards(aws.StringValue(pair.Value))
+				query = append(query, url.QueryEscape(key)+"="+url.QueryEscape(value))
+			}
+
+		case "source-ip":
+			if c := condition.SourceIpConfig; c != nil && len(c.Values) > 0 {
+				if ip, _, err := net.ParseCIDR(aws.StringValue(c.Values[0])); err == nil {
+					sample.SourceIp = ip.String()
+				}
+			}
+		}
+	}
+
+	sort.Strings(query)
+	sample.Query = strings.Join(query, "&")
+
+	return sample
+}
+
+func fillLbListenerRuleWildcards(v string) string {
+	return strings.NewReplacer("*", lbListenerRuleWildcardFill, "?", lbListenerRuleWildcardFill).Replace(v)
+}
+
+// fillLbListenerRuleQueryWildcards is fillLbListenerRuleWildcards for query
+// string patterns, in which a backslash escapes a literal * or ?.
+func fillLbListenerRuleQueryWildcards(v string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range v {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*' || r == '?':
+			b.WriteString(lbListenerRuleWildcardFill)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func flattenLbListenerRuleRequestSample(sample *lbListenerRuleRequestSample) []interface{} {
+	if sample == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"method":    sample.Method,
+			"host":      sample.Host,
+			"path":      sample.Path,
+			"query":     sample.Query,
+			"headers":   sample.Headers,
+			"source_ip": sample.SourceIp,
+		},
+	}
+}
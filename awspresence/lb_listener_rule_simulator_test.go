@@ -0,0 +1,107 @@
+package awspresence
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+func TestSimulateLbListenerRuleRequest(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Conditions []*elbv2.RuleCondition
+		Expected   *lbListenerRuleRequestSample
+	}{
+		{
+			Name: "no conditions",
+			Expected: &lbListenerRuleRequestSample{
+				Method:  "GET",
+				Path:    "/",
+				Headers: map[string]string{},
+			},
+		},
+		{
+			Name: "all fields",
+			Conditions: []*elbv2.RuleCondition{
+				{
+					Field: aws.String("host-header"),
+					HostHeaderConfig: &elbv2.HostHeaderConditionConfig{
+						Values: aws.StringSlice([]string{"*.Example.com", "example.org"}),
+					},
+				},
+				{
+					Field: aws.String("path-pattern"),
+					PathPatternConfig: &elbv2.PathPatternConditionConfig{
+						Values: aws.StringSlice([]string{"/img/*.jp?g"}),
+					},
+				},
+				{
+					Field: aws.String("http-header"),
+					HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+						HttpHeaderName: aws.String("User-Agent"),
+						Values:         aws.StringSlice([]string{"*Mobile*"}),
+					},
+				},
+				{
+					Field: aws.String("http-request-method"),
+					HttpRequestMethodConfig: &elbv2.HttpRequestMethodConditionConfig{
+						Values: aws.StringSlice([]string{"POST", "PUT"}),
+					},
+				},
+				{
+					Field: aws.String("query-string"),
+					QueryStringConfig: &elbv2.QueryStringConditionConfig{
+						Values: []*elbv2.QueryStringKeyValuePair{
+							{Key: aws.String("version"), Value: aws.String("v1 beta")},
+						},
+					},
+				},
+				{
+					Field: aws.String("query-string"),
+					QueryStringConfig: &elbv2.QueryStringConditionConfig{
+						Values: []*elbv2.QueryStringKeyValuePair{
+							{Value: aws.String("*lang\\?")},
+						},
+					},
+				},
+				{
+					Field: aws.String("source-ip"),
+					SourceIpConfig: &elbv2.SourceIpConditionConfig{
+						Values: aws.StringSlice([]string{"10.0.0.0/8"}),
+					},
+				},
+			},
+			Expected: &lbListenerRuleRequestSample{
+				Method:   "POST",
+				Host:     "x.example.com",
+				Path:     "/img/x.jpxg",
+				Query:    "version=v1+beta&x=xlang%3F",
+				Headers:  map[string]string{"User-Agent": "xMobilex"},
+				SourceIp: "10.0.0.0",
+			},
+		},
+		{
+			Name: "deprecated values and relative path",
+			Conditions: []*elbv2.RuleCondition{
+				{
+					Field:  aws.String("path-pattern"),
+					Values: aws.StringSlice([]string{"*.css"}),
+				},
+			},
+			Expected: &lbListenerRuleRequestSample{
+				Method:  "GET",
+				Path:    "/x.css",
+				Headers: map[string]string{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		actual := simulateLbListenerRuleRequest(tc.Conditions)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("%s: expected %#v, got %#v", tc.Name, tc.Expected, actual)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package awspresence
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// lbResourceOp identifies an operation of an LB resource type, e.g. the
+// creates of awspresence_lb_listener_rule.
+type lbResourceOp struct {
+	Resource  string
+	Operation string
+}
+
+// lbResourceOpStats holds the counters recorded for a single lbResourceOp.
+type lbResourceOpStats struct {
+	Count   int
+	Errors  int
+	Seconds float64
+}
+
+var lbMetrics = struct {
+	sync.Mutex
+	path string
+	ops  map[lbResourceOp]*lbResourceOpStats
+}{ops: make(map[lbResourceOp]*lbResourceOpStats)}
+
+// isLbMetricsResource reports whether the resource registered under name is
+// one of the LB resources whose operations are recorded for the metrics file.
+func isLbMetricsResource(name string) bool {
+	return strings.HasPrefix(name, "awspresence_lb") || strings.HasPrefix(name, "awspresence_alb")
+}
+
+// addLbResourceMetrics records the creates, updates and deletes of the
+// resource registered under name.
+func addLbResourceMetrics(name string, r *schema.Resource) {
+	r.Create = lbResourceMetricsFunc(name, "create", r.Create)
+	r.Update = lbResourceMetricsFunc(name, "update", r.Update)
+	r.Delete = lbResourceMetricsFunc(name, "delete", r.Delete)
+}
+
+func lbResourceMetricsFunc(name, operation string, fn func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if fn == nil {
+		return nil
+	}
+
+	return func(d *schema.ResourceData, meta interface{}) error {
+		start := time.Now()
+		err := fn(d, meta)
+		recordLbResourceOp(lbResourceOp{Resource: name, Operation: operation}, time.Since(start), err)
+		return err
+	}
+}
+
+func recordLbResourceOp(op lbResourceOp, duration time.Duration, err error) {
+	lbMetrics.Lock()
+	defer lbMetrics.Unlock()
+
+	if lbMetrics.path == "" {
+		return
+	}
+
+	stats, ok := lbMetrics.ops[op]
+	if !ok {
+		stats = &lbResourceOpStats{}
+		lbMetrics.ops[op] = stats
+	}
+	stats.Count++
+	if err != nil {
+		stats.Errors++
+	}
+	stats.Seconds += duration.Seconds()
+}
+
+// enableLbMetricsFile turns on the recording of LB resource operations, to be
+// written to path at the end of the Terraform run, or as soon as stop is done
+// when the run is interrupted.
+func enableLbMetricsFile(path string, stop context.Context) {
+	lbMetrics.Lock()
+	lbMetrics.path = path
+	lbMetrics.Unlock()
+
+	go func() {
+		<-stop.Done()
+		WriteLbMetricsFile()
+	}()
+}
+
+// WriteLbMetricsFile writes the LB resource operations, and the ELB API calls
+// they made, to the file set in the metrics_file provider argument, in the
+// Prometheus text format. Provider processes that only planned or refreshed do
+// not write it, so they never overwrite the metrics of an apply. It is called
+// once the plugin server shuts down, at the end of a Terraform run.
+func WriteLbMetricsFile() {
+	lbMetrics.Lock()
+	path := lbMetrics.path
+	ops := make(map[lbResourceOp]*lbResourceOpStats, len(lbMetrics.ops))
+	for op, stats := range lbMetrics.ops {
+		s := *stats
+		ops[op] = &s
+	}
+	lbMetrics.Unlock()
+
+	if path == "" || len(ops) == 0 {
+		return
+	}
+
+	apiCallMetrics.Lock()
+	apiOps := make(map[string]*apiCallStats)
+	for op, stats := range apiCallMetrics.ops {
+		if strings.HasPrefix(op, elbv2.ServiceName+":") {
+			s := *stats
+			apiOps[strings.TrimPrefix(op, elbv2.ServiceName+":")] = &s
+		}
+	}
+	apiCallMetrics.Unlock()
+
+	if err := writeLbMetricsFile(path, []byte(formatLbMetrics(ops, apiOps))); err != nil {
+		log.Printf("[WARN] Error writing metrics file %s: %s", path, err)
+		return
+	}
+	log.Printf("[INFO] Wrote LB metrics to %s", path)
+}
+
+// writeLbMetricsFile replaces the file at path with data. It writes to a
+// temporary file of its own in the same directory first, and renames it over
+// path, so that a pipeline reading the file never sees it half written and
+// concurrent writers never share a temporary file.
+func writeLbMetricsFile(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// formatLbMetrics formats the LB resource operations, and the ELB API
+// operations they called, in the Prometheus text format.
+func formatLbMetrics(ops map[lbResourceOp]*lbResourceOpStats, apiOps map[string]*apiCallStats) string {
+	keys := make([]lbResourceOp, 0, len(ops))
+	for op := range ops {
+		keys = append(keys, op)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Resource != keys[j].Resource {
+			return keys[i].Resource < keys[j].Resource
+		}
+		return keys[i].Operation < keys[j].Operation
+	})
+
+	apiKeys := make([]string, 0, len(apiOps))
+	for op := range apiOps {
+		apiKeys = append(apiKeys, op)
+	}
+	sort.Strings(apiKeys)
+
+	var b strings.Builder
+	writeHeader := func(name, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	}
+
+	writeHeader("awspresence_lb_resource_operations_total", "Creates, updates and deletes of LB resources.")
+	for _, op := range keys {
+		fmt.Fprintf(&b, "awspresence_lb_resource_operations_total{resource=%q,operation=%q} %d\n", op.Resource, op.Operation, ops[op].Count)
+	}
+	writeHeader("awspresence_lb_resource_operation_errors_total", "Failed creates, updates and deletes of LB resources.")
+	for _, op := range keys {
+		fmt.Fprintf(&b, "awspresence_lb_resource_operation_errors_total{resource=%q,operation=%q} %d\n", op.Resource, op.Operation, ops[op].Errors)
+	}
+	writeHeader("awspresence_lb_resource_operation_seconds_total", "Time spent in creates, updates and deletes of LB resources.")
+	for _, op := range keys {
+		fmt.Fprintf(&b, "awspresence_lb_resource_operation_seconds_total{resource=%q,operation=%q} %.3f\n", op.Resource, op.Operation, ops[op].Seconds)
+	}
+
+	writeHeader("awspresence_lb_api_requests_total", "Requests made to the ELB API.")
+	for _, op := range apiKeys {
+		fmt.Fprintf(&b, "awspresence_lb_api_requests_total{operation=%q} %d\n", op, apiOps[op].Requests)
+	}
+	writeHeader("awspresence_lb_api_retries_total", "Retried attempts of requests made to the ELB API.")
+	for _, op := range apiKeys {
+		fmt.Fprintf(&b, "awspresence_lb_api_retries_total{operation=%q} %d\n", op, apiOps[op].Attempts-apiOps[op].Requests)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package awspresence
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatLbMetrics(t *testing.T) {
+	ops := map[lbResourceOp]*lbResourceOpStats{
+		{Resource: "awspresence_lb_listener_rule", Operation: "update"}: {Count: 1, Seconds: 0.25},
+		{Resource: "awspresence_lb_listener_rule", Operation: "create"}: {Count: 3, Errors: 1, Seconds: 1.5},
+		{Resource: "awspresence_lb", Operation: "delete"}:               {Count: 1, Seconds: 180},
+	}
+	apiOps := map[string]*apiCallStats{
+		"DescribeRules": {Requests: 4, Attempts: 4},
+		"CreateRule":    {Requests: 3, Attempts: 5, Throttles: 2, Errors: 1},
+	}
+
+	expected := `# HELP awspresence_lb_resource_operations_total Creates, updates and deletes of LB resources.
+# TYPE awspresence_lb_resource_operations_total counter
+awspresence_lb_resource_operations_total{resource="awspresence_lb",operation="delete"} 1
+awspresence_lb_resource_operations_total{resource="awspresence_lb_listener_rule",operation="create"} 3
+awspresence_lb_resource_operations_total{resource="awspresence_lb_listener_rule",operation="update"} 1
+# HELP awspresence_lb_resource_operation_errors_total Failed creates, updates and deletes of LB resources.
+# TYPE awspresence_lb_resource_operation_errors_total counter
+awspresence_lb_resource_operation_errors_total{resource="awspresence_lb",operation="delete"} 0
+awspresence_lb_resource_operation_errors_total{resource="awspresence_lb_listener_rule",operation="create"} 1
+awspresence_lb_resource_operation_errors_total{resource="awspresence_lb_listener_rule",operation="update"} 0
+# HELP awspresence_lb_resource_operation_seconds_total Time spent in creates, updates and deletes of LB resources.
+# TYPE awspresence_lb_resource_operation_seconds_total counter
+awspresence_lb_resource_operation_seconds_total{resource="awspresence_lb",operation="delete"} 180.000
+awspresence_lb_resource_operation_seconds_total{resource="awspresence_lb_listener_rule",operation="create"} 1.500
+awspresence_lb_resource_operation_seconds_total{resource="awspresence_lb_listener_rule",operation="update"} 0.250
+# HELP awspresence_lb_api_requests_total Requests made to the ELB API.
+# TYPE awspresence_lb_api_requests_total counter
+awspresence_lb_api_requests_total{operation="CreateRule"} 3
+awspresence_lb_api_requests_total{operation="DescribeRules"} 4
+# HELP awspresence_lb_api_retries_total Retried attempts of requests made to the ELB API.
+# TYPE awspresence_lb_api_retries_total counter
+awspresence_lb_api_retries_total{operation="CreateRule"} 2
+awspresence_lb_api_retries_total{operation="DescribeRules"} 0
+`
+	if got := formatLbMetrics(ops, apiOps); got != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestIsLbMetricsResource(t *testing.T) {
+	cases := map[string]bool{
+		"awspresence_lb":                         true,
+		"awspresence_alb_listener_rule":          true,
+		"awspresence_lb_listener_rule":           true,
+		"awspresence_acm_certificate":            false,
+		"awspresence_iam_server_certificate":     false,
+		"awspresence_lb_target_group_attachment": true,
+	}
+
+	for name, expected := range cases {
+		if got := isLbMetricsResource(name); got != expected {
+			t.Errorf("%s: expected %t, got %t", name, expected, got)
+		}
+	}
+}
+
+func TestWriteLbMetricsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lb-metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "lb.prom")
+
+	for _, data := range []string{"first\n", "second\n"} {
+		if err := writeLbMetricsFile(path, []byte(data)); err != nil {
+			t.Fatalf("error writing metrics file: %s", err)
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != data {
+			t.Fatalf("expected %q, got %q", data, got)
+		}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "lb.prom" {
+		t.Fatalf("expected only lb.prom to be left behind, got %d files", len(files))
+	}
+	if mode := files[0].Mode().Perm(); mode != 0644 {
+		t.Fatalf("expected mode 0644, got %o", mode)
+	}
+}
@@ -0,0 +1,141 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Registered targets cannot be tagged, so the tags of a target group
+// attachment are kept on its target group, under a key namespaced by the
+// target: "awspresence:attachment/<target id>[:<port>]/<key>". The target
+// group resources ignore tags under the prefix.
+const lbTargetGroupAttachmentTagPrefix = "awspresence:attachment/"
+
+const (
+	elbv2TagKeyMaxLength   = 128
+	elbv2TagValueMaxLength = 256
+)
+
+// lbTargetGroupAttachmentTagNamespace returns the prefix of the target group
+// tag keys holding the tags of the attachment of targetId on port.
+func lbTargetGroupAttachmentTagNamespace(targetId string, port int) string {
+	if port != 0 {
+		return fmt.Sprintf("%s%s:%d/", lbTargetGroupAttachmentTagPrefix, targetId, port)
+	}
+	return fmt.Sprintf("%s%s/", lbTargetGroupAttachmentTagPrefix, targetId)
+}
+
+// expandLbTargetGroupAttachmentTags returns the target group tags holding the
+// attachment tags in m under namespace.
+func expandLbTargetGroupAttachmentTags(namespace string, m map[string]interface{}) ([]*elbv2.Tag, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := make([]*elbv2.Tag, 0, len(keys))
+	for _, k := range keys {
+		key := namespace + k
+		if len(key) > elbv2TagKeyMaxLength {
+			return nil, fmt.Errorf("tag %q is stored on the target group as %q, which is longer than %d characters", k, key, elbv2TagKeyMaxLength)
+		}
+		value := m[k].(string)
+		if len(value) > elbv2TagValueMaxLength {
+			return nil, fmt.Errorf("value of tag %q is longer than %d characters", k, elbv2TagValueMaxLength)
+		}
+
+		tags = append(tags, &elbv2.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	return tags, nil
+}
+
+// flattenLbTargetGroupAttachmentTags returns the attachment tags held under
+// namespace in the target group tags ts.
+func flattenLbTargetGroupAttachmentTags(namespace string, ts []*elbv2.Tag) map[string]string {
+	result := make(map[string]string)
+	for _, t := range ts {
+		key := aws.StringValue(t.Key)
+		if strings.HasPrefix(key, namespace) {
+			result[strings.TrimPrefix(key, namespace)] = aws.StringValue(t.Value)
+		}
+	}
+
+	return result
+}
+
+// readLbTargetGroupAttachmentTags returns the attachment tags held under
+// namespace on the target group.
+func readLbTargetGroupAttachmentTags(conn *elbv2.ELBV2, targetGroupArn, namespace string) (map[string]string, error) {
+	resp, err := conn.DescribeTags(&elbv2.DescribeTagsInput{
+		ResourceArns: []*string{aws.String(targetGroupArn)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range resp.TagDescriptions {
+		if aws.StringValue(t.ResourceArn) == targetGroupArn {
+			return flattenLbTargetGroupAttachmentTags(namespace, t.Tags), nil
+		}
+	}
+
+	return map[string]string{}, nil
+}
+
+// setLbTargetGroupAttachmentTags updates the attachment tags held under
+// namespace on the target group from the change of the tags argument.
+func setLbTargetGroupAttachmentTags(conn *elbv2.ELBV2, d *schema.ResourceData, namespace string) error {
+	if !d.HasChange("tags") {
+		return nil
+	}
+
+	oraw, nraw := d.GetChange("tags")
+	o := oraw.(map[string]interface{})
+	n := nraw.(map[string]interface{})
+	targetGroupArn := d.Get("target_group_arn").(string)
+
+	var remove []*string
+	for k, v := range o {
+		if nv, ok := n[k]; !ok || nv != v {
+			remove = append(remove, aws.String(namespace+k))
+		}
+	}
+	if len(remove) > 0 {
+		log.Printf("[DEBUG] Removing attachment tags: %s from %s", aws.StringValueSlice(remove), targetGroupArn)
+		_, err := conn.RemoveTags(&elbv2.RemoveTagsInput{
+			ResourceArns: []*string{aws.String(targetGroupArn)},
+			TagKeys:      remove,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	create, err := expandLbTargetGroupAttachmentTags(namespace, n)
+	if err != nil {
+		return err
+	}
+	if len(create) > 0 {
+		log.Printf("[DEBUG] Creating attachment tags: %s for %s", create, targetGroupArn)
+		_, err := conn.AddTags(&elbv2.AddTagsInput{
+			ResourceArns: []*string{aws.String(targetGroupArn)},
+			Tags:         create,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package awspresence
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+func TestLbTargetGroupAttachmentTagNamespace(t *testing.T) {
+	if actual, expected := lbTargetGroupAttachmentTagNamespace("i-0123456789abcdef0", 80), "awspresence:attachment/i-0123456789abcdef0:80/"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+	if actual, expected := lbTargetGroupAttachmentTagNamespace("10.0.1.10", 0), "awspresence:attachment/10.0.1.10/"; actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestExpandLbTargetGroupAttachmentTags(t *testing.T) {
+	namespace := lbTargetGroupAttachmentTagNamespace("i-0123456789abcdef0", 80)
+
+	tags, err := expandLbTargetGroupAttachmentTags(namespace, map[string]interface{}{
+		"Purpose": "canary",
+		"Owner":   "payments",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []*elbv2.Tag{
+		{Key: aws.String(namespace + "Owner"), Value: aws.String("payments")},
+		{Key: aws.String(namespace + "Purpose"), Value: aws.String("canary")},
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("expected %s, got %s", expected, tags)
+	}
+
+	if _, err := expandLbTargetGroupAttachmentTags(namespace, map[string]interface{}{strings.Repeat("k", 100): "v"}); err == nil {
+		t.Fatal("expected an error for a key longer than 128 characters once namespaced")
+	}
+	if _, err := expandLbTargetGroupAttachmentTags(namespace, map[string]interface{}{"Owner": strings.Repeat("v", 257)}); err == nil {
+		t.Fatal("expected an error for a value longer than 256 characters")
+	}
+}
+
+func TestFlattenLbTargetGroupAttachmentTags(t *testing.T) {
+	tags := []*elbv2.Tag{
+		{Key: aws.String("Name"), Value: aws.String("web")},
+		{Key: aws.String("awspresence:attachment/i-0123456789abcdef0:80/Owner"), Value: aws.String("payments")},
+		{Key: aws.String("awspresence:attachment/i-0123456789abcdef0:8080/Owner"), Value: aws.String("checkout")},
+	}
+
+	actual := flattenLbTargetGroupAttachmentTags(lbTargetGroupAttachmentTagNamespace("i-0123456789abcdef0", 80), tags)
+	if expected := map[string]string{"Owner": "payments"}; !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	if actual, expected := tagsToMapELBv2(tags), map[string]string{"Name": "web"}; !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected target group tags %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,100 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+)
+
+// The provider can run against LocalStack, which emulates most of the ELBv2
+// API but not all of it. In LocalStack mode every service endpoint points at
+// LocalStack, and the features below are refused at plan time instead of
+// failing or misbehaving on apply. Acceptance tests use the same registry to
+// skip the tests that depend on them.
+
+const (
+	localstackFeatureAuthenticateCognito = "authenticate-cognito"
+	localstackFeatureAuthenticateOidc    = "authenticate-oidc"
+	localstackFeatureLbNetworkInterfaces = "lb-network-interfaces"
+	localstackFeatureManagedPrefixLists  = "managed-prefix-lists"
+
+	// localstackAccessKey is the access key LocalStack accepts when no
+	// credentials are configured.
+	localstackAccessKey = "test"
+)
+
+// localstackUnsupportedFeatures maps the features LocalStack does not support
+// to the reason shown to the user.
+var localstackUnsupportedFeatures = map[string]string{
+	localstackFeatureAuthenticateCognito: "authenticate-cognito actions need Cognito user pools, which LocalStack does not provide",
+	localstackFeatureAuthenticateOidc:    "authenticate-oidc actions are accepted but never enforced by LocalStack",
+	localstackFeatureLbNetworkInterfaces: "LocalStack does not create network interfaces for load balancers",
+	localstackFeatureManagedPrefixLists:  "LocalStack does not emulate the EC2 managed prefix list APIs",
+}
+
+// applyLocalstack points every service endpoint not set explicitly at
+// endpoint, and turns off the checks LocalStack cannot answer.
+func (c *Config) applyLocalstack(endpoint string) {
+	log.Printf("[INFO] Running against LocalStack at %s", endpoint)
+
+	for _, name := range endpointServiceNames {
+		if c.Endpoints[name] == "" {
+			c.Endpoints[name] = endpoint
+		}
+	}
+
+	c.SkipCredsValidation = true
+	c.SkipGetEC2Platforms = true
+	c.SkipMetadataApiCheck = true
+	c.SkipRegionValidation = true
+	c.SkipRequestingAccountId = true
+	c.S3ForcePathStyle = true
+
+	if c.AccessKey == "" && c.Profile == "" {
+		c.AccessKey = localstackAccessKey
+		c.SecretKey = localstackAccessKey
+	}
+}
+
+// checkLocalstackFeature returns an error when the client runs against
+// LocalStack, which does not support feature.
+func (c *AWSClient) checkLocalstackFeature(feature string) error {
+	if c == nil || !c.localstack {
+		return nil
+	}
+	if reason, ok := localstackUnsupportedFeatures[feature]; ok {
+		return fmt.Errorf("%s is not supported when running against LocalStack: %s", feature, reason)
+	}
+	return nil
+}
+
+// checkLocalstackActions refuses the listener actions of a type LocalStack
+// does not support.
+func (c *AWSClient) checkLocalstackActions(actions []interface{}) error {
+	for _, action := range actions {
+		actionMap, ok := action.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if actionType, _ := actionMap["type"].(string); lbAuthenticateActionTypes[actionType] {
+			if err := c.checkLocalstackFeature(actionType); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkLocalstackConditions refuses the listener rule conditions that match
+// prefix lists, which LocalStack cannot resolve.
+func (c *AWSClient) checkLocalstackConditions(conditions []interface{}) error {
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if len(lbListenerRuleSourceIpPrefixListIds(conditionMap)) > 0 {
+			return c.checkLocalstackFeature(localstackFeatureManagedPrefixLists)
+		}
+	}
+	return nil
+}
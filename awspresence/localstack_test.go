@@ -0,0 +1,92 @@
+package awspresence
+
+import (
+	"testing"
+)
+
+func TestConfigApplyLocalstack(t *testing.T) {
+	config := &Config{
+		Endpoints: map[string]string{
+			"elb": "http://elb.example.com",
+		},
+	}
+	config.applyLocalstack("http://localhost:4566")
+
+	if got := config.Endpoints["elb"]; got != "http://elb.example.com" {
+		t.Errorf("expected explicit elb endpoint to be kept, got %q", got)
+	}
+	for _, name := range []string{"ec2", "iam", "secretsmanager", "sts"} {
+		if got := config.Endpoints[name]; got != "http://localhost:4566" {
+			t.Errorf("expected %s endpoint to point at LocalStack, got %q", name, got)
+		}
+	}
+	if !config.SkipCredsValidation || !config.SkipRequestingAccountId || !config.SkipMetadataApiCheck {
+		t.Errorf("expected the checks LocalStack cannot answer to be skipped")
+	}
+	if config.AccessKey != localstackAccessKey || config.SecretKey != localstackAccessKey {
+		t.Errorf("expected LocalStack credentials, got %q/%q", config.AccessKey, config.SecretKey)
+	}
+
+	config = &Config{
+		Endpoints: map[string]string{},
+		Profile:   "localstack",
+	}
+	config.applyLocalstack("http://localhost:4566")
+	if config.AccessKey != "" {
+		t.Errorf("expected profile credentials to be kept, got access key %q", config.AccessKey)
+	}
+}
+
+func TestAWSClientCheckLocalstackActions(t *testing.T) {
+	actions := []interface{}{
+		map[string]interface{}{"type": "authenticate-oidc"},
+		map[string]interface{}{"type": "forward"},
+	}
+
+	client := &AWSClient{}
+	if err := client.checkLocalstackActions(actions); err != nil {
+		t.Errorf("expected no error outside of LocalStack, got %s", err)
+	}
+
+	client.localstack = true
+	if err := client.checkLocalstackActions(actions); err == nil {
+		t.Errorf("expected an error for authenticate-oidc against LocalStack")
+	}
+	if err := client.checkLocalstackActions(actions[1:]); err != nil {
+		t.Errorf("expected no error for forward against LocalStack, got %s", err)
+	}
+	if err := client.checkLocalstackFeature("forward"); err != nil {
+		t.Errorf("expected no error for a supported feature, got %s", err)
+	}
+}
+
+func TestAWSClientCheckLocalstackConditions(t *testing.T) {
+	sourceIp := func(prefixListIds ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"field": "source-ip",
+			"source_ip": []interface{}{
+				map[string]interface{}{
+					"values":          []interface{}{"192.0.2.0/24"},
+					"prefix_list_ids": prefixListIds,
+				},
+			},
+		}
+	}
+	conditions := []interface{}{
+		map[string]interface{}{"field": "path-pattern"},
+		sourceIp("pl-0123456789abcdef0"),
+	}
+
+	client := &AWSClient{}
+	if err := client.checkLocalstackConditions(conditions); err != nil {
+		t.Errorf("expected no error outside of LocalStack, got %s", err)
+	}
+
+	client.localstack = true
+	if err := client.checkLocalstackConditions(conditions); err == nil {
+		t.Errorf("expected an error for prefix_list_ids against LocalStack")
+	}
+	if err := client.checkLocalstackConditions([]interface{}{sourceIp()}); err != nil {
+		t.Errorf("expected no error for source_ip values against LocalStack, got %s", err)
+	}
+}
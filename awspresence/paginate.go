@@ -0,0 +1,122 @@
+package awspresence
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	paginateMaxThrottleRetries = 8
+	paginateBaseBackoff        = 500 * time.Millisecond
+	paginateMaxBackoff         = 20 * time.Second
+)
+
+// paginateSleep is swapped out in tests so backoff does not slow them down.
+var paginateSleep = time.Sleep
+
+// paginateThrottleCodes are the error codes the ELB, ELBv2 and EC2 APIs use to
+// signal request throttling.
+var paginateThrottleCodes = []string{
+	"Throttling",
+	"ThrottlingException",
+	"RequestLimitExceeded",
+}
+
+// paginateCallStats holds the counters recorded for a single API operation.
+type paginateCallStats struct {
+	Calls     int
+	Pages     int
+	Throttles int
+	Duration  time.Duration
+}
+
+var paginateMetrics = struct {
+	sync.Mutex
+	ops map[string]*paginateCallStats
+}{ops: make(map[string]*paginateCallStats)}
+
+// paginate calls fn once per page, passing the Marker/NextToken returned by the
+// previous page (nil for the first page), until fn returns a nil or empty
+// marker. Throttled calls are retried with exponential backoff. Per-call
+// counters are recorded under op and logged once the last page is read.
+func paginate(op string, fn func(marker *string) (*string, error)) error {
+	var marker *string
+	stats := paginateCallStats{}
+	start := time.Now()
+
+	defer func() {
+		stats.Duration = time.Since(start)
+		recordPaginateStats(op, stats)
+		log.Printf("[DEBUG] %s: %d page(s), %d call(s), %d throttled, %s",
+			op, stats.Pages, stats.Calls, stats.Throttles, stats.Duration)
+	}()
+
+	for {
+		next, err := paginatePage(fn, marker, &stats)
+		if err != nil {
+			return err
+		}
+		stats.Pages++
+
+		if next == nil || *next == "" {
+			return nil
+		}
+		marker = next
+	}
+}
+
+func paginatePage(fn func(marker *string) (*string, error), marker *string, stats *paginateCallStats) (*string, error) {
+	backoff := paginateBaseBackoff
+
+	for attempt := 0; ; attempt++ {
+		stats.Calls++
+		next, err := fn(marker)
+		if err == nil {
+			return next, nil
+		}
+
+		if !isPaginateThrottleError(err) || attempt >= paginateMaxThrottleRetries {
+			return nil, err
+		}
+
+		stats.Throttles++
+		log.Printf("[DEBUG] Request throttled, retrying page in %s: %s", backoff, err)
+		paginateSleep(backoff)
+
+		backoff *= 2
+		if backoff > paginateMaxBackoff {
+			backoff = paginateMaxBackoff
+		}
+	}
+}
+
+func isPaginateThrottleError(err error) bool {
+	return isAWSErrCode(err, paginateThrottleCodes...)
+}
+
+func recordPaginateStats(op string, stats paginateCallStats) {
+	paginateMetrics.Lock()
+	defer paginateMetrics.Unlock()
+
+	total, ok := paginateMetrics.ops[op]
+	if !ok {
+		total = &paginateCallStats{}
+		paginateMetrics.ops[op] = total
+	}
+	total.Calls += stats.Calls
+	total.Pages += stats.Pages
+	total.Throttles += stats.Throttles
+	total.Duration += stats.Duration
+}
+
+// paginateStats returns a snapshot of the counters recorded for op.
+func paginateStats(op string) paginateCallStats {
+	paginateMetrics.Lock()
+	defer paginateMetrics.Unlock()
+
+	if stats, ok := paginateMetrics.ops[op]; ok {
+		return *stats
+	}
+	return paginateCallStats{}
+}
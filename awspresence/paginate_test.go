@@ -0,0 +1,112 @@
+package awspresence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestPaginate_followsMarkers(t *testing.T) {
+	markers := []*string{aws.String("page-2"), aws.String("page-3"), nil}
+	var seen []string
+
+	err := paginate("TestPaginate_followsMarkers", func(marker *string) (*string, error) {
+		seen = append(seen, aws.StringValue(marker))
+		next := markers[0]
+		markers = markers[1:]
+		return next, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"", "page-2", "page-3"}
+	if len(seen) != len(expected) {
+		t.Fatalf("expected %d pages, got %d: %v", len(expected), len(seen), seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Fatalf("page %d: expected marker %q, got %q", i, expected[i], seen[i])
+		}
+	}
+
+	stats := paginateStats("TestPaginate_followsMarkers")
+	if stats.Pages != 3 || stats.Calls != 3 || stats.Throttles != 0 {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+}
+
+func TestPaginate_emptyMarkerStops(t *testing.T) {
+	calls := 0
+	err := paginate("TestPaginate_emptyMarkerStops", func(marker *string) (*string, error) {
+		calls++
+		return aws.String(""), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestPaginate_retriesThrottling(t *testing.T) {
+	var slept []time.Duration
+	paginateSleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { paginateSleep = time.Sleep }()
+
+	calls := 0
+	err := paginate("TestPaginate_retriesThrottling", func(marker *string) (*string, error) {
+		calls++
+		if calls <= 2 {
+			return nil, awserr.New("Throttling", "Rate exceeded", nil)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(slept) != 2 || slept[0] != paginateBaseBackoff || slept[1] != 2*paginateBaseBackoff {
+		t.Fatalf("unexpected backoff: %v", slept)
+	}
+
+	stats := paginateStats("TestPaginate_retriesThrottling")
+	if stats.Pages != 1 || stats.Calls != 3 || stats.Throttles != 2 {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+}
+
+func TestPaginate_throttleRetriesExhausted(t *testing.T) {
+	paginateSleep = func(time.Duration) {}
+	defer func() { paginateSleep = time.Sleep }()
+
+	calls := 0
+	err := paginate("TestPaginate_throttleRetriesExhausted", func(marker *string) (*string, error) {
+		calls++
+		return nil, awserr.New("ThrottlingException", "Rate exceeded", nil)
+	})
+	if !isAWSErr(err, "ThrottlingException", "") {
+		t.Fatalf("expected throttling error, got %v", err)
+	}
+	if calls != paginateMaxThrottleRetries+1 {
+		t.Fatalf("expected %d calls, got %d", paginateMaxThrottleRetries+1, calls)
+	}
+}
+
+func TestPaginate_otherErrorsNotRetried(t *testing.T) {
+	calls := 0
+	err := paginate("TestPaginate_otherErrorsNotRetried", func(marker *string) (*string, error) {
+		calls++
+		return nil, errors.New("boom")
+	})
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("expected boom error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/terraform/helper/mutexkv"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 	homedir "github.com/mitchellh/go-homedir"
 )
@@ -15,7 +16,7 @@ func Provider() terraform.ResourceProvider {
 	// TODO: Move the configuration to this, requires validation
 
 	// The actual provider
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"access_key": {
 				Type:        schema.TypeString,
@@ -72,6 +73,52 @@ func Provider() terraform.ResourceProvider {
 				Description: descriptions["max_retries"],
 			},
 
+			"retry_mode": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AWS_RETRY_MODE", retryModeStandard),
+				Description: descriptions["retry_mode"],
+				ValidateFunc: validation.StringInSlice([]string{
+					retryModeStandard,
+					retryModeAdaptive,
+				}, false),
+			},
+
+			"api_call_summary": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: descriptions["api_call_summary"],
+			},
+
+			"metrics_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AWSPRESENCE_METRICS_FILE", ""),
+				Description: descriptions["metrics_file"],
+			},
+
+			"localstack_endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("LOCALSTACK_ENDPOINT", ""),
+				Description: descriptions["localstack_endpoint"],
+			},
+
+			"user_agent_module": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AWSPRESENCE_MODULE", ""),
+				Description: descriptions["user_agent_module"],
+			},
+
+			"user_agent_workspace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_WORKSPACE", ""),
+				Description: descriptions["user_agent_workspace"],
+			},
+
 			"allowed_account_ids": {
 				Type:          schema.TypeSet,
 				Elem:          &schema.Schema{Type: schema.TypeString},
@@ -142,34 +189,74 @@ func Provider() terraform.ResourceProvider {
 
 		DataSourcesMap: map[string]*schema.Resource{
 			// Adding the Aliases for the ALB -> LB Rename
-			"awspresence_lb":               dataSourceAwsLb(),
-			"awspresence_alb":              dataSourceAwsLb(),
-			"awspresence_lb_listener":      dataSourceAwsLbListener(),
-			"awspresence_alb_listener":     dataSourceAwsLbListener(),
-			"awspresence_lb_target_group":  dataSourceAwsLbTargetGroup(),
-			"awspresence_alb_target_group": dataSourceAwsLbTargetGroup(),
+			"awspresence_lb":                          dataSourceAwsLb(),
+			"awspresence_alb":                         dataSourceAwsLb(),
+			"awspresence_elb":                         dataSourceAwsElb(),
+			"awspresence_ip_ranges":                   dataSourceAwsIPRanges(),
+			"awspresence_lb_dependency_graph":         dataSourceAwsLbDependencyGraph(),
+			"awspresence_lb_listener":                 dataSourceAwsLbListener(),
+			"awspresence_alb_listener":                dataSourceAwsLbListener(),
+			"awspresence_lb_listener_certificates":    dataSourceAwsLbListenerCertificates(),
+			"awspresence_lb_listener_rule_exists":     dataSourceAwsLbListenerRuleExists(),
+			"awspresence_lb_listener_rule_imports":    dataSourceAwsLbListenerRuleImports(),
+			"awspresence_lb_path_patterns":            dataSourceAwsLbPathPatterns(),
+			"awspresence_lb_rule_change_history":      dataSourceAwsLbRuleChangeHistory(),
+			"awspresence_lb_synthetics_canary_script": dataSourceAwsLbSyntheticsCanaryScript(),
+			"awspresence_lb_target_group":             dataSourceAwsLbTargetGroup(),
+			"awspresence_alb_target_group":            dataSourceAwsLbTargetGroup(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
+			"awspresence_acm_certificate":            resourceAwsAcmCertificate(),
+			"awspresence_acm_certificate_validation": resourceAwsAcmCertificateValidation(),
+			"awspresence_iam_server_certificate":     resourceAwsIAMServerCertificate(),
+
 			// ALBs are actually LBs because they can be type `network` or `application`
 			// To avoid regressions, we will add a new resource for each and they both point
 			// back to the old ALB version. IF the Terraform supported aliases for resources
 			// this would be a whole lot simpler
-			"awspresence_alb":                         resourceAwsLb(),
-			"awspresence_lb":                          resourceAwsLb(),
-			"awspresence_alb_listener":                resourceAwsLbListener(),
-			"awspresence_lb_listener":                 resourceAwsLbListener(),
-			"awspresence_alb_listener_certificate":    resourceAwsLbListenerCertificate(),
-			"awspresence_lb_listener_certificate":     resourceAwsLbListenerCertificate(),
-			"awspresence_alb_listener_rule":           resourceAwsLbbListenerRule(),
-			"awspresence_lb_listener_rule":            resourceAwsLbbListenerRule(),
-			"awspresence_alb_target_group":            resourceAwsLbTargetGroup(),
-			"awspresence_lb_target_group":             resourceAwsLbTargetGroup(),
-			"awspresence_alb_target_group_attachment": resourceAwsLbTargetGroupAttachment(),
-			"awspresence_lb_target_group_attachment":  resourceAwsLbTargetGroupAttachment(),
+			"awspresence_alb":                             resourceAwsLb(),
+			"awspresence_lb":                              resourceAwsLb(),
+			"awspresence_lb_cloudfront_origin_lock":       resourceAwsLbCloudfrontOriginLock(),
+			"awspresence_lb_cookie_stickiness_policy":     resourceAwsLBCookieStickinessPolicy(),
+			"awspresence_lb_failover_pair":                resourceAwsLbFailoverPair(),
+			"awspresence_alb_listener":                    resourceAwsLbListener(),
+			"awspresence_lb_listener":                     resourceAwsLbListener(),
+			"awspresence_alb_listener_certificate":        resourceAwsLbListenerCertificate(),
+			"awspresence_lb_listener_certificate":         resourceAwsLbListenerCertificate(),
+			"awspresence_alb_listener_rule":               resourceAwsLbbListenerRule(),
+			"awspresence_lb_listener_rule":                resourceAwsLbbListenerRule(),
+			"awspresence_alb_target_group":                resourceAwsLbTargetGroup(),
+			"awspresence_lb_target_group":                 resourceAwsLbTargetGroup(),
+			"awspresence_alb_target_group_attachment":     resourceAwsLbTargetGroupAttachment(),
+			"awspresence_lb_target_group_attachment":      resourceAwsLbTargetGroupAttachment(),
+			"awspresence_lb_target_group_attachment_dns":  resourceAwsLbTargetGroupAttachmentDns(),
+			"awspresence_wafregional_web_acl_association": resourceAwsWafRegionalWebAclAssociation(),
 		},
-		ConfigureFunc: providerConfigure,
 	}
+
+	for name, r := range provider.ResourcesMap {
+		if isLbMetricsResource(name) {
+			addLbResourceMetrics(name, r)
+		}
+	}
+
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		client, err := providerConfigure(d)
+		if err != nil {
+			return nil, err
+		}
+
+		// Write the metrics file when Terraform stops the provider, e.g. on
+		// an interrupted apply, as well as at the end of the run.
+		if path := d.Get("metrics_file").(string); path != "" {
+			enableLbMetricsFile(path, provider.StopContext())
+		}
+
+		return client, nil
+	}
+
+	return provider
 }
 
 var descriptions map[string]string
@@ -199,6 +286,26 @@ func init() {
 			"being executed. If the API request still fails, an error is\n" +
 			"thrown.",
 
+		"retry_mode": "Specifies how retries are attempted. Valid values are `standard` and\n" +
+			"`adaptive`. In `adaptive` mode requests are also rate limited on the client\n" +
+			"once the API starts throttling them.",
+
+		"api_call_summary": "Log a summary of the AWS API calls made by the provider, per\n" +
+			"operation, at the end of the Terraform run.",
+
+		"metrics_file": "The path of a file to write metrics of the LB resource operations\n" +
+			"of an apply to, in the Prometheus text format.",
+
+		"localstack_endpoint": "The URL of a LocalStack instance to run against. Every service\n" +
+			"endpoint not set in `endpoints` points at it, and the features LocalStack\n" +
+			"does not support are refused at plan time.",
+
+		"user_agent_module": "An identifier of the Terraform module the provider is configured\n" +
+			"in, appended to the User-Agent of every AWS API request.",
+
+		"user_agent_workspace": "An identifier of the Terraform workspace, appended to the\n" +
+			"User-Agent of every AWS API request.",
+
 		"endpoint": "Use this to override the default service endpoint URL",
 
 		"insecure": "Explicitly allow the provider to perform \"insecure\" SSL requests. If omitted," +
@@ -369,6 +476,9 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		Region:                  d.Get("region").(string),
 		Endpoints:               make(map[string]string),
 		MaxRetries:              d.Get("max_retries").(int),
+		RetryMode:               d.Get("retry_mode").(string),
+		ApiCallSummary:          d.Get("api_call_summary").(bool),
+		MetricsFile:             d.Get("metrics_file").(string),
 		Insecure:                d.Get("insecure").(bool),
 		SkipCredsValidation:     d.Get("skip_credentials_validation").(bool),
 		SkipGetEC2Platforms:     d.Get("skip_get_ec2_platforms").(bool),
@@ -376,6 +486,9 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		SkipRequestingAccountId: d.Get("skip_requesting_account_id").(bool),
 		SkipMetadataApiCheck:    d.Get("skip_metadata_api_check").(bool),
 		S3ForcePathStyle:        d.Get("s3_force_path_style").(bool),
+		LocalstackEndpoint:      d.Get("localstack_endpoint").(string),
+		UserAgentModule:         d.Get("user_agent_module").(string),
+		UserAgentWorkspace:      d.Get("user_agent_workspace").(string),
 	}
 
 	// Set CredsFilename, expanding home directory
@@ -15,11 +15,9 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/terraform-providers/terraform-provider-template/template"
-	"github.com/terraform-providers/terraform-provider-tls/tls"
 )
 
 var testAccProviders map[string]terraform.ResourceProvider
-var testAccProvidersWithTLS map[string]terraform.ResourceProvider
 var testAccProviderFactories func(providers *[]*schema.Provider) map[string]terraform.ResourceProviderFactory
 var testAccProvider *schema.Provider
 var testAccTemplateProvider *schema.Provider
@@ -38,20 +36,8 @@ func init() {
 				*providers = append(*providers, p.(*schema.Provider))
 				return p, nil
 			},
-			"tls": func() (terraform.ResourceProvider, error) {
-				p := tls.Provider()
-				*providers = append(*providers, p.(*schema.Provider))
-				return p, nil
-			},
 		}
 	}
-	testAccProvidersWithTLS = map[string]terraform.ResourceProvider{
-		"tls": tls.Provider(),
-	}
-
-	for k, v := range testAccProviders {
-		testAccProvidersWithTLS[k] = v
-	}
 }
 
 func TestProvider(t *testing.T) {
@@ -65,7 +51,10 @@ func TestProvider_impl(t *testing.T) {
 }
 
 func testAccPreCheck(t *testing.T) {
-	if os.Getenv("AWS_PROFILE") == "" && os.Getenv("AWS_ACCESS_KEY_ID") == "" {
+	// LocalStack accepts any credentials, and the provider supplies some.
+	if os.Getenv("LOCALSTACK_ENDPOINT") != "" {
+		log.Printf("[INFO] Test: Running against LocalStack at %s", os.Getenv("LOCALSTACK_ENDPOINT"))
+	} else if os.Getenv("AWS_PROFILE") == "" && os.Getenv("AWS_ACCESS_KEY_ID") == "" {
 		t.Fatal("AWS_ACCESS_KEY_ID or AWS_PROFILE must be set for acceptance tests")
 	}
 
@@ -83,6 +72,17 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+// testAccPreCheckLocalstackFeature skips the test when running against
+// LocalStack, which does not support feature.
+func testAccPreCheckLocalstackFeature(t *testing.T, feature string) {
+	if os.Getenv("LOCALSTACK_ENDPOINT") == "" {
+		return
+	}
+	if reason, ok := localstackUnsupportedFeatures[feature]; ok {
+		t.Skipf("skipping test against LocalStack: %s", reason)
+	}
+}
+
 // testAccAwsProviderAccountID returns the account ID of an AWS provider
 func testAccAwsProviderAccountID(provider *schema.Provider) string {
 	if provider == nil {
@@ -0,0 +1,26 @@
+package awspresence
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// regionSchema returns the schema for the optional per-resource region
+// override. Leaving it unset manages the resource in the provider region.
+func regionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		ForceNew: true,
+	}
+}
+
+// resourceAWSClient returns the client for the region a resource is managed
+// in, honouring its region argument when one is set.
+func resourceAWSClient(d *schema.ResourceData, meta interface{}) *AWSClient {
+	client := meta.(*AWSClient)
+	if v, ok := d.GetOk("region"); ok {
+		return client.regionalClient(v.(string))
+	}
+	return client
+}
@@ -0,0 +1,471 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+const (
+	// acmCertificateValidationMethodNone is reported as the validation method
+	// of imported and private CA issued certificates.
+	acmCertificateValidationMethodNone = "NONE"
+)
+
+func resourceAwsAcmCertificate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsAcmCertificateCreate,
+		Read:   resourceAwsAcmCertificateRead,
+		Update: resourceAwsAcmCertificateUpdate,
+		Delete: resourceAwsAcmCertificateDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"certificate_body": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				StateFunc:     normalizeCert,
+				ConflictsWith: []string{"domain_name", "validation_method", "certificate_authority_arn"},
+			},
+
+			"certificate_chain": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				StateFunc:     normalizeCert,
+				ConflictsWith: []string{"domain_name", "validation_method", "certificate_authority_arn"},
+			},
+
+			"private_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				StateFunc:     normalizeCert,
+				Sensitive:     true,
+				ConflictsWith: []string{"domain_name", "validation_method", "certificate_authority_arn"},
+			},
+
+			"domain_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				StateFunc:     acmCertificateDomainNameStateFunc,
+				ConflictsWith: []string{"private_key", "certificate_body", "certificate_chain"},
+			},
+
+			"subject_alternative_names": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:      schema.TypeString,
+					StateFunc: acmCertificateDomainNameStateFunc,
+				},
+				ConflictsWith: []string{"private_key", "certificate_body", "certificate_chain"},
+			},
+
+			"certificate_authority_arn": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validate.Arn,
+				ConflictsWith: []string{"private_key", "certificate_body", "certificate_chain", "validation_method"},
+			},
+
+			"validation_method": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					acm.ValidationMethodDns,
+					acm.ValidationMethodEmail,
+				}, false),
+				ConflictsWith: []string{"private_key", "certificate_body", "certificate_chain", "certificate_authority_arn"},
+			},
+
+			"options": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"certificate_transparency_logging_preference": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							Default:  acm.CertificateTransparencyLoggingPreferenceEnabled,
+							ValidateFunc: validation.StringInSlice([]string{
+								acm.CertificateTransparencyLoggingPreferenceEnabled,
+								acm.CertificateTransparencyLoggingPreferenceDisabled,
+							}, false),
+						},
+					},
+				},
+				ConflictsWith: []string{"private_key", "certificate_body", "certificate_chain"},
+			},
+
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"domain_validation_options": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domain_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_record_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_record_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_record_value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"validation_emails": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"tags": tagsSchema(),
+
+			"region": regionSchema(),
+		},
+	}
+}
+
+func resourceAwsAcmCertificateCreate(d *schema.ResourceData, meta interface{}) error {
+	if _, ok := d.GetOk("domain_name"); ok {
+		return resourceAwsAcmCertificateCreateRequested(d, meta)
+	}
+
+	if _, ok := d.GetOk("certificate_body"); ok {
+		return resourceAwsAcmCertificateCreateImported(d, meta)
+	}
+
+	return fmt.Errorf("Error creating ACM certificate: either domain_name or certificate_body and private_key must be set")
+}
+
+func resourceAwsAcmCertificateCreateImported(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).acmconn
+
+	if _, ok := d.GetOk("private_key"); !ok {
+		return fmt.Errorf("Error importing ACM certificate: private_key must be set with certificate_body")
+	}
+
+	params := &acm.ImportCertificateInput{
+		Certificate: []byte(d.Get("certificate_body").(string)),
+		PrivateKey:  []byte(d.Get("private_key").(string)),
+	}
+	if chain, ok := d.GetOk("certificate_chain"); ok {
+		params.CertificateChain = []byte(chain.(string))
+	}
+
+	resp, err := conn.ImportCertificate(params)
+	if err != nil {
+		return fmt.Errorf("Error importing ACM certificate: %s", err)
+	}
+
+	d.SetId(aws.StringValue(resp.CertificateArn))
+
+	return resourceAwsAcmCertificateCreateTags(d, meta)
+}
+
+func resourceAwsAcmCertificateCreateRequested(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).acmconn
+
+	params := &acm.RequestCertificateInput{
+		DomainName:       aws.String(acmCertificateDomainNameStateFunc(d.Get("domain_name"))),
+		IdempotencyToken: aws.String(resource.PrefixedUniqueId("tf")), // 32 character limit
+		Options:          expandAcmCertificateOptions(d.Get("options").([]interface{})),
+	}
+
+	if sans, ok := d.GetOk("subject_alternative_names"); ok {
+		for _, san := range sans.([]interface{}) {
+			params.SubjectAlternativeNames = append(params.SubjectAlternativeNames, aws.String(acmCertificateDomainNameStateFunc(san)))
+		}
+	}
+
+	if v, ok := d.GetOk("certificate_authority_arn"); ok {
+		params.CertificateAuthorityArn = aws.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("validation_method"); ok {
+		params.ValidationMethod = aws.String(v.(string))
+	}
+
+	log.Printf("[DEBUG] Requesting ACM certificate: %s", params)
+	resp, err := conn.RequestCertificate(params)
+	if err != nil {
+		return fmt.Errorf("Error requesting ACM certificate: %s", err)
+	}
+
+	d.SetId(aws.StringValue(resp.CertificateArn))
+
+	return resourceAwsAcmCertificateCreateTags(d, meta)
+}
+
+func resourceAwsAcmCertificateCreateTags(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).acmconn
+
+	if v, ok := d.GetOk("tags"); ok && len(v.(map[string]interface{})) > 0 {
+		_, err := conn.AddTagsToCertificate(&acm.AddTagsToCertificateInput{
+			CertificateArn: aws.String(d.Id()),
+			Tags:           tagsFromMapACM(v.(map[string]interface{})),
+		})
+		if err != nil {
+			return fmt.Errorf("Error adding tags to ACM certificate (%s): %s", d.Id(), err)
+		}
+	}
+
+	return resourceAwsAcmCertificateRead(d, meta)
+}
+
+func resourceAwsAcmCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).acmconn
+
+	params := &acm.DescribeCertificateInput{
+		CertificateArn: aws.String(d.Id()),
+	}
+
+	// Requested certificates take a few seconds before ACM fills in the
+	// records needed to validate them.
+	var certificate *acm.CertificateDetail
+	var domainValidationOptions []map[string]interface{}
+	var emailValidationOptions []string
+	err := resource.Retry(1*time.Minute, func() *resource.RetryError {
+		resp, err := conn.DescribeCertificate(params)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		certificate = resp.Certificate
+		domainValidationOptions, emailValidationOptions, err = flattenAcmCertificateValidationOptions(certificate)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+
+		return nil
+	})
+	if isAWSErr(err, acm.ErrCodeResourceNotFoundException, "") {
+		log.Printf("[WARN] ACM certificate (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error describing ACM certificate (%s): %s", d.Id(), err)
+	}
+
+	d.Set("arn", certificate.CertificateArn)
+	d.Set("domain_name", certificate.DomainName)
+	d.Set("status", certificate.Status)
+
+	if err := d.Set("subject_alternative_names", flattenAcmCertificateSubjectAlternativeNames(certificate)); err != nil {
+		return fmt.Errorf("Error setting subject_alternative_names: %s", err)
+	}
+
+	if err := d.Set("domain_validation_options", domainValidationOptions); err != nil {
+		return fmt.Errorf("Error setting domain_validation_options: %s", err)
+	}
+
+	if err := d.Set("validation_emails", emailValidationOptions); err != nil {
+		return fmt.Errorf("Error setting validation_emails: %s", err)
+	}
+
+	d.Set("certificate_authority_arn", certificate.CertificateAuthorityArn)
+	d.Set("validation_method", acmCertificateValidationMethod(certificate))
+
+	if aws.StringValue(certificate.Type) == acm.CertificateTypeAmazonIssued {
+		if err := d.Set("options", flattenAcmCertificateOptions(certificate.Options)); err != nil {
+			return fmt.Errorf("Error setting options: %s", err)
+		}
+	}
+
+	tagsResp, err := conn.ListTagsForCertificate(&acm.ListTagsForCertificateInput{
+		CertificateArn: aws.String(d.Id()),
+	})
+	if err != nil {
+		return fmt.Errorf("Error listing tags for ACM certificate (%s): %s", d.Id(), err)
+	}
+
+	if err := d.Set("tags", tagsToMapACM(tagsResp.Tags)); err != nil {
+		return fmt.Errorf("Error setting tags: %s", err)
+	}
+
+	d.Set("region", resourceAWSClient(d, meta).region)
+
+	return nil
+}
+
+func resourceAwsAcmCertificateUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).acmconn
+
+	if err := setTagsACM(conn, d); err != nil {
+		return fmt.Errorf("Error updating tags for ACM certificate (%s): %s", d.Id(), err)
+	}
+
+	return resourceAwsAcmCertificateRead(d, meta)
+}
+
+func resourceAwsAcmCertificateDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).acmconn
+
+	log.Printf("[INFO] Deleting ACM certificate: %s", d.Id())
+
+	// A certificate cannot be deleted while a listener still uses it, and
+	// listeners release certificates asynchronously after being updated or
+	// destroyed.
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := conn.DeleteCertificate(&acm.DeleteCertificateInput{
+			CertificateArn: aws.String(d.Id()),
+		})
+		if isAWSErr(err, acm.ErrCodeResourceInUseException, "") {
+			log.Printf("[WARN] ACM certificate (%s) still in use, retrying: %s", d.Id(), err)
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if isAWSErr(err, acm.ErrCodeResourceNotFoundException, "") {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error deleting ACM certificate (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// flattenAcmCertificateValidationOptions returns the DNS records and the email
+// addresses that can be used to validate certificate. An error is returned
+// while ACM has not yet generated the validation records of a domain.
+func flattenAcmCertificateValidationOptions(certificate *acm.CertificateDetail) ([]map[string]interface{}, []string, error) {
+	var domainValidationOptions []map[string]interface{}
+	var emailValidationOptions []string
+
+	switch aws.StringValue(certificate.Type) {
+	case acm.CertificateTypeAmazonIssued:
+		for _, o := range certificate.DomainValidationOptions {
+			if o.ResourceRecord != nil {
+				domainValidationOptions = append(domainValidationOptions, map[string]interface{}{
+					"domain_name":           aws.StringValue(o.DomainName),
+					"resource_record_name":  aws.StringValue(o.ResourceRecord.Name),
+					"resource_record_type":  aws.StringValue(o.ResourceRecord.Type),
+					"resource_record_value": aws.StringValue(o.ResourceRecord.Value),
+				})
+			} else if len(o.ValidationEmails) > 0 {
+				emailValidationOptions = append(emailValidationOptions, aws.StringValueSlice(o.ValidationEmails)...)
+			} else if o.ValidationStatus == nil || aws.StringValue(o.ValidationStatus) == acm.DomainStatusPendingValidation {
+				return nil, nil, fmt.Errorf("validation options for %s not yet available", aws.StringValue(o.DomainName))
+			}
+		}
+	case acm.CertificateTypePrivate:
+		// Private certificates do not need validation, but ACM only fills in
+		// all of their details once they leave PENDING_VALIDATION.
+		if aws.StringValue(certificate.Status) == acm.CertificateStatusPendingValidation {
+			return nil, nil, fmt.Errorf("certificate still pending issuance")
+		}
+	}
+
+	return domainValidationOptions, emailValidationOptions, nil
+}
+
+// flattenAcmCertificateSubjectAlternativeNames returns the subject alternative
+// names of certificate, without the domain name that ACM always includes.
+func flattenAcmCertificateSubjectAlternativeNames(certificate *acm.CertificateDetail) []string {
+	var sans []string
+	for _, san := range certificate.SubjectAlternativeNames {
+		if aws.StringValue(san) != aws.StringValue(certificate.DomainName) {
+			sans = append(sans, aws.StringValue(san))
+		}
+	}
+
+	return sans
+}
+
+// acmCertificateValidationMethod returns the method used to validate
+// certificate, or NONE for certificates that ACM did not issue itself.
+func acmCertificateValidationMethod(certificate *acm.CertificateDetail) string {
+	if aws.StringValue(certificate.Type) == acm.CertificateTypeAmazonIssued {
+		for _, o := range certificate.DomainValidationOptions {
+			if o.ValidationMethod != nil {
+				return aws.StringValue(o.ValidationMethod)
+			}
+		}
+	}
+
+	return acmCertificateValidationMethodNone
+}
+
+func acmCertificateDomainNameStateFunc(v interface{}) string {
+	// AWS provides domain names without a trailing dot, but users may
+	// configure fully qualified names.
+	return strings.TrimSuffix(v.(string), ".")
+}
+
+func expandAcmCertificateOptions(l []interface{}) *acm.CertificateOptions {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m := l[0].(map[string]interface{})
+
+	options := &acm.CertificateOptions{}
+	if v, ok := m["certificate_transparency_logging_preference"]; ok {
+		options.CertificateTransparencyLoggingPreference = aws.String(v.(string))
+	}
+
+	return options
+}
+
+func flattenAcmCertificateOptions(options *acm.CertificateOptions) []interface{} {
+	if options == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"certificate_transparency_logging_preference": aws.StringValue(options.CertificateTransparencyLoggingPreference),
+		},
+	}
+}
@@ -0,0 +1,310 @@
+package awspresence
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestFlattenAcmCertificateValidationOptions(t *testing.T) {
+	cases := []struct {
+		Name        string
+		Certificate *acm.CertificateDetail
+		Domain      []map[string]interface{}
+		Email       []string
+		ExpectError bool
+	}{
+		{
+			Name: "dns",
+			Certificate: &acm.CertificateDetail{
+				Type: aws.String(acm.CertificateTypeAmazonIssued),
+				DomainValidationOptions: []*acm.DomainValidation{
+					{
+						DomainName: aws.String("example.com"),
+						ResourceRecord: &acm.ResourceRecord{
+							Name:  aws.String("_abc.example.com."),
+							Type:  aws.String(acm.RecordTypeCname),
+							Value: aws.String("_def.acm-validations.aws."),
+						},
+					},
+				},
+			},
+			Domain: []map[string]interface{}{
+				{
+					"domain_name":           "example.com",
+					"resource_record_name":  "_abc.example.com.",
+					"resource_record_type":  acm.RecordTypeCname,
+					"resource_record_value": "_def.acm-validations.aws.",
+				},
+			},
+		},
+		{
+			Name: "email",
+			Certificate: &acm.CertificateDetail{
+				Type: aws.String(acm.CertificateTypeAmazonIssued),
+				DomainValidationOptions: []*acm.DomainValidation{
+					{
+						DomainName:       aws.String("example.com"),
+						ValidationEmails: aws.StringSlice([]string{"admin@example.com"}),
+					},
+				},
+			},
+			Email: []string{"admin@example.com"},
+		},
+		{
+			Name: "pending",
+			Certificate: &acm.CertificateDetail{
+				Type: aws.String(acm.CertificateTypeAmazonIssued),
+				DomainValidationOptions: []*acm.DomainValidation{
+					{
+						DomainName:       aws.String("example.com"),
+						ValidationStatus: aws.String(acm.DomainStatusPendingValidation),
+					},
+				},
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "imported",
+			Certificate: &acm.CertificateDetail{
+				Type: aws.String(acm.CertificateTypeImported),
+			},
+		},
+		{
+			Name: "private pending",
+			Certificate: &acm.CertificateDetail{
+				Type:   aws.String(acm.CertificateTypePrivate),
+				Status: aws.String(acm.CertificateStatusPendingValidation),
+			},
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		domain, email, err := flattenAcmCertificateValidationOptions(tc.Certificate)
+		if tc.ExpectError {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tc.Name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tc.Name, err)
+		}
+		if !reflect.DeepEqual(domain, tc.Domain) {
+			t.Fatalf("%s: expected domain options %#v, got %#v", tc.Name, tc.Domain, domain)
+		}
+		if !reflect.DeepEqual(email, tc.Email) {
+			t.Fatalf("%s: expected emails %#v, got %#v", tc.Name, tc.Email, email)
+		}
+	}
+}
+
+func TestAcmCertificateValidationMethod(t *testing.T) {
+	cases := []struct {
+		Certificate *acm.CertificateDetail
+		Expected    string
+	}{
+		{
+			Certificate: &acm.CertificateDetail{
+				Type: aws.String(acm.CertificateTypeAmazonIssued),
+				DomainValidationOptions: []*acm.DomainValidation{
+					{ValidationMethod: aws.String(acm.ValidationMethodDns)},
+				},
+			},
+			Expected: acm.ValidationMethodDns,
+		},
+		{
+			Certificate: &acm.CertificateDetail{
+				Type: aws.String(acm.CertificateTypeAmazonIssued),
+				DomainValidationOptions: []*acm.DomainValidation{
+					{ValidationMethod: aws.String(acm.ValidationMethodEmail)},
+				},
+			},
+			Expected: acm.ValidationMethodEmail,
+		},
+		{
+			Certificate: &acm.CertificateDetail{
+				Type: aws.String(acm.CertificateTypeImported),
+			},
+			Expected: acmCertificateValidationMethodNone,
+		},
+	}
+
+	for i, tc := range cases {
+		if actual := acmCertificateValidationMethod(tc.Certificate); actual != tc.Expected {
+			t.Fatalf("case %d: expected %q, got %q", i, tc.Expected, actual)
+		}
+	}
+}
+
+func TestFlattenAcmCertificateSubjectAlternativeNames(t *testing.T) {
+	certificate := &acm.CertificateDetail{
+		DomainName:              aws.String("example.com"),
+		SubjectAlternativeNames: aws.StringSlice([]string{"example.com", "www.example.com", "*.example.com"}),
+	}
+
+	expected := []string{"www.example.com", "*.example.com"}
+	if actual := flattenAcmCertificateSubjectAlternativeNames(certificate); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestAcmCertificateValidationRecordFqdns(t *testing.T) {
+	options := []map[string]interface{}{
+		{"resource_record_name": "_b.example.com."},
+		{"resource_record_name": "_a.example.com."},
+		// The wildcard and the apex domain are validated by the same record.
+		{"resource_record_name": "_a.example.com."},
+	}
+
+	expected := []string{"_a.example.com", "_b.example.com"}
+	if actual := acmCertificateValidationRecordFqdns(options); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestAccAWSAcmCertificate_imported(t *testing.T) {
+	resourceName := "aws_acm_certificate.test"
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAcmCertificateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAcmCertificateConfigImported(key, certificate),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "arn"),
+					resource.TestCheckResourceAttr(resourceName, "domain_name", "example.com"),
+					resource.TestCheckResourceAttr(resourceName, "status", acm.CertificateStatusIssued),
+					resource.TestCheckResourceAttr(resourceName, "validation_method", acmCertificateValidationMethodNone),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"private_key", "certificate_body"},
+			},
+		},
+	})
+}
+
+func TestAccAWSAcmCertificate_listener(t *testing.T) {
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAcmCertificateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAcmCertificateConfigListener(key, certificate),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("aws_lb_listener.test", "certificate_arn", "aws_acm_certificate.test", "arn"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAcmCertificateDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).acmconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_acm_certificate" {
+			continue
+		}
+
+		_, err := conn.DescribeCertificate(&acm.DescribeCertificateInput{
+			CertificateArn: aws.String(rs.Primary.ID),
+		})
+		if err == nil {
+			return fmt.Errorf("ACM certificate %s still exists", rs.Primary.ID)
+		}
+		if !isAWSErr(err, acm.ErrCodeResourceNotFoundException, "") {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccAcmCertificateConfigImported(key, certificate string) string {
+	return fmt.Sprintf(`
+resource "aws_acm_certificate" "test" {
+  certificate_body = "%[1]s"
+  private_key      = "%[2]s"
+
+  tags = {
+    Name = "tf-acc-test"
+  }
+}
+`, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key))
+}
+
+func testAccAcmCertificateConfigListener(key, certificate string) string {
+	return fmt.Sprintf(`
+resource "aws_acm_certificate" "test" {
+  certificate_body = "%[1]s"
+  private_key      = "%[2]s"
+}
+
+resource "aws_lb_listener" "test" {
+  load_balancer_arn = "${aws_lb.test.id}"
+  protocol          = "HTTPS"
+  port              = "443"
+  ssl_policy        = "ELBSecurityPolicy-2016-08"
+  certificate_arn   = "${aws_acm_certificate.test.arn}"
+
+  default_action {
+    type = "fixed-response"
+
+    fixed_response {
+      content_type = "text/plain"
+      status_code  = "404"
+    }
+  }
+}
+
+resource "aws_lb" "test" {
+  internal = true
+  subnets  = ["${aws_subnet.test.*.id}"]
+
+  tags = {
+    Name = "tf-acc-test-acm-certificate-listener"
+  }
+}
+
+data "aws_availability_zones" "available" {}
+
+resource "aws_vpc" "test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "tf-acc-test-acm-certificate-listener"
+  }
+}
+
+resource "aws_subnet" "test" {
+  count             = 2
+  vpc_id            = "${aws_vpc.test.id}"
+  cidr_block        = "10.0.${count.index}.0/24"
+  availability_zone = "${data.aws_availability_zones.available.names[count.index]}"
+
+  tags = {
+    Name = "tf-acc-test-acm-certificate-listener"
+  }
+}
+`, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key))
+}
@@ -0,0 +1,195 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsAcmCertificateValidation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsAcmCertificateValidationCreate,
+		Read:   resourceAwsAcmCertificateValidationRead,
+		Delete: resourceAwsAcmCertificateValidationDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(45 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"certificate_arn": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"validation_record_fqdns": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:      schema.TypeString,
+					StateFunc: acmCertificateDomainNameStateFunc,
+				},
+				Set: schema.HashString,
+			},
+
+			"region": regionSchema(),
+		},
+	}
+}
+
+func resourceAwsAcmCertificateValidationCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).acmconn
+	certificateArn := d.Get("certificate_arn").(string)
+
+	params := &acm.DescribeCertificateInput{
+		CertificateArn: aws.String(certificateArn),
+	}
+
+	resp, err := conn.DescribeCertificate(params)
+	if err != nil {
+		return fmt.Errorf("Error describing ACM certificate (%s): %s", certificateArn, err)
+	}
+
+	if certificateType := aws.StringValue(resp.Certificate.Type); certificateType != acm.CertificateTypeAmazonIssued {
+		return fmt.Errorf("ACM certificate (%s) has type %s, no validation necessary", certificateArn, certificateType)
+	}
+
+	if v, ok := d.GetOk("validation_record_fqdns"); ok {
+		if err := checkAcmCertificateValidationRecords(conn, certificateArn, v.(*schema.Set).List()); err != nil {
+			return err
+		}
+	} else {
+		log.Printf("[INFO] No validation_record_fqdns set, skipping check")
+	}
+
+	err = resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		resp, err := conn.DescribeCertificate(params)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		if status := aws.StringValue(resp.Certificate.Status); status != acm.CertificateStatusIssued {
+			return resource.RetryableError(fmt.Errorf("Expected certificate to be issued but was in state %s", status))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error waiting for ACM certificate (%s) to be issued: %s", certificateArn, err)
+	}
+
+	log.Printf("[INFO] ACM certificate validation for %s done, certificate was issued", certificateArn)
+
+	return resourceAwsAcmCertificateValidationRead(d, meta)
+}
+
+// checkAcmCertificateValidationRecords makes sure fqdns are exactly the DNS
+// records ACM expects, so that a typo fails fast instead of waiting for the
+// whole create timeout.
+func checkAcmCertificateValidationRecords(conn *acm.ACM, certificateArn string, fqdns []interface{}) error {
+	var expected []string
+
+	// ACM takes a few seconds to generate the validation records of a newly
+	// requested certificate.
+	err := resource.Retry(1*time.Minute, func() *resource.RetryError {
+		resp, err := conn.DescribeCertificate(&acm.DescribeCertificateInput{
+			CertificateArn: aws.String(certificateArn),
+		})
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		if aws.StringValue(resp.Certificate.Status) == acm.CertificateStatusIssued {
+			expected = nil
+			return nil
+		}
+
+		domainValidationOptions, _, err := flattenAcmCertificateValidationOptions(resp.Certificate)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+
+		expected = acmCertificateValidationRecordFqdns(domainValidationOptions)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error reading validation records of ACM certificate (%s): %s", certificateArn, err)
+	}
+
+	if expected == nil {
+		return nil
+	}
+
+	var actual []string
+	for _, fqdn := range fqdns {
+		actual = append(actual, acmCertificateDomainNameStateFunc(fqdn))
+	}
+	sort.Strings(actual)
+
+	if !reflect.DeepEqual(expected, actual) {
+		return fmt.Errorf("ACM certificate (%s) needs validation records %s, got %s",
+			certificateArn, strings.Join(expected, ", "), strings.Join(actual, ", "))
+	}
+
+	return nil
+}
+
+// acmCertificateValidationRecordFqdns returns the sorted, de-duplicated names
+// of the DNS validation records. Wildcard and apex names share a record.
+func acmCertificateValidationRecordFqdns(domainValidationOptions []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var fqdns []string
+
+	for _, o := range domainValidationOptions {
+		fqdn := acmCertificateDomainNameStateFunc(o["resource_record_name"])
+		if !seen[fqdn] {
+			seen[fqdn] = true
+			fqdns = append(fqdns, fqdn)
+		}
+	}
+	sort.Strings(fqdns)
+
+	return fqdns
+}
+
+func resourceAwsAcmCertificateValidationRead(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).acmconn
+
+	resp, err := conn.DescribeCertificate(&acm.DescribeCertificateInput{
+		CertificateArn: aws.String(d.Get("certificate_arn").(string)),
+	})
+	if isAWSErr(err, acm.ErrCodeResourceNotFoundException, "") {
+		log.Printf("[WARN] ACM certificate (%s) not found, removing validation from state", d.Get("certificate_arn").(string))
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error describing ACM certificate (%s): %s", d.Get("certificate_arn").(string), err)
+	}
+
+	if status := aws.StringValue(resp.Certificate.Status); status != acm.CertificateStatusIssued {
+		log.Printf("[INFO] ACM certificate (%s) is in state %s, removing validation from state", d.Get("certificate_arn").(string), status)
+		d.SetId("")
+		return nil
+	}
+
+	d.SetId(aws.TimeValue(resp.Certificate.IssuedAt).String())
+	d.Set("region", resourceAWSClient(d, meta).region)
+
+	return nil
+}
+
+func resourceAwsAcmCertificateValidationDelete(d *schema.ResourceData, meta interface{}) error {
+	// Validation cannot be undone; removing the resource only forgets it.
+	return nil
+}
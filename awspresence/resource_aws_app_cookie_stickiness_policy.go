@@ -5,7 +5,6 @@ import (
 	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/elb"
 )
 
@@ -16,10 +15,8 @@ func resourceAwsELBSticknessPolicyAssigned(policyName, lbName, lbPort string, el
 	}
 	describeResp, err := elbconn.DescribeLoadBalancers(describeElbOpts)
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok {
-			if ec2err.Code() == "LoadBalancerNotFound" {
-				return false, nil
-			}
+		if isAWSErrCode(err, "LoadBalancerNotFound") {
+			return false, nil
 		}
 		return false, fmt.Errorf("Error retrieving ELB description: %s", err)
 	}
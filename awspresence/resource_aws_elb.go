@@ -4,16 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"log"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
-	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -37,14 +35,14 @@ func resourceAwsElb() *schema.Resource {
 				Computed:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"name_prefix"},
-				ValidateFunc:  validateElbName,
+				ValidateFunc:  validate.ElbName,
 			},
 			"name_prefix": {
 				Type:          schema.TypeString,
 				Optional:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"name"},
-				ValidateFunc:  validateElbNamePrefix,
+				ValidateFunc:  validate.ElbNamePrefix,
 			},
 
 			"arn": {
@@ -137,7 +135,7 @@ func resourceAwsElb() *schema.Resource {
 							Type:         schema.TypeInt,
 							Optional:     true,
 							Default:      60,
-							ValidateFunc: validateAccessLogsInterval,
+							ValidateFunc: validate.ElbAccessLogsInterval,
 						},
 						"bucket": {
 							Type:     schema.TypeString,
@@ -170,7 +168,7 @@ func resourceAwsElb() *schema.Resource {
 						"instance_protocol": {
 							Type:         schema.TypeString,
 							Required:     true,
-							ValidateFunc: validateListenerProtocol(),
+							ValidateFunc: validate.ElbListenerProtocol(),
 						},
 
 						"lb_port": {
@@ -182,13 +180,13 @@ func resourceAwsElb() *schema.Resource {
 						"lb_protocol": {
 							Type:         schema.TypeString,
 							Required:     true,
-							ValidateFunc: validateListenerProtocol(),
+							ValidateFunc: validate.ElbListenerProtocol(),
 						},
 
 						"ssl_certificate_id": {
 							Type:         schema.TypeString,
 							Optional:     true,
-							ValidateFunc: validateArn,
+							ValidateFunc: validate.Arn,
 						},
 					},
 				},
@@ -217,7 +215,7 @@ func resourceAwsElb() *schema.Resource {
 						"target": {
 							Type:         schema.TypeString,
 							Required:     true,
-							ValidateFunc: validateHeathCheckTarget,
+							ValidateFunc: validate.ElbHealthCheckTarget,
 						},
 
 						"interval": {
@@ -300,12 +298,10 @@ func resourceAwsElbCreate(d *schema.ResourceData, meta interface{}) error {
 		_, err := elbconn.CreateLoadBalancer(elbOpts)
 
 		if err != nil {
-			if awsErr, ok := err.(awserr.Error); ok {
-				// Check for IAM SSL Cert error, eventual consistancy issue
-				if awsErr.Code() == "CertificateNotFound" {
-					return resource.RetryableError(
-						fmt.Errorf("Error creating ELB Listener with SSL Cert, retrying: %s", err))
-				}
+			// Check for IAM SSL Cert error, eventual consistancy issue
+			if isAWSErrCode(err, "CertificateNotFound") {
+				return resource.RetryableError(
+					fmt.Errorf("Error creating ELB Listener with SSL Cert, retrying: %s", err))
 			}
 			return resource.NonRetryableError(err)
 		}
@@ -512,15 +508,13 @@ func resourceAwsElbUpdate(d *schema.ResourceData, meta interface{}) error {
 			err := resource.Retry(5*time.Minute, func() *resource.RetryError {
 				log.Printf("[DEBUG] ELB Create Listeners opts: %s", createListenersOpts)
 				if _, err := elbconn.CreateLoadBalancerListeners(createListenersOpts); err != nil {
-					if awsErr, ok := err.(awserr.Error); ok {
-						if awsErr.Code() == "DuplicateListener" {
-							log.Printf("[DEBUG] Duplicate listener found for ELB (%s), retrying", d.Id())
-							return resource.RetryableError(awsErr)
-						}
-						if awsErr.Code() == "CertificateNotFound" && strings.Contains(awsErr.Message(), "Server Certificate not found for the key: arn") {
-							log.Printf("[DEBUG] SSL Cert not found for given ARN, retrying")
-							return resource.RetryableError(awsErr)
-						}
+					if isAWSErrCode(err, "DuplicateListener") {
+						log.Printf("[DEBUG] Duplicate listener found for ELB (%s), retrying", d.Id())
+						return resource.RetryableError(err)
+					}
+					if isAWSErrMessage(err, "Server Certificate not found for the key: arn", "CertificateNotFound") {
+						log.Printf("[DEBUG] SSL Cert not found for given ARN, retrying")
+						return resource.RetryableError(err)
 					}
 
 					// Didn't recognize the error, so shouldn't retry.
@@ -765,13 +759,11 @@ func resourceAwsElbUpdate(d *schema.ResourceData, meta interface{}) error {
 			err := resource.Retry(5*time.Minute, func() *resource.RetryError {
 				_, err := elbconn.AttachLoadBalancerToSubnets(attachOpts)
 				if err != nil {
-					if awsErr, ok := err.(awserr.Error); ok {
-						// eventually consistent issue with removing a subnet in AZ1 and
-						// immediately adding a new one in the same AZ
-						if awsErr.Code() == "InvalidConfigurationRequest" && strings.Contains(awsErr.Message(), "cannot be attached to multiple subnets in the same AZ") {
-							log.Printf("[DEBUG] retrying az association")
-							return resource.RetryableError(awsErr)
-						}
+					// eventually consistent issue with removing a subnet in AZ1 and
+					// immediately adding a new one in the same AZ
+					if isAWSErrMessage(err, "cannot be attached to multiple subnets in the same AZ", "InvalidConfigurationRequest") {
+						log.Printf("[DEBUG] retrying az association")
+						return resource.RetryableError(err)
 					}
 					return resource.NonRetryableError(err)
 				}
@@ -805,6 +797,10 @@ func resourceAwsElbDelete(d *schema.ResourceData, meta interface{}) error {
 		LoadBalancerName: aws.String(d.Id()),
 	}
 	if _, err := elbconn.DeleteLoadBalancer(&deleteElbOpts); err != nil {
+		if isLbNotFoundErr(err) {
+			log.Printf("[WARN] ELB %s already deleted", d.Id())
+			return nil
+		}
 		return fmt.Errorf("Error deleting ELB: %s", err)
 	}
 
@@ -836,8 +832,7 @@ func resourceAwsElbListenerHash(v interface{}) int {
 }
 
 func isLoadBalancerNotFound(err error) bool {
-	elberr, ok := err.(awserr.Error)
-	return ok && elberr.Code() == "LoadBalancerNotFound"
+	return isAWSErrCode(err, "LoadBalancerNotFound")
 }
 
 func sourceSGIdByName(conn *ec2.EC2, sg, vpcId string) (string, error) {
@@ -866,12 +861,9 @@ func sourceSGIdByName(conn *ec2.EC2, sg, vpcId string) (string, error) {
 	}
 	resp, err := conn.DescribeSecurityGroups(req)
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok {
-			if ec2err.Code() == "InvalidSecurityGroupID.NotFound" ||
-				ec2err.Code() == "InvalidGroup.NotFound" {
-				resp = nil
-				err = nil
-			}
+		if isAWSErrCode(err, "InvalidSecurityGroupID.NotFound", "InvalidGroup.NotFound") {
+			resp = nil
+			err = nil
 		}
 
 		if err != nil {
@@ -888,112 +880,6 @@ func sourceSGIdByName(conn *ec2.EC2, sg, vpcId string) (string, error) {
 	return *group.GroupId, nil
 }
 
-func validateAccessLogsInterval(v interface{}, k string) (ws []string, errors []error) {
-	value := v.(int)
-
-	// Check if the value is either 5 or 60 (minutes).
-	if value != 5 && value != 60 {
-		errors = append(errors, fmt.Errorf(
-			"%q contains an invalid Access Logs interval \"%d\". "+
-				"Valid intervals are either 5 or 60 (minutes).",
-			k, value))
-	}
-	return
-}
-
-func validateHeathCheckTarget(v interface{}, k string) (ws []string, errors []error) {
-	value := v.(string)
-
-	// Parse the Health Check target value.
-	matches := regexp.MustCompile(`\A(\w+):(\d+)(.+)?\z`).FindStringSubmatch(value)
-
-	// Check if the value contains a valid target.
-	if matches == nil || len(matches) < 1 {
-		errors = append(errors, fmt.Errorf(
-			"%q contains an invalid Health Check: %s",
-			k, value))
-
-		// Invalid target? Return immediately,
-		// there is no need to collect other
-		// errors.
-		return
-	}
-
-	// Check if the value contains a valid protocol.
-	if !isValidProtocol(matches[1]) {
-		errors = append(errors, fmt.Errorf(
-			"%q contains an invalid Health Check protocol %q. "+
-				"Valid protocols are either %q, %q, %q, or %q.",
-			k, matches[1], "TCP", "SSL", "HTTP", "HTTPS"))
-	}
-
-	// Check if the value contains a valid port range.
-	port, _ := strconv.Atoi(matches[2])
-	if port < 1 || port > 65535 {
-		errors = append(errors, fmt.Errorf(
-			"%q contains an invalid Health Check target port \"%d\". "+
-				"Valid port is in the range from 1 to 65535 inclusive.",
-			k, port))
-	}
-
-	switch strings.ToLower(matches[1]) {
-	case "tcp", "ssl":
-		// Check if value is in the form <PROTOCOL>:<PORT> for TCP and/or SSL.
-		if matches[3] != "" {
-			errors = append(errors, fmt.Errorf(
-				"%q cannot contain a path in the Health Check target: %s",
-				k, value))
-		}
-
-	case "http", "https":
-		// Check if value is in the form <PROTOCOL>:<PORT>/<PATH> for HTTP and/or HTTPS.
-		if matches[3] == "" {
-			errors = append(errors, fmt.Errorf(
-				"%q must contain a path in the Health Check target: %s",
-				k, value))
-		}
-
-		// Cannot be longer than 1024 multibyte characters.
-		if len([]rune(matches[3])) > 1024 {
-			errors = append(errors, fmt.Errorf("%q cannot contain a path longer "+
-				"than 1024 characters in the Health Check target: %s",
-				k, value))
-		}
-
-	}
-
-	return
-}
-
-func isValidProtocol(s string) bool {
-	if s == "" {
-		return false
-	}
-	s = strings.ToLower(s)
-
-	validProtocols := map[string]bool{
-		"http":  true,
-		"https": true,
-		"ssl":   true,
-		"tcp":   true,
-	}
-
-	if _, ok := validProtocols[s]; !ok {
-		return false
-	}
-
-	return true
-}
-
-func validateListenerProtocol() schema.SchemaValidateFunc {
-	return validation.StringInSlice([]string{
-		"HTTP",
-		"HTTPS",
-		"SSL",
-		"TCP",
-	}, true)
-}
-
 // ELB automatically creates ENI(s) on creation
 // but the cleanup is asynchronous and may take time
 // which then blocks IGW, SG or VPC on deletion
@@ -1044,8 +930,7 @@ func detachNetworkInterfaces(conn *ec2.EC2, nis []*ec2.NetworkInterface) error {
 			Force:        aws.Bool(true),
 		})
 		if err != nil {
-			awsErr, ok := err.(awserr.Error)
-			if ok && awsErr.Code() == "InvalidAttachmentID.NotFound" {
+			if isAWSErrCode(err, "InvalidAttachmentID.NotFound") {
 				log.Printf("[DEBUG] ENI %s is already detached", *ni.NetworkInterfaceId)
 				continue
 			}
@@ -1061,8 +946,7 @@ func detachNetworkInterfaces(conn *ec2.EC2, nis []*ec2.NetworkInterface) error {
 		}
 
 		if _, err := stateConf.WaitForState(); err != nil {
-			awsErr, ok := err.(awserr.Error)
-			if ok && awsErr.Code() == "InvalidNetworkInterfaceID.NotFound" {
+			if isAWSErrCode(err, "InvalidNetworkInterfaceID.NotFound") {
 				continue
 			}
 			return fmt.Errorf(
@@ -1079,8 +963,7 @@ func deleteNetworkInterfaces(conn *ec2.EC2, nis []*ec2.NetworkInterface) error {
 			NetworkInterfaceId: ni.NetworkInterfaceId,
 		})
 		if err != nil {
-			awsErr, ok := err.(awserr.Error)
-			if ok && awsErr.Code() == "InvalidNetworkInterfaceID.NotFound" {
+			if isAWSErrCode(err, "InvalidNetworkInterfaceID.NotFound") {
 				log.Printf("[DEBUG] ENI %s is already deleted", *ni.NetworkInterfaceId)
 				continue
 			}
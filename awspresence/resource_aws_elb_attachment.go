@@ -49,7 +49,7 @@ func resourceAwsElbAttachmentCreate(d *schema.ResourceData, meta interface{}) er
 	err := resource.Retry(10*time.Minute, func() *resource.RetryError {
 		_, err := elbconn.RegisterInstancesWithLoadBalancer(&registerInstancesOpts)
 
-		if isAWSErr(err, "InvalidTarget", "") {
+		if isAWSErrCode(err, "InvalidTarget") {
 			return resource.RetryableError(fmt.Errorf("Error attaching instance to ELB, retrying: %s", err))
 		}
 
@@ -127,7 +127,7 @@ func resourceAwsElbAttachmentDelete(d *schema.ResourceData, meta interface{}) er
 	}
 
 	_, err := elbconn.DeregisterInstancesFromLoadBalancer(&deRegisterInstancesOpts)
-	if err != nil {
+	if err != nil && !isLbNotFoundErr(err) {
 		return fmt.Errorf("Failure deregistering instances from ELB: %s", err)
 	}
 
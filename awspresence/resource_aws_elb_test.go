@@ -3,11 +3,9 @@ package awspresence
 import (
 	"fmt"
 	"log"
-	"math/rand"
 	"reflect"
 	"regexp"
 	"testing"
-	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -24,26 +22,6 @@ func init() {
 	})
 }
 
-const testAccTLSServerCert = `
-resource "tls_private_key" "example" {
-  algorithm = "RSA"
-}
-resource "tls_self_signed_cert" "example" {
-  key_algorithm   = "RSA"
-  private_key_pem = "${tls_private_key.example.private_key_pem}"
-  subject {
-    common_name  = "example.com"
-    organization = "ACME Examples, Inc"
-  }
-  validity_period_hours = 12
-  allowed_uses = [
-    "key_encipherment",
-    "digital_signature",
-    "server_auth",
-  ]
-}
-`
-
 func testSweepELBs(region string) error {
 	client, err := sharedClientForRegion(region)
 	if err != nil {
@@ -408,6 +386,8 @@ func TestAccAWSELB_Listener_SSLCertificateID_IAMServerCertificate(t *testing.T)
 	var conf elb.LoadBalancerDescription
 	rName := fmt.Sprintf("tf-acctest-%s", acctest.RandString(10))
 	resourceName := "aws_elb.bar"
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
 
 	testCheck := func(*terraform.State) error {
 		if len(conf.ListenerDescriptions) != 1 {
@@ -420,22 +400,22 @@ func TestAccAWSELB_Listener_SSLCertificateID_IAMServerCertificate(t *testing.T)
 
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProvidersWithTLS,
+		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckAWSELBDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config:      testAccELBConfig_Listener_IAMServerCertificate(rName, "tcp"),
+				Config:      testAccELBConfig_Listener_IAMServerCertificate(rName, "tcp", key, certificate),
 				ExpectError: regexp.MustCompile(`ssl_certificate_id may be set only when protocol is 'https' or 'ssl'`),
 			},
 			{
-				Config: testAccELBConfig_Listener_IAMServerCertificate(rName, "https"),
+				Config: testAccELBConfig_Listener_IAMServerCertificate(rName, "https", key, certificate),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAWSELBExists(resourceName, &conf),
 					testCheck,
 				),
 			},
 			{
-				Config:      testAccELBConfig_Listener_IAMServerCertificate_AddInvalidListener(rName),
+				Config:      testAccELBConfig_Listener_IAMServerCertificate_AddInvalidListener(rName, key, certificate),
 				ExpectError: regexp.MustCompile(`ssl_certificate_id may be set only when protocol is 'https' or 'ssl'`),
 			},
 		},
@@ -869,177 +849,6 @@ func TestResourceAwsElbListenerHash(t *testing.T) {
 	}
 }
 
-func TestResourceAWSELB_validateElbNameCannotBeginWithHyphen(t *testing.T) {
-	var elbName = "-Testing123"
-	_, errors := validateElbName(elbName, "SampleKey")
-
-	if len(errors) != 1 {
-		t.Fatalf("Expected the ELB Name to trigger a validation error")
-	}
-}
-
-func TestResourceAWSELB_validateElbNameCanBeAnEmptyString(t *testing.T) {
-	var elbName = ""
-	_, errors := validateElbName(elbName, "SampleKey")
-
-	if len(errors) != 0 {
-		t.Fatalf("Expected the ELB Name to pass validation")
-	}
-}
-
-func TestResourceAWSELB_validateElbNameCannotBeLongerThan32Characters(t *testing.T) {
-	var elbName = "Testing123dddddddddddddddddddvvvv"
-	_, errors := validateElbName(elbName, "SampleKey")
-
-	if len(errors) != 1 {
-		t.Fatalf("Expected the ELB Name to trigger a validation error")
-	}
-}
-
-func TestResourceAWSELB_validateElbNameCannotHaveSpecialCharacters(t *testing.T) {
-	var elbName = "Testing123%%"
-	_, errors := validateElbName(elbName, "SampleKey")
-
-	if len(errors) != 1 {
-		t.Fatalf("Expected the ELB Name to trigger a validation error")
-	}
-}
-
-func TestResourceAWSELB_validateElbNameCannotEndWithHyphen(t *testing.T) {
-	var elbName = "Testing123-"
-	_, errors := validateElbName(elbName, "SampleKey")
-
-	if len(errors) != 1 {
-		t.Fatalf("Expected the ELB Name to trigger a validation error")
-	}
-}
-
-func TestResourceAWSELB_validateAccessLogsInterval(t *testing.T) {
-	type testCases struct {
-		Value    int
-		ErrCount int
-	}
-
-	invalidCases := []testCases{
-		{
-			Value:    0,
-			ErrCount: 1,
-		},
-		{
-			Value:    10,
-			ErrCount: 1,
-		},
-		{
-			Value:    -1,
-			ErrCount: 1,
-		},
-	}
-
-	for _, tc := range invalidCases {
-		_, errors := validateAccessLogsInterval(tc.Value, "interval")
-		if len(errors) != tc.ErrCount {
-			t.Fatalf("Expected %q to trigger a validation error.", tc.Value)
-		}
-	}
-
-}
-
-func TestResourceAWSELB_validateHealthCheckTarget(t *testing.T) {
-	type testCase struct {
-		Value    string
-		ErrCount int
-	}
-
-	randomRunes := func(n int) string {
-		rand.Seed(time.Now().UTC().UnixNano())
-
-		// A complete set of modern Katakana characters.
-		runes := []rune("アイウエオ" +
-			"カキクケコガギグゲゴサシスセソザジズゼゾ" +
-			"タチツテトダヂヅデドナニヌネノハヒフヘホ" +
-			"バビブベボパピプペポマミムメモヤユヨラリ" +
-			"ルレロワヰヱヲン")
-
-		s := make([]rune, n)
-		for i := range s {
-			s[i] = runes[rand.Intn(len(runes))]
-		}
-		return string(s)
-	}
-
-	validCases := []testCase{
-		{
-			Value:    "TCP:1234",
-			ErrCount: 0,
-		},
-		{
-			Value:    "http:80/test",
-			ErrCount: 0,
-		},
-		{
-			Value:    fmt.Sprintf("HTTP:8080/%s", randomRunes(5)),
-			ErrCount: 0,
-		},
-		{
-			Value:    "SSL:8080",
-			ErrCount: 0,
-		},
-	}
-
-	for _, tc := range validCases {
-		_, errors := validateHeathCheckTarget(tc.Value, "target")
-		if len(errors) != tc.ErrCount {
-			t.Fatalf("Expected %q not to trigger a validation error.", tc.Value)
-		}
-	}
-
-	invalidCases := []testCase{
-		{
-			Value:    "",
-			ErrCount: 1,
-		},
-		{
-			Value:    "TCP:",
-			ErrCount: 1,
-		},
-		{
-			Value:    "TCP:1234/",
-			ErrCount: 1,
-		},
-		{
-			Value:    "SSL:8080/",
-			ErrCount: 1,
-		},
-		{
-			Value:    "HTTP:8080",
-			ErrCount: 1,
-		},
-		{
-			Value:    "incorrect-value",
-			ErrCount: 1,
-		},
-		{
-			Value:    "TCP:123456",
-			ErrCount: 1,
-		},
-		{
-			Value:    "incorrect:80/",
-			ErrCount: 1,
-		},
-		{
-			Value:    fmt.Sprintf("HTTP:8080/%s%s", randomString(512), randomRunes(512)),
-			ErrCount: 1,
-		},
-	}
-
-	for _, tc := range invalidCases {
-		_, errors := validateHeathCheckTarget(tc.Value, "target")
-		if len(errors) != tc.ErrCount {
-			t.Fatalf("Expected %q to trigger a validation error.", tc.Value)
-		}
-	}
-}
-
 func testAccCheckAWSELBDestroy(s *terraform.State) error {
 	conn := testAccProvider.Meta().(*AWSClient).elbconn
 
@@ -1697,16 +1506,14 @@ resource "aws_security_group" "bar" {
 }
 `
 
-func testAccELBConfig_Listener_IAMServerCertificate(certName, lbProtocol string) string {
+func testAccELBConfig_Listener_IAMServerCertificate(certName, lbProtocol, key, certificate string) string {
 	return fmt.Sprintf(`
 data "aws_availability_zones" "available" {}
 
-%[1]s
-
 resource "aws_iam_server_certificate" "test_cert" {
-  name             = "%[2]s"
-  certificate_body = "${tls_self_signed_cert.example.cert_pem}"
-  private_key      = "${tls_private_key.example.private_key_pem}"
+  name             = "%[1]s"
+  certificate_body = "%[3]s"
+  private_key      = "%[4]s"
 }
 
 resource "aws_elb" "bar" {
@@ -1714,25 +1521,23 @@ resource "aws_elb" "bar" {
 
   listener {
     instance_port      = 443
-    instance_protocol  = "%[3]s"
+    instance_protocol  = "%[2]s"
     lb_port            = 443
-    lb_protocol        = "%[3]s"
+    lb_protocol        = "%[2]s"
     ssl_certificate_id = "${aws_iam_server_certificate.test_cert.arn}"
   }
 }
-`, testAccTLSServerCert, certName, lbProtocol)
+`, certName, lbProtocol, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key))
 }
 
-func testAccELBConfig_Listener_IAMServerCertificate_AddInvalidListener(certName string) string {
+func testAccELBConfig_Listener_IAMServerCertificate_AddInvalidListener(certName, key, certificate string) string {
 	return fmt.Sprintf(`
 data "aws_availability_zones" "available" {}
 
-%[1]s
-
 resource "aws_iam_server_certificate" "test_cert" {
-  name             = "%[2]s"
-  certificate_body = "${tls_self_signed_cert.example.cert_pem}"
-  private_key      = "${tls_private_key.example.private_key_pem}"
+  name             = "%[1]s"
+  certificate_body = "%[2]s"
+  private_key      = "%[3]s"
 }
 
 resource "aws_elb" "bar" {
@@ -1755,7 +1560,7 @@ resource "aws_elb" "bar" {
     ssl_certificate_id = "${aws_iam_server_certificate.test_cert.arn}"
   }
 }
-`, testAccTLSServerCert, certName)
+`, certName, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key))
 }
 
 const testAccAWSELBConfig_subnets = `
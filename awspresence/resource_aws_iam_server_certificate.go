@@ -0,0 +1,319 @@
+package awspresence
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+const (
+	// iamServerCertificatePropagationOccurrences is the number of consecutive
+	// successful reads required before a new certificate is considered visible
+	// to the services that reference it.
+	iamServerCertificatePropagationOccurrences = 5
+)
+
+func resourceAwsIAMServerCertificate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsIAMServerCertificateCreate,
+		Read:   resourceAwsIAMServerCertificateRead,
+		Delete: resourceAwsIAMServerCertificateDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAwsIAMServerCertificateImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(2 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"certificate_body": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: normalizeCert,
+			},
+
+			"certificate_chain": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				ForceNew:  true,
+				StateFunc: normalizeCert,
+			},
+
+			"path": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "/",
+				ForceNew:     true,
+				ValidateFunc: validate.IamServerCertificatePath,
+			},
+
+			"private_key": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: normalizeCert,
+				Sensitive: true,
+			},
+
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"name_prefix"},
+				ValidateFunc:  validation.StringLenBetween(0, 128),
+			},
+
+			"name_prefix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(0, 128-resource.UniqueIDSuffixLength),
+			},
+
+			"arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAwsIAMServerCertificateCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	var sslCertName string
+	if v, ok := d.GetOk("name"); ok {
+		sslCertName = v.(string)
+	} else if v, ok := d.GetOk("name_prefix"); ok {
+		sslCertName = resource.PrefixedUniqueId(v.(string))
+	} else {
+		sslCertName = resource.UniqueId()
+	}
+
+	createOpts := &iam.UploadServerCertificateInput{
+		CertificateBody:       aws.String(d.Get("certificate_body").(string)),
+		PrivateKey:            aws.String(d.Get("private_key").(string)),
+		ServerCertificateName: aws.String(sslCertName),
+		Path:                  aws.String(d.Get("path").(string)),
+	}
+
+	if v, ok := d.GetOk("certificate_chain"); ok {
+		createOpts.CertificateChain = aws.String(v.(string))
+	}
+
+	log.Printf("[DEBUG] Creating IAM Server Certificate: %s", sslCertName)
+	resp, err := conn.UploadServerCertificate(createOpts)
+
+	// IAM expects the chain to be ordered from the issuer of the certificate
+	// up to the root. Chains exported root first are rejected as malformed,
+	// so retry once with the chain reversed before giving up.
+	if isAWSErr(err, iam.ErrCodeMalformedCertificateException, "") && createOpts.CertificateChain != nil {
+		if chain, ok := reverseIamServerCertificateChain(aws.StringValue(createOpts.CertificateChain)); ok {
+			log.Printf("[WARN] IAM Server Certificate (%s) chain rejected, retrying with the chain reversed: %s", sslCertName, err)
+			createOpts.CertificateChain = aws.String(chain)
+			resp, err = conn.UploadServerCertificate(createOpts)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error uploading IAM Server Certificate (%s): %s", sslCertName, err)
+	}
+
+	d.SetId(aws.StringValue(resp.ServerCertificateMetadata.ServerCertificateId))
+	d.Set("name", sslCertName)
+
+	if err := waitForIamServerCertificatePropagation(conn, sslCertName, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return fmt.Errorf("Error waiting for IAM Server Certificate (%s) to propagate: %s", sslCertName, err)
+	}
+
+	return resourceAwsIAMServerCertificateRead(d, meta)
+}
+
+func resourceAwsIAMServerCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	resp, err := conn.GetServerCertificate(&iam.GetServerCertificateInput{
+		ServerCertificateName: aws.String(d.Get("name").(string)),
+	})
+	if isAWSErr(err, iam.ErrCodeNoSuchEntityException, "") {
+		log.Printf("[WARN] IAM Server Certificate (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading IAM Server Certificate (%s): %s", d.Id(), err)
+	}
+
+	metadata := resp.ServerCertificate.ServerCertificateMetadata
+	d.SetId(aws.StringValue(metadata.ServerCertificateId))
+
+	// these values should always be present, and have a default if not set in
+	// configuration, and so safe to reference with nil checks
+	d.Set("certificate_body", normalizeCert(resp.ServerCertificate.CertificateBody))
+
+	if c := iamServerCertificateChainState(aws.StringValue(resp.ServerCertificate.CertificateChain), d.Get("certificate_chain").(string)); c != "" {
+		d.Set("certificate_chain", c)
+	}
+
+	d.Set("path", metadata.Path)
+	d.Set("arn", metadata.Arn)
+
+	return nil
+}
+
+func resourceAwsIAMServerCertificateDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*AWSClient).iamconn
+
+	log.Printf("[INFO] Deleting IAM Server Certificate: %s", d.Id())
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		_, err := conn.DeleteServerCertificate(&iam.DeleteServerCertificateInput{
+			ServerCertificateName: aws.String(d.Get("name").(string)),
+		})
+		if isAWSErr(err, iam.ErrCodeDeleteConflictException, "currently in use by arn") {
+			log.Printf("[WARN] Conflict deleting IAM Server Certificate, retrying: %s", err)
+			return resource.RetryableError(err)
+		}
+		if isAWSErr(err, iam.ErrCodeNoSuchEntityException, "") {
+			return nil
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error deleting IAM Server Certificate (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsIAMServerCertificateImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("name", d.Id())
+	// private_key can't be fetched from any API call
+	return []*schema.ResourceData{d}, nil
+}
+
+// waitForIamServerCertificatePropagation waits until a newly uploaded
+// certificate has been readable several times in a row. IAM is eventually
+// consistent, and load balancer listeners created right after the upload
+// otherwise fail with CertificateNotFound.
+func waitForIamServerCertificatePropagation(conn *iam.IAM, name string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{"available"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := conn.GetServerCertificate(&iam.GetServerCertificateInput{
+				ServerCertificateName: aws.String(name),
+			})
+			if isAWSErr(err, iam.ErrCodeNoSuchEntityException, "") {
+				return name, "pending", nil
+			}
+			if err != nil {
+				return nil, "", err
+			}
+
+			return resp, "available", nil
+		},
+		Timeout:                   timeout,
+		MinTimeout:                2 * time.Second,
+		ContinuousTargetOccurence: iamServerCertificatePropagationOccurrences,
+	}
+
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+// reverseIamServerCertificateChain returns chain with its PEM certificates in
+// reverse order. The second return value is false when the chain holds fewer
+// than two certificates, or anything other than certificates, in which case
+// reordering cannot help.
+func reverseIamServerCertificateChain(chain string) (string, bool) {
+	var blocks []*pem.Block
+
+	rest := []byte(chain)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != pemBlockTypeCertificate {
+			return "", false
+		}
+		blocks = append(blocks, block)
+	}
+
+	if len(blocks) < 2 || len(strings.TrimSpace(string(rest))) != 0 {
+		return "", false
+	}
+
+	var reversed []string
+	for i := len(blocks) - 1; i >= 0; i-- {
+		reversed = append(reversed, string(pem.EncodeToMemory(blocks[i])))
+	}
+
+	return strings.Join(reversed, ""), true
+}
+
+// iamServerCertificateChainState returns the normalized chain to store for the
+// chain IAM returns. When the chain was uploaded reversed, as Create does for
+// chains IAM rejects as malformed, the normalized chain already in state is
+// kept, so that the reversal does not show as a change of the configuration.
+func iamServerCertificateChainState(remote, current string) string {
+	if remote == "" {
+		return ""
+	}
+	if reversed, ok := reverseIamServerCertificateChain(remote); ok && current != "" && normalizeCert(reversed) == current {
+		return current
+	}
+	return normalizeCert(remote)
+}
+
+func normalizeCert(cert interface{}) string {
+	if cert == nil || cert == (*string)(nil) {
+		return ""
+	}
+
+	var rawCert string
+	switch cert := cert.(type) {
+	case string:
+		rawCert = cert
+	case *string:
+		rawCert = *cert
+	default:
+		return ""
+	}
+
+	cleanVal := sha1.Sum(stripCR([]byte(strings.TrimSpace(rawCert))))
+	return hex.EncodeToString(cleanVal[:])
+}
+
+// strip CRs from raw literals. Lifted from go/scanner/scanner.go
+// See https://github.com/golang/go/blob/release-branch.go1.6/src/go/scanner/scanner.go#L479
+func stripCR(b []byte) []byte {
+	c := make([]byte, len(b))
+	i := 0
+	for _, ch := range b {
+		if ch != '\r' {
+			c[i] = ch
+			i++
+		}
+	}
+	return c[:i]
+}
@@ -0,0 +1,228 @@
+package awspresence
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestReverseIamServerCertificateChain(t *testing.T) {
+	key := tlsRsaPrivateKeyPem(1024)
+	intermediate := tlsRsaX509SelfSignedCertificatePem(key, "intermediate.example.com")
+	root := tlsRsaX509SelfSignedCertificatePem(key, "root.example.com")
+
+	reversed, ok := reverseIamServerCertificateChain(root + "\n" + intermediate)
+	if !ok {
+		t.Fatal("expected a two certificate chain to be reversed")
+	}
+	if reversed != intermediate+root {
+		t.Fatalf("expected the intermediate certificate first, got:\n%s", reversed)
+	}
+
+	cases := map[string]string{
+		"empty":             "",
+		"single":            root,
+		"private key":       root + key,
+		"trailing garbage":  root + intermediate + "garbage",
+		"not a certificate": "garbage",
+	}
+
+	for name, chain := range cases {
+		if _, ok := reverseIamServerCertificateChain(chain); ok {
+			t.Fatalf("%s: expected chain not to be reversed", name)
+		}
+	}
+}
+
+func TestIamServerCertificateChainState(t *testing.T) {
+	key := tlsRsaPrivateKeyPem(1024)
+	intermediate := tlsRsaX509SelfSignedCertificatePem(key, "intermediate.example.com")
+	root := tlsRsaX509SelfSignedCertificatePem(key, "root.example.com")
+	configured := normalizeCert(root + intermediate)
+
+	cases := []struct {
+		name     string
+		remote   string
+		current  string
+		expected string
+	}{
+		{"same order", root + intermediate, configured, configured},
+		{"same order with CRs and whitespace", "\r\n" + strings.Replace(root+intermediate, "\n", "\r\n", -1) + "\n", configured, configured},
+		{"uploaded reversed", intermediate + root, configured, configured},
+		{"imported reversed", intermediate + root, "", normalizeCert(intermediate + root)},
+		{"other chain", intermediate, configured, normalizeCert(intermediate)},
+		{"no chain", "", configured, ""},
+	}
+
+	for _, tc := range cases {
+		if actual := iamServerCertificateChainState(tc.remote, tc.current); actual != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestAccAWSIAMServerCertificate_basic(t *testing.T) {
+	var cert iam.ServerCertificate
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "aws_iam_server_certificate.test"
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckIAMServerCertificateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIAMServerCertificateConfig(rName, key, certificate),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCertExists(resourceName, &cert),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "path", "/"),
+					resource.TestCheckResourceAttrSet(resourceName, "arn"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           rName,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"private_key"},
+			},
+		},
+	})
+}
+
+func TestAccAWSIAMServerCertificate_Path(t *testing.T) {
+	var cert iam.ServerCertificate
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "aws_iam_server_certificate.test"
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckIAMServerCertificateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIAMServerCertificateConfigPath(rName, "/cloudfront/test/", key, certificate),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCertExists(resourceName, &cert),
+					resource.TestCheckResourceAttr(resourceName, "path", "/cloudfront/test/"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSIAMServerCertificate_disappears(t *testing.T) {
+	var cert iam.ServerCertificate
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "aws_iam_server_certificate.test"
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckIAMServerCertificateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIAMServerCertificateConfig(rName, key, certificate),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCertExists(resourceName, &cert),
+					testAccCheckIAMServerCertificateDisappears(&cert),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccCheckCertExists(n string, cert *iam.ServerCertificate) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Server Cert ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).iamconn
+		describeOpts := &iam.GetServerCertificateInput{
+			ServerCertificateName: aws.String(rs.Primary.Attributes["name"]),
+		}
+		resp, err := conn.GetServerCertificate(describeOpts)
+		if err != nil {
+			return err
+		}
+
+		*cert = *resp.ServerCertificate
+
+		return nil
+	}
+}
+
+func testAccCheckIAMServerCertificateDisappears(cert *iam.ServerCertificate) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*AWSClient).iamconn
+
+		_, err := conn.DeleteServerCertificate(&iam.DeleteServerCertificateInput{
+			ServerCertificateName: cert.ServerCertificateMetadata.ServerCertificateName,
+		})
+
+		return err
+	}
+}
+
+func testAccCheckIAMServerCertificateDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).iamconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_iam_server_certificate" {
+			continue
+		}
+
+		_, err := conn.GetServerCertificate(&iam.GetServerCertificateInput{
+			ServerCertificateName: aws.String(rs.Primary.Attributes["name"]),
+		})
+		if err == nil {
+			return fmt.Errorf("IAM Server Certificate %s still exists", rs.Primary.ID)
+		}
+		if !isAWSErr(err, iam.ErrCodeNoSuchEntityException, "") {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccIAMServerCertificateConfig(rName, key, certificate string) string {
+	return fmt.Sprintf(`
+resource "aws_iam_server_certificate" "test" {
+  name             = %[1]q
+  certificate_body = "%[2]s"
+  private_key      = "%[3]s"
+}
+`, rName, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key))
+}
+
+func testAccIAMServerCertificateConfigPath(rName, path, key, certificate string) string {
+	return fmt.Sprintf(`
+resource "aws_iam_server_certificate" "test" {
+  name             = %[1]q
+  path             = %[2]q
+  certificate_body = "%[3]s"
+  private_key      = "%[4]s"
+}
+`, rName, path, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key))
+}
@@ -5,15 +5,18 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func resourceAwsLb() *schema.Resource {
@@ -51,7 +54,7 @@ func resourceAwsLb() *schema.Resource {
 				Computed:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"name_prefix"},
-				ValidateFunc:  validateElbName,
+				ValidateFunc:  validate.ElbName,
 			},
 
 			"name_prefix": {
@@ -59,7 +62,7 @@ func resourceAwsLb() *schema.Resource {
 				Optional:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"name"},
-				ValidateFunc:  validateElbNamePrefix,
+				ValidateFunc:  validate.ElbNamePrefix,
 			},
 
 			"internal": {
@@ -122,6 +125,39 @@ func resourceAwsLb() *schema.Resource {
 				},
 			},
 
+			"availability_zone_addresses": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"availability_zone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"subnet_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dns_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"network_interface_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"private_ipv4_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"public_ipv4_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
 			"access_logs": {
 				Type:             schema.TypeList,
 				Optional:         true,
@@ -156,6 +192,15 @@ func resourceAwsLb() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Get("enforce_deletion_protection").(bool)
+				},
+			},
+
+			"enforce_deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 
 			"idle_timeout": {
@@ -179,6 +224,45 @@ func resourceAwsLb() *schema.Resource {
 				DiffSuppressFunc: suppressIfLBType("network"),
 			},
 
+			"enable_zonal_shift": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"preserve_host_header": {
+				Type:             schema.TypeBool,
+				Optional:         true,
+				Default:          false,
+				DiffSuppressFunc: suppressIfLBType("network"),
+			},
+
+			"enable_xff_client_port": {
+				Type:             schema.TypeBool,
+				Optional:         true,
+				Default:          false,
+				DiffSuppressFunc: suppressIfLBType("network"),
+			},
+
+			"enable_tls_version_and_cipher_suite_headers": {
+				Type:             schema.TypeBool,
+				Optional:         true,
+				Default:          false,
+				DiffSuppressFunc: suppressIfLBType("network"),
+			},
+
+			"xff_header_processing_mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "append",
+				ValidateFunc: validation.StringInSlice([]string{
+					"append",
+					"preserve",
+					"remove",
+				}, false),
+				DiffSuppressFunc: suppressIfLBType("network"),
+			},
+
 			"ip_address_type": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -201,6 +285,8 @@ func resourceAwsLb() *schema.Resource {
 			},
 
 			"tags": tagsSchema(),
+
+			"region": regionSchema(),
 		},
 	}
 }
@@ -212,7 +298,7 @@ func suppressIfLBType(t string) schema.SchemaDiffSuppressFunc {
 }
 
 func resourceAwsLbCreate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	var name string
 	if v, ok := d.GetOk("name"); ok {
@@ -310,7 +396,7 @@ func resourceAwsLbCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsLbRead(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 	lbArn := d.Id()
 
 	describeLbOpts := &elbv2.DescribeLoadBalancersInput{
@@ -332,11 +418,22 @@ func resourceAwsLbRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to find ALB: %#v", describeResp.LoadBalancers)
 	}
 
-	return flattenAwsLbResource(d, meta, describeResp.LoadBalancers[0])
+	if err := flattenAwsLbResource(d, meta, describeResp.LoadBalancers[0]); err != nil {
+		return err
+	}
+
+	// Recording the enforcement as lapsed makes the next plan show an update
+	// that turns deletion protection back on.
+	if d.Get("enforce_deletion_protection").(bool) && !d.Get("enable_deletion_protection").(bool) {
+		log.Printf("[WARN] LB (%s) deletion protection was disabled outside of Terraform", d.Id())
+		d.Set("enforce_deletion_protection", false)
+	}
+
+	return nil
 }
 
 func resourceAwsLbUpdate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	if !d.IsNewResource() {
 		if err := setElbV2Tags(elbconn, d); err != nil {
@@ -392,6 +489,32 @@ func resourceAwsLbUpdate(d *schema.ResourceData, meta interface{}) error {
 				Value: aws.String(strconv.FormatBool(d.Get("enable_http2").(bool))),
 			})
 		}
+		// Like zonal shift, the header attributes are only sent on change, so
+		// that they are left alone while at their defaults.
+		if d.HasChange("preserve_host_header") {
+			attributes = append(attributes, &elbv2.LoadBalancerAttribute{
+				Key:   aws.String("routing.http.preserve_host_header.enabled"),
+				Value: aws.String(strconv.FormatBool(d.Get("preserve_host_header").(bool))),
+			})
+		}
+		if d.HasChange("enable_xff_client_port") {
+			attributes = append(attributes, &elbv2.LoadBalancerAttribute{
+				Key:   aws.String("routing.http.xff_client_port.enabled"),
+				Value: aws.String(strconv.FormatBool(d.Get("enable_xff_client_port").(bool))),
+			})
+		}
+		if d.HasChange("enable_tls_version_and_cipher_suite_headers") {
+			attributes = append(attributes, &elbv2.LoadBalancerAttribute{
+				Key:   aws.String("routing.http.x_amzn_tls_version_and_cipher_suite.enabled"),
+				Value: aws.String(strconv.FormatBool(d.Get("enable_tls_version_and_cipher_suite_headers").(bool))),
+			})
+		}
+		if d.HasChange("xff_header_processing_mode") && !(d.IsNewResource() && d.Get("xff_header_processing_mode").(string) == "append") {
+			attributes = append(attributes, &elbv2.LoadBalancerAttribute{
+				Key:   aws.String("routing.http.xff_header_processing.mode"),
+				Value: aws.String(d.Get("xff_header_processing_mode").(string)),
+			})
+		}
 	case "network":
 		if d.HasChange("enable_cross_zone_load_balancing") || d.IsNewResource() {
 			attributes = append(attributes, &elbv2.LoadBalancerAttribute{
@@ -401,10 +524,19 @@ func resourceAwsLbUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	if d.HasChange("enable_deletion_protection") || d.IsNewResource() {
+	if d.HasChange("enable_deletion_protection") || d.HasChange("enforce_deletion_protection") || d.IsNewResource() {
 		attributes = append(attributes, &elbv2.LoadBalancerAttribute{
 			Key:   aws.String("deletion_protection.enabled"),
-			Value: aws.String(fmt.Sprintf("%t", d.Get("enable_deletion_protection").(bool))),
+			Value: aws.String(fmt.Sprintf("%t", lbDeletionProtectionEnabled(d))),
+		})
+	}
+
+	// Zonal shift is only sent on change so that API implementations without
+	// Route 53 ARC support are unaffected while it is left at the default.
+	if d.HasChange("enable_zonal_shift") {
+		attributes = append(attributes, &elbv2.LoadBalancerAttribute{
+			Key:   aws.String("zonal_shift.config.enabled"),
+			Value: aws.String(strconv.FormatBool(d.Get("enable_zonal_shift").(bool))),
 		})
 	}
 
@@ -500,7 +632,8 @@ func resourceAwsLbUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsLbDelete(d *schema.ResourceData, meta interface{}) error {
-	lbconn := meta.(*AWSClient).elbv2conn
+	client := resourceAWSClient(d, meta)
+	lbconn := client.elbv2conn
 
 	log.Printf("[INFO] Deleting LB: %s", d.Id())
 
@@ -509,10 +642,18 @@ func resourceAwsLbDelete(d *schema.ResourceData, meta interface{}) error {
 		LoadBalancerArn: aws.String(d.Id()),
 	}
 	if _, err := lbconn.DeleteLoadBalancer(&deleteElbOpts); err != nil {
+		if isLbNotFoundErr(err) {
+			log.Printf("[WARN] LB %s already deleted", d.Id())
+			return nil
+		}
 		return fmt.Errorf("Error deleting LB: %s", err)
 	}
 
-	conn := meta.(*AWSClient).ec2conn
+	// There are no network interfaces to clean up or wait for.
+	if client.checkLocalstackFeature(localstackFeatureLbNetworkInterfaces) != nil {
+		return nil
+	}
+	conn := client.ec2conn
 
 	err := cleanupLBNetworkInterfaces(conn, d.Id())
 	if err != nil {
@@ -613,6 +754,12 @@ func waitForNLBNetworkInterfacesToDetach(conn *ec2.EC2, lbArn string) error {
 	})
 }
 
+// lbDeletionProtectionEnabled returns whether deletion protection should be
+// enabled on the load balancer. enforce_deletion_protection implies it.
+func lbDeletionProtectionEnabled(d *schema.ResourceData) bool {
+	return d.Get("enable_deletion_protection").(bool) || d.Get("enforce_deletion_protection").(bool)
+}
+
 func getLbNameFromArn(arn string) (string, error) {
 	re := regexp.MustCompile("([^/]+/[^/]+/[^/]+)$")
 	matches := re.FindStringSubmatch(arn)
@@ -624,6 +771,73 @@ func getLbNameFromArn(arn string) (string, error) {
 	return matches[1], nil
 }
 
+// describeLbAvailabilityZoneAddresses returns, for each availability zone of
+// a network load balancer, its zonal DNS name and the network interface the
+// load balancer has in the zone.
+func describeLbAvailabilityZoneAddresses(client *AWSClient, lb *elbv2.LoadBalancer) ([]map[string]interface{}, error) {
+	if client.checkLocalstackFeature(localstackFeatureLbNetworkInterfaces) != nil {
+		return flattenLbAvailabilityZoneAddresses(lb, nil), nil
+	}
+
+	name, err := getLbNameFromArn(aws.StringValue(lb.LoadBalancerArn))
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.ec2conn.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("attachment.instance-owner-id"),
+				Values: []*string{aws.String("amazon-elb")},
+			},
+			{
+				Name:   aws.String("description"),
+				Values: []*string{aws.String("ELB " + name)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenLbAvailabilityZoneAddresses(lb, out.NetworkInterfaces), nil
+}
+
+// flattenLbAvailabilityZoneAddresses pairs the availability zones of a load
+// balancer with its network interfaces by subnet, sorted by zone name.
+func flattenLbAvailabilityZoneAddresses(lb *elbv2.LoadBalancer, enis []*ec2.NetworkInterface) []map[string]interface{} {
+	enisBySubnet := make(map[string]*ec2.NetworkInterface, len(enis))
+	for _, eni := range enis {
+		enisBySubnet[aws.StringValue(eni.SubnetId)] = eni
+	}
+
+	result := make([]map[string]interface{}, 0, len(lb.AvailabilityZones))
+	for _, az := range lb.AvailabilityZones {
+		m := map[string]interface{}{
+			"availability_zone":    aws.StringValue(az.ZoneName),
+			"subnet_id":            aws.StringValue(az.SubnetId),
+			"dns_name":             aws.StringValue(az.ZoneName) + "." + aws.StringValue(lb.DNSName),
+			"network_interface_id": "",
+			"private_ipv4_address": "",
+			"public_ipv4_address":  "",
+		}
+		if eni, ok := enisBySubnet[aws.StringValue(az.SubnetId)]; ok {
+			m["network_interface_id"] = aws.StringValue(eni.NetworkInterfaceId)
+			m["private_ipv4_address"] = aws.StringValue(eni.PrivateIpAddress)
+			if eni.Association != nil {
+				m["public_ipv4_address"] = aws.StringValue(eni.Association.PublicIp)
+			}
+		}
+		result = append(result, m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i]["availability_zone"].(string) < result[j]["availability_zone"].(string)
+	})
+
+	return result
+}
+
 // flattenSubnetsFromAvailabilityZones creates a slice of strings containing the subnet IDs
 // for the ALB based on the AvailabilityZones structure returned by the API.
 func flattenSubnetsFromAvailabilityZones(availabilityZones []*elbv2.AvailabilityZone) []string {
@@ -665,7 +879,7 @@ func lbSuffixFromARN(arn *string) string {
 
 // flattenAwsLbResource takes a *elbv2.LoadBalancer and populates all respective resource fields.
 func flattenAwsLbResource(d *schema.ResourceData, meta interface{}, lb *elbv2.LoadBalancer) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	d.Set("arn", lb.LoadBalancerArn)
 	d.Set("arn_suffix", lbSuffixFromARN(lb.LoadBalancerArn))
@@ -677,6 +891,7 @@ func flattenAwsLbResource(d *schema.ResourceData, meta interface{}, lb *elbv2.Lo
 	d.Set("dns_name", lb.DNSName)
 	d.Set("ip_address_type", lb.IpAddressType)
 	d.Set("load_balancer_type", lb.Type)
+	d.Set("region", resourceAWSClient(d, meta).region)
 
 	if err := d.Set("subnets", flattenSubnetsFromAvailabilityZones(lb.AvailabilityZones)); err != nil {
 		return fmt.Errorf("error setting subnets: %s", err)
@@ -686,6 +901,18 @@ func flattenAwsLbResource(d *schema.ResourceData, meta interface{}, lb *elbv2.Lo
 		return fmt.Errorf("error setting subnet_mapping: %s", err)
 	}
 
+	var zoneAddresses []map[string]interface{}
+	if aws.StringValue(lb.Type) == elbv2.LoadBalancerTypeEnumNetwork {
+		var err error
+		zoneAddresses, err = describeLbAvailabilityZoneAddresses(resourceAWSClient(d, meta), lb)
+		if err != nil {
+			return fmt.Errorf("Error retrieving network interfaces of LB %s: %s", d.Id(), err)
+		}
+	}
+	if err := d.Set("availability_zone_addresses", zoneAddresses); err != nil {
+		return fmt.Errorf("error setting availability_zone_addresses: %s", err)
+	}
+
 	respTags, err := elbconn.DescribeTags(&elbv2.DescribeTagsInput{
 		ResourceArns: []*string{lb.LoadBalancerArn},
 	})
@@ -742,6 +969,25 @@ func flattenAwsLbResource(d *schema.ResourceData, meta interface{}, lb *elbv2.Lo
 			crossZoneLbEnabled := aws.StringValue(attr.Value) == "true"
 			log.Printf("[DEBUG] Setting NLB Cross Zone Load Balancing Enabled: %t", crossZoneLbEnabled)
 			d.Set("enable_cross_zone_load_balancing", crossZoneLbEnabled)
+		case "zonal_shift.config.enabled":
+			zonalShiftEnabled := aws.StringValue(attr.Value) == "true"
+			log.Printf("[DEBUG] Setting LB Zonal Shift Enabled: %t", zonalShiftEnabled)
+			d.Set("enable_zonal_shift", zonalShiftEnabled)
+		case "routing.http.preserve_host_header.enabled":
+			preserveHostHeader := aws.StringValue(attr.Value) == "true"
+			log.Printf("[DEBUG] Setting ALB Preserve Host Header: %t", preserveHostHeader)
+			d.Set("preserve_host_header", preserveHostHeader)
+		case "routing.http.xff_client_port.enabled":
+			xffClientPortEnabled := aws.StringValue(attr.Value) == "true"
+			log.Printf("[DEBUG] Setting ALB XFF Client Port Enabled: %t", xffClientPortEnabled)
+			d.Set("enable_xff_client_port", xffClientPortEnabled)
+		case "routing.http.x_amzn_tls_version_and_cipher_suite.enabled":
+			tlsHeadersEnabled := aws.StringValue(attr.Value) == "true"
+			log.Printf("[DEBUG] Setting ALB TLS Version And Cipher Suite Headers Enabled: %t", tlsHeadersEnabled)
+			d.Set("enable_tls_version_and_cipher_suite_headers", tlsHeadersEnabled)
+		case "routing.http.xff_header_processing.mode":
+			log.Printf("[DEBUG] Setting ALB XFF Header Processing Mode: %s", aws.StringValue(attr.Value))
+			d.Set("xff_header_processing_mode", attr.Value)
 		}
 	}
 
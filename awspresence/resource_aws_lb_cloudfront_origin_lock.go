@@ -0,0 +1,412 @@
+package awspresence
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+const (
+	lbCloudfrontOriginFacingPrefixListName = "com.amazonaws.global.cloudfront.origin-facing"
+	lbCloudfrontOriginLockDefaultHeader    = "X-Origin-Verify"
+	lbCloudfrontOriginLockRuleDescription  = "CloudFront origin-facing"
+)
+
+func resourceAwsLbCloudfrontOriginLock() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsLbCloudfrontOriginLockCreate,
+		Read:   resourceAwsLbCloudfrontOriginLockRead,
+		Update: resourceAwsLbCloudfrontOriginLockUpdate,
+		Delete: resourceAwsLbCloudfrontOriginLockDelete,
+
+		CustomizeDiff: resourceAwsLbCloudfrontOriginLockCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"security_group_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      443,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"prefix_list_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validate.PrefixListId,
+			},
+			"security_group_rule_missing": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"header_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      lbCloudfrontOriginLockDefaultHeader,
+				ValidateFunc: validation.StringLenBetween(1, 40),
+			},
+			"header_value": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"header_values": {
+				Type:      schema.TypeList,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+			},
+			"rotate_secret_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validate.Duration(time.Hour),
+			},
+			"rotated_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"secret_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"secret_arn": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"kms_key_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"recovery_window_in_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validate.SecretsManagerRecoveryWindow,
+			},
+
+			"region": regionSchema(),
+		},
+	}
+}
+
+func resourceAwsLbCloudfrontOriginLockCreate(d *schema.ResourceData, meta interface{}) error {
+	client := resourceAWSClient(d, meta)
+
+	prefixListId := d.Get("prefix_list_id").(string)
+	if prefixListId == "" {
+		var err error
+		prefixListId, err = managedPrefixListIdByName(client.ec2conn, lbCloudfrontOriginFacingPrefixListName)
+		if err != nil {
+			return fmt.Errorf("Error looking up CloudFront origin-facing prefix list: %s", err)
+		}
+		d.Set("prefix_list_id", prefixListId)
+	}
+
+	headerValue, err := generateLbCloudfrontOriginLockHeaderValue()
+	if err != nil {
+		return err
+	}
+
+	input := &secretsmanager.CreateSecretInput{
+		Name:         aws.String(d.Get("secret_name").(string)),
+		Description:  aws.String(fmt.Sprintf("Value of the %s header CloudFront sends to the load balancer", d.Get("header_name").(string))),
+		SecretString: aws.String(headerValue),
+	}
+	if v, ok := d.GetOk("kms_key_id"); ok {
+		input.KmsKeyId = aws.String(v.(string))
+	}
+
+	log.Printf("[DEBUG] Creating Secrets Manager secret: %s", aws.StringValue(input.Name))
+	resp, err := client.secretsmanagerconn.CreateSecret(input)
+	if err != nil {
+		return fmt.Errorf("Error creating Secrets Manager secret: %s", err)
+	}
+	d.SetId(aws.StringValue(resp.ARN))
+
+	if err := authorizeLbCloudfrontOriginLockIngress(client.ec2conn, d.Get("security_group_id").(string), prefixListId, d.Get("port").(int)); err != nil {
+		return err
+	}
+
+	return resourceAwsLbCloudfrontOriginLockRead(d, meta)
+}
+
+func resourceAwsLbCloudfrontOriginLockRead(d *schema.ResourceData, meta interface{}) error {
+	client := resourceAWSClient(d, meta)
+
+	secret, err := client.secretsmanagerconn.DescribeSecret(&secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(d.Id()),
+	})
+	if isAWSErrCode(err, secretsmanager.ErrCodeResourceNotFoundException) {
+		log.Printf("[WARN] Secrets Manager secret (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error describing Secrets Manager secret %s: %s", d.Id(), err)
+	}
+	if secret.DeletedDate != nil {
+		log.Printf("[WARN] Secrets Manager secret (%s) scheduled for deletion, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	value, err := client.secretsmanagerconn.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(d.Id()),
+	})
+	if err != nil {
+		return fmt.Errorf("Error reading value of Secrets Manager secret %s: %s", d.Id(), err)
+	}
+
+	// The previous value stays accepted until the next rotation, so that
+	// distributions can be updated after the rules.
+	headerValues := []string{aws.StringValue(value.SecretString)}
+	previous, err := client.secretsmanagerconn.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId:     aws.String(d.Id()),
+		VersionStage: aws.String("AWSPREVIOUS"),
+	})
+	if err != nil && !isAWSErrCode(err, secretsmanager.ErrCodeResourceNotFoundException) {
+		return fmt.Errorf("Error reading previous value of Secrets Manager secret %s: %s", d.Id(), err)
+	}
+	if err == nil {
+		headerValues = append(headerValues, aws.StringValue(previous.SecretString))
+	}
+
+	d.Set("secret_arn", secret.ARN)
+	d.Set("secret_name", secret.Name)
+	d.Set("kms_key_id", secret.KmsKeyId)
+	d.Set("header_value", value.SecretString)
+	if err := d.Set("header_values", headerValues); err != nil {
+		return fmt.Errorf("error setting header_values: %s", err)
+	}
+	if value.CreatedDate != nil {
+		d.Set("rotated_at", aws.TimeValue(value.CreatedDate).UTC().Format(time.RFC3339))
+	}
+	d.Set("region", client.region)
+
+	securityGroupId := d.Get("security_group_id").(string)
+	resp, err := client.ec2conn.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: aws.StringSlice([]string{securityGroupId}),
+	})
+	if err != nil {
+		return fmt.Errorf("Error describing security group %s: %s", securityGroupId, err)
+	}
+
+	found := false
+	if len(resp.SecurityGroups) == 1 {
+		found = lbCloudfrontOriginLockIngressExists(resp.SecurityGroups[0], d.Get("prefix_list_id").(string), d.Get("port").(int))
+	}
+	if !found {
+		log.Printf("[WARN] Ingress from prefix list %s not found in security group %s", d.Get("prefix_list_id").(string), securityGroupId)
+	}
+	d.Set("security_group_rule_missing", !found)
+
+	return nil
+}
+
+func resourceAwsLbCloudfrontOriginLockUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := resourceAWSClient(d, meta)
+	conn := client.ec2conn
+	securityGroupId := d.Get("security_group_id").(string)
+
+	if d.HasChange("rotated_at") {
+		headerValue, err := generateLbCloudfrontOriginLockHeaderValue()
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] Rotating Secrets Manager secret: %s", d.Id())
+		_, err = client.secretsmanagerconn.PutSecretValue(&secretsmanager.PutSecretValueInput{
+			SecretId:     aws.String(d.Id()),
+			SecretString: aws.String(headerValue),
+		})
+		if err != nil {
+			return fmt.Errorf("Error rotating Secrets Manager secret %s: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("port") || d.HasChange("prefix_list_id") || d.HasChange("security_group_rule_missing") {
+		oldPort, newPort := d.GetChange("port")
+		oldPrefixListId, newPrefixListId := d.GetChange("prefix_list_id")
+
+		if err := revokeLbCloudfrontOriginLockIngress(conn, securityGroupId, oldPrefixListId.(string), oldPort.(int)); err != nil {
+			return err
+		}
+		if err := authorizeLbCloudfrontOriginLockIngress(conn, securityGroupId, newPrefixListId.(string), newPort.(int)); err != nil {
+			return err
+		}
+	}
+
+	return resourceAwsLbCloudfrontOriginLockRead(d, meta)
+}
+
+func resourceAwsLbCloudfrontOriginLockDelete(d *schema.ResourceData, meta interface{}) error {
+	client := resourceAWSClient(d, meta)
+
+	if err := revokeLbCloudfrontOriginLockIngress(client.ec2conn, d.Get("security_group_id").(string), d.Get("prefix_list_id").(string), d.Get("port").(int)); err != nil {
+		return err
+	}
+
+	input := &secretsmanager.DeleteSecretInput{
+		SecretId: aws.String(d.Id()),
+	}
+	if v := d.Get("recovery_window_in_days").(int); v == 0 {
+		input.ForceDeleteWithoutRecovery = aws.Bool(true)
+	} else {
+		input.RecoveryWindowInDays = aws.Int64(int64(v))
+	}
+
+	log.Printf("[DEBUG] Deleting Secrets Manager secret: %s", d.Id())
+	_, err := client.secretsmanagerconn.DeleteSecret(input)
+	if isAWSErrCode(err, secretsmanager.ErrCodeResourceNotFoundException) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error deleting Secrets Manager secret %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceAwsLbCloudfrontOriginLockCustomizeDiff plans an update when the last
+// read found the security group rule removed outside of Terraform, and plans
+// a new header value once rotate_secret_after has passed since the last one.
+func resourceAwsLbCloudfrontOriginLockCustomizeDiff(diff *schema.ResourceDiff, v interface{}) error {
+	if diff.Id() == "" {
+		// The origin-facing prefix list is looked up on create when no
+		// prefix_list_id is configured.
+		if client, ok := v.(*AWSClient); ok && diff.NewValueKnown("prefix_list_id") && diff.Get("prefix_list_id").(string) == "" {
+			if err := client.checkLocalstackFeature(localstackFeatureManagedPrefixLists); err != nil {
+				return fmt.Errorf("Error looking up CloudFront origin-facing prefix list: %s", err)
+			}
+		}
+		return nil
+	}
+
+	if diff.Get("security_group_rule_missing").(bool) {
+		if err := diff.SetNew("security_group_rule_missing", false); err != nil {
+			return err
+		}
+	}
+
+	if lbCloudfrontOriginLockRotationDue(diff.Get("rotate_secret_after").(string), diff.Get("rotated_at").(string), time.Now()) {
+		log.Printf("[DEBUG] Header value of %s due for rotation", diff.Id())
+		for _, k := range []string{"rotated_at", "header_value", "header_values"} {
+			if err := diff.SetNewComputed(k); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// lbCloudfrontOriginLockRotationDue reports whether the header value created
+// at rotatedAt is older than rotateAfter at now.
+func lbCloudfrontOriginLockRotationDue(rotateAfter, rotatedAt string, now time.Time) bool {
+	if rotateAfter == "" || rotatedAt == "" {
+		return false
+	}
+
+	after, err := time.ParseDuration(rotateAfter)
+	if err != nil {
+		return false
+	}
+	at, err := time.Parse(time.RFC3339, rotatedAt)
+	if err != nil {
+		log.Printf("[WARN] Unexpected rotation time %q: %s", rotatedAt, err)
+		return false
+	}
+
+	return !now.Before(at.Add(after))
+}
+
+func lbCloudfrontOriginLockIpPermission(prefixListId string, port int) *ec2.IpPermission {
+	return &ec2.IpPermission{
+		IpProtocol: aws.String("tcp"),
+		FromPort:   aws.Int64(int64(port)),
+		ToPort:     aws.Int64(int64(port)),
+		PrefixListIds: []*ec2.PrefixListId{
+			{
+				PrefixListId: aws.String(prefixListId),
+				Description:  aws.String(lbCloudfrontOriginLockRuleDescription),
+			},
+		},
+	}
+}
+
+func authorizeLbCloudfrontOriginLockIngress(conn *ec2.EC2, securityGroupId, prefixListId string, port int) error {
+	log.Printf("[DEBUG] Allowing port %d from prefix list %s in security group %s", port, prefixListId, securityGroupId)
+	_, err := conn.AuthorizeSecurityGroupIngress(&ec2.AuthorizeSecurityGroupIngressInput{
+		GroupId:       aws.String(securityGroupId),
+		IpPermissions: []*ec2.IpPermission{lbCloudfrontOriginLockIpPermission(prefixListId, port)},
+	})
+	if isAWSErrCode(err, "InvalidPermission.Duplicate") {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error authorizing ingress from prefix list %s in security group %s: %s", prefixListId, securityGroupId, err)
+	}
+	return nil
+}
+
+func revokeLbCloudfrontOriginLockIngress(conn *ec2.EC2, securityGroupId, prefixListId string, port int) error {
+	if prefixListId == "" {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Revoking port %d from prefix list %s in security group %s", port, prefixListId, securityGroupId)
+	_, err := conn.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+		GroupId:       aws.String(securityGroupId),
+		IpPermissions: []*ec2.IpPermission{lbCloudfrontOriginLockIpPermission(prefixListId, port)},
+	})
+	if isAWSErrCode(err, "InvalidPermission.NotFound", "InvalidGroup.NotFound") {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error revoking ingress from prefix list %s in security group %s: %s", prefixListId, securityGroupId, err)
+	}
+	return nil
+}
+
+// lbCloudfrontOriginLockIngressExists reports whether group allows TCP traffic
+// on port from the prefix list.
+func lbCloudfrontOriginLockIngressExists(group *ec2.SecurityGroup, prefixListId string, port int) bool {
+	for _, permission := range group.IpPermissions {
+		if aws.StringValue(permission.IpProtocol) != "tcp" ||
+			aws.Int64Value(permission.FromPort) > int64(port) || aws.Int64Value(permission.ToPort) < int64(port) {
+			continue
+		}
+		for _, prefixList := range permission.PrefixListIds {
+			if aws.StringValue(prefixList.PrefixListId) == prefixListId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateLbCloudfrontOriginLockHeaderValue returns a random header value. It
+// is hex encoded so that it holds none of the wildcards of http-header rule
+// conditions.
+func generateLbCloudfrontOriginLockHeaderValue() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("Error generating header value: %s", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,235 @@
+package awspresence
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestLbCloudfrontOriginLockIngressExists(t *testing.T) {
+	group := &ec2.SecurityGroup{
+		IpPermissions: []*ec2.IpPermission{
+			{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int64(80),
+				ToPort:     aws.Int64(80),
+				IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+			},
+			{
+				IpProtocol:    aws.String("tcp"),
+				FromPort:      aws.Int64(443),
+				ToPort:        aws.Int64(443),
+				PrefixListIds: []*ec2.PrefixListId{{PrefixListId: aws.String("pl-3b927c52")}},
+			},
+		},
+	}
+
+	if !lbCloudfrontOriginLockIngressExists(group, "pl-3b927c52", 443) {
+		t.Fatal("expected ingress on 443 from the prefix list")
+	}
+	if lbCloudfrontOriginLockIngressExists(group, "pl-3b927c52", 80) {
+		t.Fatal("expected no ingress on 80 from the prefix list")
+	}
+	if lbCloudfrontOriginLockIngressExists(group, "pl-00000000", 443) {
+		t.Fatal("expected no ingress on 443 from another prefix list")
+	}
+}
+
+func TestGenerateLbCloudfrontOriginLockHeaderValue(t *testing.T) {
+	first, err := generateLbCloudfrontOriginLockHeaderValue()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := generateLbCloudfrontOriginLockHeaderValue()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !regexp.MustCompile(`^[0-9a-f]{64}$`).MatchString(first) {
+		t.Fatalf("expected 64 hex characters, got %q", first)
+	}
+	if first == second {
+		t.Fatal("expected distinct header values")
+	}
+}
+
+func TestLbCloudfrontOriginLockRotationDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		rotateAfter string
+		rotatedAt   string
+		expected    bool
+	}{
+		{"", "2026-01-01T00:00:00Z", false},
+		{"720h", "", false},
+		{"720h", "2026-02-20T00:00:00Z", false},
+		{"720h", "2026-01-30T12:00:00Z", true},
+		{"720h", "2026-01-01T00:00:00Z", true},
+		{"24h", "2026-02-28T13:00:00Z", false},
+		{"24h", "not a time", false},
+	}
+
+	for _, tc := range cases {
+		actual := lbCloudfrontOriginLockRotationDue(tc.rotateAfter, tc.rotatedAt, now)
+		if actual != tc.expected {
+			t.Errorf("rotate_secret_after %q, rotated_at %q: expected %t, got %t", tc.rotateAfter, tc.rotatedAt, tc.expected, actual)
+		}
+	}
+}
+
+func TestAccAWSLBCloudfrontOriginLock_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	resourceName := "aws_lb_cloudfront_origin_lock.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t); testAccPreCheckLocalstackFeature(t, localstackFeatureManagedPrefixLists) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBCloudfrontOriginLockDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBCloudfrontOriginLockConfig(rName, 443),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr(resourceName, "prefix_list_id", regexp.MustCompile(`^pl-`)),
+					resource.TestMatchResourceAttr(resourceName, "header_value", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+					resource.TestCheckResourceAttr(resourceName, "header_values.#", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "rotated_at"),
+					resource.TestCheckResourceAttr(resourceName, "header_name", "X-Origin-Verify"),
+					resource.TestCheckResourceAttr(resourceName, "secret_name", rName),
+					resource.TestCheckResourceAttr(resourceName, "security_group_rule_missing", "false"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.test", "condition.#", "1"),
+				),
+			},
+			{
+				Config: testAccAWSLBCloudfrontOriginLockConfig(rName, 8443),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "port", "8443"),
+					resource.TestCheckResourceAttr(resourceName, "security_group_rule_missing", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSLBCloudfrontOriginLockDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).secretsmanagerconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_lb_cloudfront_origin_lock" {
+			continue
+		}
+
+		resp, err := conn.DescribeSecret(&secretsmanager.DescribeSecretInput{
+			SecretId: aws.String(rs.Primary.ID),
+		})
+		if isAWSErrCode(err, secretsmanager.ErrCodeResourceNotFoundException) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if resp.DeletedDate == nil {
+			return fmt.Errorf("Secrets Manager secret %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccAWSLBCloudfrontOriginLockConfig(rName string, port int) string {
+	return fmt.Sprintf(`
+resource "aws_lb_cloudfront_origin_lock" "test" {
+  security_group_id       = "${aws_security_group.test.id}"
+  port                    = %[2]d
+  secret_name             = %[1]q
+  recovery_window_in_days = 0
+}
+
+resource "aws_lb_listener_rule" "test" {
+  listener_arn = "${aws_lb_listener.test.arn}"
+
+  action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.test.arn}"
+  }
+
+  condition {
+    field = "http-header"
+
+    http_header {
+      http_header_name = "${aws_lb_cloudfront_origin_lock.test.header_name}"
+      values           = ["${aws_lb_cloudfront_origin_lock.test.header_value}"]
+    }
+  }
+}
+
+resource "aws_lb_listener" "test" {
+  load_balancer_arn = "${aws_lb.test.id}"
+  protocol          = "HTTP"
+  port              = "80"
+
+  default_action {
+    type = "fixed-response"
+
+    fixed_response {
+      content_type = "text/plain"
+      status_code  = "403"
+    }
+  }
+}
+
+resource "aws_lb" "test" {
+  name            = %[1]q
+  internal        = true
+  security_groups = ["${aws_security_group.test.id}"]
+  subnets         = ["${aws_subnet.test.*.id}"]
+}
+
+resource "aws_lb_target_group" "test" {
+  name     = %[1]q
+  port     = 8080
+  protocol = "HTTP"
+  vpc_id   = "${aws_vpc.test.id}"
+}
+
+data "aws_availability_zones" "available" {
+  state = "available"
+}
+
+resource "aws_vpc" "test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = %[1]q
+  }
+}
+
+resource "aws_subnet" "test" {
+  count             = 2
+  vpc_id            = "${aws_vpc.test.id}"
+  cidr_block        = "10.0.${count.index}.0/24"
+  availability_zone = "${data.aws_availability_zones.available.names[count.index]}"
+
+  tags = {
+    Name = %[1]q
+  }
+}
+
+resource "aws_security_group" "test" {
+  name   = %[1]q
+  vpc_id = "${aws_vpc.test.id}"
+
+  tags = {
+    Name = %[1]q
+  }
+}
+`, rName, port)
+}
@@ -3,10 +3,10 @@ package awspresence
 import (
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
@@ -19,6 +19,9 @@ func resourceAwsLBCookieStickinessPolicy() *schema.Resource {
 		Create: resourceAwsLBCookieStickinessPolicyCreate,
 		Read:   resourceAwsLBCookieStickinessPolicyRead,
 		Delete: resourceAwsLBCookieStickinessPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -97,10 +100,8 @@ func resourceAwsLBCookieStickinessPolicyRead(d *schema.ResourceData, meta interf
 
 	getResp, err := elbconn.DescribeLoadBalancerPolicies(request)
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok {
-			if ec2err.Code() == "PolicyNotFound" || ec2err.Code() == "LoadBalancerNotFound" {
-				d.SetId("")
-			}
+		if isAWSErrCode(err, "PolicyNotFound", "LoadBalancerNotFound") {
+			d.SetId("")
 			return nil
 		}
 		return fmt.Errorf("Error retrieving policy: %s", err)
@@ -122,18 +123,19 @@ func resourceAwsLBCookieStickinessPolicyRead(d *schema.ResourceData, meta interf
 		return nil
 	}
 
-	// We can get away with this because there's only one attribute, the
-	// cookie expiration, in these descriptions.
-	policyDesc := getResp.PolicyDescriptions[0]
-	cookieAttr := policyDesc.PolicyAttributeDescriptions[0]
-	if *cookieAttr.AttributeName != "CookieExpirationPeriod" {
-		return fmt.Errorf("Unable to find cookie expiration period.")
+	cookieExpirationPeriod, err := flattenLBCookieStickinessPolicyExpiration(getResp.PolicyDescriptions[0].PolicyAttributeDescriptions)
+	if err != nil {
+		return err
 	}
-	d.Set("cookie_expiration_period", cookieAttr.AttributeValue)
+	d.Set("cookie_expiration_period", cookieExpirationPeriod)
 
 	d.Set("name", policyName)
 	d.Set("load_balancer", lbName)
-	d.Set("lb_port", lbPort)
+	port, err := strconv.Atoi(lbPort)
+	if err != nil {
+		return fmt.Errorf("Error parsing LB port %q from policy ID %q: %s", lbPort, d.Id(), err)
+	}
+	d.Set("lb_port", port)
 
 	return nil
 }
@@ -153,6 +155,10 @@ func resourceAwsLBCookieStickinessPolicyDelete(d *schema.ResourceData, meta inte
 	}
 
 	if _, err := elbconn.SetLoadBalancerPoliciesOfListener(setLoadBalancerOpts); err != nil {
+		if isLbNotFoundErr(err) {
+			log.Printf("[WARN] Listener of LB stickiness policy %s already deleted", d.Id())
+			return nil
+		}
 		return fmt.Errorf("Error removing LBCookieStickinessPolicy: %s", err)
 	}
 
@@ -161,12 +167,36 @@ func resourceAwsLBCookieStickinessPolicyDelete(d *schema.ResourceData, meta inte
 		PolicyName:       aws.String(policyName),
 	}
 
-	if _, err := elbconn.DeleteLoadBalancerPolicy(request); err != nil {
+	if _, err := elbconn.DeleteLoadBalancerPolicy(request); err != nil && !isLbNotFoundErr(err) {
 		return fmt.Errorf("Error deleting LB stickiness policy %s: %s", d.Id(), err)
 	}
 	return nil
 }
 
+// flattenLBCookieStickinessPolicyExpiration returns the CookieExpirationPeriod
+// from a policy's attribute descriptions. The attribute is reported as "0" or
+// omitted entirely when the cookie never expires.
+func flattenLBCookieStickinessPolicyExpiration(attributes []*elb.PolicyAttributeDescription) (int, error) {
+	for _, attr := range attributes {
+		if aws.StringValue(attr.AttributeName) != "CookieExpirationPeriod" {
+			continue
+		}
+
+		value := aws.StringValue(attr.AttributeValue)
+		if value == "" {
+			return 0, nil
+		}
+
+		period, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("Error parsing cookie expiration period %q: %s", value, err)
+		}
+		return period, nil
+	}
+
+	return 0, nil
+}
+
 // resourceAwsLBCookieStickinessPolicyParseId takes an ID and parses it into
 // it's constituent parts. You need three axes (LB name, policy name, and LB
 // port) to create or identify a stickiness policy in AWS's API.
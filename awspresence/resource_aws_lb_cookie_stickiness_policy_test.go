@@ -38,6 +38,11 @@ func TestAccAWSLBCookieStickinessPolicy_basic(t *testing.T) {
 					),
 				),
 			},
+			{
+				ResourceName:      "aws_lb_cookie_stickiness_policy.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -145,6 +150,116 @@ func TestAccAWSLBCookieStickinessPolicy_drift(t *testing.T) {
 	})
 }
 
+func TestAccAWSLBCookieStickinessPolicy_expirationDrift(t *testing.T) {
+	lbName := fmt.Sprintf("tf-test-lb-%s", acctest.RandString(5))
+
+	// Policies are immutable, so an out of band expiration change is made the
+	// same way the CLI does it: detach, delete and recreate under the same name.
+	changeExpiration := func() {
+		conn := testAccProvider.Meta().(*AWSClient).elbconn
+
+		_, err := conn.SetLoadBalancerPoliciesOfListener(&elb.SetLoadBalancerPoliciesOfListenerInput{
+			LoadBalancerName: aws.String(lbName),
+			LoadBalancerPort: aws.Int64(80),
+			PolicyNames:      []*string{},
+		})
+		if err != nil {
+			t.Fatalf("Error detaching LBCookieStickinessPolicy: %s", err)
+		}
+
+		_, err = conn.DeleteLoadBalancerPolicy(&elb.DeleteLoadBalancerPolicyInput{
+			LoadBalancerName: aws.String(lbName),
+			PolicyName:       aws.String("foo-policy"),
+		})
+		if err != nil {
+			t.Fatalf("Error deleting LBCookieStickinessPolicy: %s", err)
+		}
+
+		_, err = conn.CreateLBCookieStickinessPolicy(&elb.CreateLBCookieStickinessPolicyInput{
+			LoadBalancerName:       aws.String(lbName),
+			PolicyName:             aws.String("foo-policy"),
+			CookieExpirationPeriod: aws.Int64(600),
+		})
+		if err != nil {
+			t.Fatalf("Error recreating LBCookieStickinessPolicy: %s", err)
+		}
+
+		_, err = conn.SetLoadBalancerPoliciesOfListener(&elb.SetLoadBalancerPoliciesOfListenerInput{
+			LoadBalancerName: aws.String(lbName),
+			LoadBalancerPort: aws.Int64(80),
+			PolicyNames:      []*string{aws.String("foo-policy")},
+		})
+		if err != nil {
+			t.Fatalf("Error reattaching LBCookieStickinessPolicy: %s", err)
+		}
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLBCookieStickinessPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLBCookieStickinessPolicyConfigUpdate(lbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("aws_lb_cookie_stickiness_policy.foo", "cookie_expiration_period", "300"),
+				),
+			},
+			{
+				PreConfig:          changeExpiration,
+				Config:             testAccLBCookieStickinessPolicyConfigUpdate(lbName),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestFlattenLBCookieStickinessPolicyExpiration(t *testing.T) {
+	cases := []struct {
+		Attributes []*elb.PolicyAttributeDescription
+		Expected   int
+		ErrCount   int
+	}{
+		{
+			Attributes: nil,
+			Expected:   0,
+		},
+		{
+			Attributes: []*elb.PolicyAttributeDescription{
+				{AttributeName: aws.String("CookieExpirationPeriod"), AttributeValue: aws.String("")},
+			},
+			Expected: 0,
+		},
+		{
+			Attributes: []*elb.PolicyAttributeDescription{
+				{AttributeName: aws.String("Other"), AttributeValue: aws.String("true")},
+				{AttributeName: aws.String("CookieExpirationPeriod"), AttributeValue: aws.String("300")},
+			},
+			Expected: 300,
+		},
+		{
+			Attributes: []*elb.PolicyAttributeDescription{
+				{AttributeName: aws.String("CookieExpirationPeriod"), AttributeValue: aws.String("soon")},
+			},
+			ErrCount: 1,
+		},
+	}
+
+	for i, tc := range cases {
+		period, err := flattenLBCookieStickinessPolicyExpiration(tc.Attributes)
+		if tc.ErrCount == 0 && err != nil {
+			t.Fatalf("case %d: unexpected error: %s", i, err)
+		}
+		if tc.ErrCount > 0 && err == nil {
+			t.Fatalf("case %d: expected an error", i)
+		}
+		if period != tc.Expected {
+			t.Fatalf("case %d: expected %d, got %d", i, tc.Expected, period)
+		}
+	}
+}
+
 func TestAccAWSLBCookieStickinessPolicy_missingLB(t *testing.T) {
 	lbName := fmt.Sprintf("tf-test-lb-%s", acctest.RandString(5))
 
@@ -0,0 +1,442 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsLbFailoverPair() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsLbFailoverPairCreate,
+		Read:   resourceAwsLbFailoverPairRead,
+		Update: resourceAwsLbFailoverPairUpdate,
+		Delete: resourceAwsLbFailoverPairDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAwsLbFailoverPairImport,
+		},
+
+		CustomizeDiff: resourceAwsLbFailoverPairCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"primary_listener_arn": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"primary_region": regionSchema(),
+
+			"standby_listener_arn": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"standby_region": regionSchema(),
+
+			"target_group_mapping": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"standby_rule_arns": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"created_rule_arns": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"in_sync": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// lbFailoverPairClients returns the primary and standby clients, honouring
+// each side's region argument.
+func lbFailoverPairClients(d *schema.ResourceData, meta interface{}) (*AWSClient, *AWSClient) {
+	client := meta.(*AWSClient)
+	primary := client.regionalClient(d.Get("primary_region").(string))
+	standby := client.regionalClient(d.Get("standby_region").(string))
+	return primary, standby
+}
+
+func resourceAwsLbFailoverPairCreate(d *schema.ResourceData, meta interface{}) error {
+	// The ID is set first so that the rules created before a failed
+	// replication are still tracked, and deleted with the tainted pair.
+	d.SetId(d.Get("primary_listener_arn").(string) + "_" + d.Get("standby_listener_arn").(string))
+
+	if err := syncLbFailoverPair(d, meta); err != nil {
+		return err
+	}
+
+	return resourceAwsLbFailoverPairRead(d, meta)
+}
+
+func resourceAwsLbFailoverPairRead(d *schema.ResourceData, meta interface{}) error {
+	primary, standby := lbFailoverPairClients(d, meta)
+
+	primaryRules, err := describeLbListenerRules(primary.elbv2conn, d.Get("primary_listener_arn").(string))
+	if err != nil {
+		if isAWSErrCode(err, elbv2.ErrCodeListenerNotFoundException) {
+			log.Printf("[WARN] Primary listener of LB failover pair %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error retrieving primary listener rules: %s", err)
+	}
+
+	standbyRules, err := describeLbListenerRules(standby.elbv2conn, d.Get("standby_listener_arn").(string))
+	if err != nil {
+		if isAWSErrCode(err, elbv2.ErrCodeListenerNotFoundException) {
+			log.Printf("[WARN] Standby listener of LB failover pair %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error retrieving standby listener rules: %s", err)
+	}
+
+	standbyRuleArns := make(map[string]interface{}, len(standbyRules))
+	exists := make(map[string]bool, len(standbyRules))
+	for _, rule := range standbyRules {
+		standbyRuleArns[aws.StringValue(rule.Priority)] = aws.StringValue(rule.RuleArn)
+		exists[aws.StringValue(rule.RuleArn)] = true
+	}
+
+	// Rules the pair created may since have been deleted outside of Terraform.
+	var createdRuleArns []interface{}
+	created := make(map[string]bool)
+	for _, arn := range d.Get("created_rule_arns").(*schema.Set).List() {
+		if exists[arn.(string)] {
+			createdRuleArns = append(createdRuleArns, arn)
+			created[arn.(string)] = true
+		}
+	}
+
+	targetGroups := expandLbFailoverPairTargetGroupMapping(d.Get("target_group_mapping").(map[string]interface{}))
+	changes, err := planLbFailoverPairChanges(primaryRules, standbyRules, created, targetGroups)
+	if err != nil {
+		log.Printf("[WARN] Unable to replicate the rules of LB failover pair %s: %s", d.Id(), err)
+	}
+	inSync := err == nil && changes.Empty()
+
+	d.Set("primary_region", primary.region)
+	d.Set("standby_region", standby.region)
+	d.Set("in_sync", inSync)
+	if err := d.Set("standby_rule_arns", standbyRuleArns); err != nil {
+		return fmt.Errorf("error setting standby_rule_arns: %s", err)
+	}
+	if err := d.Set("created_rule_arns", schema.NewSet(schema.HashString, createdRuleArns)); err != nil {
+		return fmt.Errorf("error setting created_rule_arns: %s", err)
+	}
+
+	return nil
+}
+
+func resourceAwsLbFailoverPairUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := syncLbFailoverPair(d, meta); err != nil {
+		return err
+	}
+
+	return resourceAwsLbFailoverPairRead(d, meta)
+}
+
+// resourceAwsLbFailoverPairDelete only deletes the standby rules the pair
+// created, leaving the rules that were already on the standby listener.
+func resourceAwsLbFailoverPairDelete(d *schema.ResourceData, meta interface{}) error {
+	_, standby := lbFailoverPairClients(d, meta)
+
+	for _, arn := range d.Get("created_rule_arns").(*schema.Set).List() {
+		log.Printf("[INFO] Deleting replicated rule %s", arn)
+		_, err := standby.elbv2conn.DeleteRule(&elbv2.DeleteRuleInput{
+			RuleArn: aws.String(arn.(string)),
+		})
+		if err != nil && !isLbNotFoundErr(err) {
+			return fmt.Errorf("Error deleting replicated LB Listener Rule %s: %s", arn, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceAwsLbFailoverPairImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	primaryListenerArn, standbyListenerArn, err := lbFailoverPairParseId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	// The region of each listener is part of its ARN.
+	primaryArn, err := arn.Parse(primaryListenerArn)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing primary listener ARN %q: %s", primaryListenerArn, err)
+	}
+	standbyArn, err := arn.Parse(standbyListenerArn)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing standby listener ARN %q: %s", standbyListenerArn, err)
+	}
+
+	d.Set("primary_listener_arn", primaryListenerArn)
+	d.Set("primary_region", primaryArn.Region)
+	d.Set("standby_listener_arn", standbyListenerArn)
+	d.Set("standby_region", standbyArn.Region)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceAwsLbFailoverPairCustomizeDiff plans a replication whenever the last
+// read found the standby listener out of step with the primary.
+func resourceAwsLbFailoverPairCustomizeDiff(diff *schema.ResourceDiff, v interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+
+	if inSync, ok := diff.GetOk("in_sync"); !ok || !inSync.(bool) {
+		if err := diff.SetNew("in_sync", true); err != nil {
+			return err
+		}
+		if err := diff.SetNewComputed("created_rule_arns"); err != nil {
+			return err
+		}
+		return diff.SetNewComputed("standby_rule_arns")
+	}
+
+	return nil
+}
+
+// syncLbFailoverPair replicates every non-default rule on the primary listener
+// to the standby listener at the same priority, and removes the standby rules
+// it created that no longer exist on the primary. The rules it creates are
+// recorded in created_rule_arns.
+func syncLbFailoverPair(d *schema.ResourceData, meta interface{}) (err error) {
+	primary, standby := lbFailoverPairClients(d, meta)
+	primaryListenerArn := d.Get("primary_listener_arn").(string)
+	standbyListenerArn := d.Get("standby_listener_arn").(string)
+
+	primaryRules, err := describeLbListenerRules(primary.elbv2conn, primaryListenerArn)
+	if err != nil {
+		return fmt.Errorf("Error retrieving primary listener rules: %s", err)
+	}
+
+	standbyRules, err := describeLbListenerRules(standby.elbv2conn, standbyListenerArn)
+	if err != nil {
+		return fmt.Errorf("Error retrieving standby listener rules: %s", err)
+	}
+
+	// created_rule_arns is unknown in the plan of a replication.
+	o, _ := d.GetChange("created_rule_arns")
+	created := make(map[string]bool)
+	for _, arn := range o.(*schema.Set).List() {
+		created[arn.(string)] = true
+	}
+	defer func() {
+		createdRuleArns := make([]interface{}, 0, len(created))
+		for arn := range created {
+			createdRuleArns = append(createdRuleArns, arn)
+		}
+		if setErr := d.Set("created_rule_arns", schema.NewSet(schema.HashString, createdRuleArns)); err == nil {
+			err = setErr
+		}
+	}()
+
+	targetGroups := expandLbFailoverPairTargetGroupMapping(d.Get("target_group_mapping").(map[string]interface{}))
+	changes, err := planLbFailoverPairChanges(primaryRules, standbyRules, created, targetGroups)
+	if err != nil {
+		return err
+	}
+
+	arns, err := applyLbListenerRuleChanges(standby.elbv2conn, standbyListenerArn, changes)
+	for _, arn := range arns {
+		created[arn] = true
+	}
+	if err != nil {
+		return fmt.Errorf("Error replicating rules to standby listener %s: %s", standbyListenerArn, err)
+	}
+	for _, rule := range changes.Delete {
+		delete(created, aws.StringValue(rule.RuleArn))
+	}
+
+	return nil
+}
+
+// planLbFailoverPairChanges returns the changes that bring the standby rules
+// the pair created, created, in line with the rules of the primary listener.
+// Rules with authenticate-oidc actions are skipped, as the API never returns
+// their client secret. A primary rule whose priority is taken on the standby
+// listener by a rule the pair did not create is an error.
+func planLbFailoverPairChanges(primaryRules, standbyRules []*elbv2.Rule, created map[string]bool, targetGroups map[string]string) (*lbListenerRuleChanges, error) {
+	changes := &lbListenerRuleChanges{}
+	standbyByPriority := lbListenerRulesByPriority(standbyRules)
+	replicated := make(map[string]bool, len(primaryRules))
+
+	for _, rule := range primaryRules {
+		priority := aws.StringValue(rule.Priority)
+		if lbActionsHaveType(rule.Actions, elbv2.ActionTypeEnumAuthenticateOidc) {
+			log.Printf("[WARN] Not replicating rule %s, as authenticate-oidc actions cannot be replicated", aws.StringValue(rule.RuleArn))
+			continue
+		}
+
+		desired, err := lbFailoverPairStandbyRule(rule, targetGroups)
+		if err != nil {
+			return nil, fmt.Errorf("Error replicating rule %s: %s", aws.StringValue(rule.RuleArn), err)
+		}
+		replicated[priority] = true
+
+		existing, ok := standbyByPriority[priority]
+		switch {
+		case !ok:
+			changes.Create = append(changes.Create, desired)
+		case !created[aws.StringValue(existing.RuleArn)]:
+			return nil, fmt.Errorf("priority %s of the standby listener is taken by rule %s, which was not created by the failover pair", priority, aws.StringValue(existing.RuleArn))
+		case !lbFailoverPairRulesEqual(desired, existing):
+			desired.RuleArn = existing.RuleArn
+			changes.Modify = append(changes.Modify, desired)
+		}
+	}
+
+	for _, rule := range standbyRules {
+		if created[aws.StringValue(rule.RuleArn)] && !replicated[aws.StringValue(rule.Priority)] {
+			changes.Delete = append(changes.Delete, rule)
+		}
+	}
+
+	return changes, nil
+}
+
+// describeLbListenerRules returns the non-default rules of a listener.
+func describeLbListenerRules(conn *elbv2.ELBV2, listenerArn string) ([]*elbv2.Rule, error) {
+	var rules []*elbv2.Rule
+
+	err := paginate("DescribeRules", func(marker *string) (*string, error) {
+		out, err := conn.DescribeRules(&elbv2.DescribeRulesInput{
+			ListenerArn: aws.String(listenerArn),
+			Marker:      marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range out.Rules {
+			if !aws.BoolValue(rule.IsDefault) {
+				rules = append(rules, rule)
+			}
+		}
+		return out.NextMarker, nil
+	})
+
+	return rules, err
+}
+
+func lbListenerRulesByPriority(rules []*elbv2.Rule) map[string]*elbv2.Rule {
+	byPriority := make(map[string]*elbv2.Rule, len(rules))
+	for _, rule := range rules {
+		byPriority[aws.StringValue(rule.Priority)] = rule
+	}
+	return byPriority
+}
+
+func expandLbFailoverPairTargetGroupMapping(m map[string]interface{}) map[string]string {
+	targetGroups := make(map[string]string, len(m))
+	for k, v := range m {
+		targetGroups[k] = v.(string)
+	}
+	return targetGroups
+}
+
+// lbFailoverPairStandbyRule builds the standby copy of a primary rule. Target
+// groups are regional, so every forward action must have an entry in
+// targetGroups; authenticate-oidc actions cannot be copied because the API
+// never returns the client secret.
+func lbFailoverPairStandbyRule(rule *elbv2.Rule, targetGroups map[string]string) (*elbv2.Rule, error) {
+	standby := &elbv2.Rule{
+		Priority: rule.Priority,
+	}
+
+	for _, condition := range rule.Conditions {
+		standby.Conditions = append(standby.Conditions, normalizeLbFailoverPairCondition(condition))
+	}
+
+	for _, action := range rule.Actions {
+		a := *action
+		switch aws.StringValue(a.Type) {
+		case elbv2.ActionTypeEnumForward:
+			arn, ok := targetGroups[aws.StringValue(a.TargetGroupArn)]
+			if !ok {
+				return nil, fmt.Errorf("no target_group_mapping entry for %s", aws.StringValue(a.TargetGroupArn))
+			}
+			a.TargetGroupArn = aws.String(arn)
+		case elbv2.ActionTypeEnumAuthenticateOidc:
+			return nil, fmt.Errorf("authenticate-oidc actions cannot be replicated")
+		}
+		standby.Actions = append(standby.Actions, &a)
+	}
+
+	sort.Slice(standby.Actions, func(i, j int) bool {
+		return aws.Int64Value(standby.Actions[i].Order) < aws.Int64Value(standby.Actions[j].Order)
+	})
+
+	return standby, nil
+}
+
+// normalizeLbFailoverPairCondition drops the legacy Values list when the
+// equivalent *Config block is present, as the API rejects rules with both.
+func normalizeLbFailoverPairCondition(condition *elbv2.RuleCondition) *elbv2.RuleCondition {
+	c := *condition
+	if c.HostHeaderConfig != nil || c.PathPatternConfig != nil {
+		c.Values = nil
+	}
+	return &c
+}
+
+func lbFailoverPairRulesEqual(desired, existing *elbv2.Rule) bool {
+	actual, err := lbFailoverPairStandbyRule(existing, lbFailoverPairIdentityMapping(existing))
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(desired.Conditions, actual.Conditions) &&
+		reflect.DeepEqual(desired.Actions, actual.Actions)
+}
+
+// lbFailoverPairIdentityMapping maps each target group of rule to itself so
+// that a standby rule can be normalized the same way as its primary.
+func lbFailoverPairIdentityMapping(rule *elbv2.Rule) map[string]string {
+	targetGroups := make(map[string]string)
+	for _, action := range rule.Actions {
+		if arn := aws.StringValue(action.TargetGroupArn); arn != "" {
+			targetGroups[arn] = arn
+		}
+	}
+	return targetGroups
+}
+
+// lbFailoverPairParseId splits the ID into the primary and standby listener
+// ARNs.
+func lbFailoverPairParseId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%q), expected PRIMARY-LISTENER-ARN_STANDBY-LISTENER-ARN", id)
+	}
+	return parts[0], parts[1], nil
+}
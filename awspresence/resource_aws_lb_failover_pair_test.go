@@ -0,0 +1,403 @@
+package awspresence
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestLbFailoverPairStandbyRule(t *testing.T) {
+	rule := &elbv2.Rule{
+		Priority: aws.String("10"),
+		Conditions: []*elbv2.RuleCondition{
+			{
+				Field:  aws.String("path-pattern"),
+				Values: []*string{aws.String("/api/*")},
+				PathPatternConfig: &elbv2.PathPatternConditionConfig{
+					Values: []*string{aws.String("/api/*")},
+				},
+			},
+		},
+		Actions: []*elbv2.Action{
+			{
+				Type:           aws.String(elbv2.ActionTypeEnumForward),
+				Order:          aws.Int64(2),
+				TargetGroupArn: aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/primary/1"),
+			},
+			{
+				Type:  aws.String(elbv2.ActionTypeEnumAuthenticateCognito),
+				Order: aws.Int64(1),
+				AuthenticateCognitoConfig: &elbv2.AuthenticateCognitoActionConfig{
+					UserPoolArn: aws.String("arn:aws:cognito-idp:us-west-2:123456789012:userpool/pool"),
+				},
+			},
+		},
+	}
+
+	standby, err := lbFailoverPairStandbyRule(rule, map[string]string{
+		"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/primary/1": "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/standby/2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if standby.Conditions[0].Values != nil {
+		t.Fatalf("expected legacy values to be dropped, got %v", standby.Conditions[0].Values)
+	}
+	if rule.Conditions[0].Values == nil {
+		t.Fatalf("expected primary rule to be left untouched")
+	}
+
+	if got := aws.StringValue(standby.Actions[0].Type); got != elbv2.ActionTypeEnumAuthenticateCognito {
+		t.Fatalf("expected actions ordered by Order, first action was %s", got)
+	}
+	if got := aws.StringValue(standby.Actions[1].TargetGroupArn); got != "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/standby/2" {
+		t.Fatalf("expected target group to be mapped, got %s", got)
+	}
+	if got := aws.StringValue(rule.Actions[0].TargetGroupArn); got != "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/primary/1" {
+		t.Fatalf("expected primary target group to be left untouched, got %s", got)
+	}
+}
+
+func TestLbFailoverPairStandbyRule_errors(t *testing.T) {
+	cases := map[string]*elbv2.Rule{
+		"unmapped target group": {
+			Actions: []*elbv2.Action{
+				{
+					Type:           aws.String(elbv2.ActionTypeEnumForward),
+					TargetGroupArn: aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/primary/1"),
+				},
+			},
+		},
+		"authenticate-oidc": {
+			Actions: []*elbv2.Action{
+				{
+					Type:                   aws.String(elbv2.ActionTypeEnumAuthenticateOidc),
+					AuthenticateOidcConfig: &elbv2.AuthenticateOidcActionConfig{},
+				},
+			},
+		},
+	}
+
+	for name, rule := range cases {
+		if _, err := lbFailoverPairStandbyRule(rule, map[string]string{}); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPlanLbFailoverPairChanges(t *testing.T) {
+	rule := func(arn, priority, path, targetGroup string) *elbv2.Rule {
+		return &elbv2.Rule{
+			RuleArn:  aws.String(arn),
+			Priority: aws.String(priority),
+			Conditions: []*elbv2.RuleCondition{
+				{
+					Field:             aws.String("path-pattern"),
+					PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice([]string{path})},
+				},
+			},
+			Actions: []*elbv2.Action{
+				{
+					Type:           aws.String(elbv2.ActionTypeEnumForward),
+					Order:          aws.Int64(1),
+					TargetGroupArn: aws.String(targetGroup),
+				},
+			},
+		}
+	}
+	oidc := rule("primary-oidc", "40", "/login/*", "primary-tg")
+	oidc.Actions = append(oidc.Actions, &elbv2.Action{
+		Type:                   aws.String(elbv2.ActionTypeEnumAuthenticateOidc),
+		Order:                  aws.Int64(0),
+		AuthenticateOidcConfig: &elbv2.AuthenticateOidcActionConfig{ClientId: aws.String("client")},
+	})
+	targetGroups := map[string]string{"primary-tg": "standby-tg"}
+
+	primaryRules := []*elbv2.Rule{
+		rule("primary-10", "10", "/api/*", "primary-tg"),
+		rule("primary-20", "20", "/v2/*", "primary-tg"),
+		rule("primary-30", "30", "/static/*", "primary-tg"),
+		oidc,
+	}
+	standbyRules := []*elbv2.Rule{
+		rule("standby-20", "20", "/v1/*", "standby-tg"),
+		rule("standby-30", "30", "/static/*", "standby-tg"),
+		rule("standby-40", "40", "/login/*", "standby-tg"),
+		rule("standby-50", "50", "/old/*", "standby-tg"),
+		rule("manual-60", "60", "/manual/*", "standby-tg"),
+	}
+	created := map[string]bool{"standby-20": true, "standby-30": true, "standby-40": true, "standby-50": true}
+
+	changes, err := planLbFailoverPairChanges(primaryRules, standbyRules, created, targetGroups)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	arns := func(rules []*elbv2.Rule) []string {
+		var result []string
+		for _, rule := range rules {
+			result = append(result, aws.StringValue(rule.RuleArn)+"@"+aws.StringValue(rule.Priority))
+		}
+		return result
+	}
+	if actual, expected := arns(changes.Create), []string{"@10"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected created rules %q, got %q", expected, actual)
+	}
+	if actual, expected := arns(changes.Modify), []string{"standby-20@20"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected modified rules %q, got %q", expected, actual)
+	}
+	if actual, expected := arns(changes.Delete), []string{"standby-40@40", "standby-50@50"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected deleted rules %q, got %q", expected, actual)
+	}
+
+	delete(created, "standby-20")
+	if _, err := planLbFailoverPairChanges(primaryRules, standbyRules, created, targetGroups); err == nil {
+		t.Error("expected an error for a priority taken by a rule the pair did not create")
+	}
+}
+
+func TestLbFailoverPairParseId(t *testing.T) {
+	primary, standby, err := lbFailoverPairParseId("arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/a/1/2_arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/b/3/4")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if primary != "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/a/1/2" {
+		t.Fatalf("unexpected primary listener ARN: %s", primary)
+	}
+	if standby != "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/b/3/4" {
+		t.Fatalf("unexpected standby listener ARN: %s", standby)
+	}
+
+	for _, id := range []string{"", "arn", "arn_", "_arn"} {
+		if _, _, err := lbFailoverPairParseId(id); err == nil {
+			t.Fatalf("expected an error for ID %q", id)
+		}
+	}
+}
+
+func TestResourceAwsLbFailoverPairImport(t *testing.T) {
+	d := resourceAwsLbFailoverPair().TestResourceData()
+	d.SetId("arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/a/1/2_arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/b/3/4")
+
+	if _, err := resourceAwsLbFailoverPairImport(d, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if region := d.Get("primary_region").(string); region != "us-west-2" {
+		t.Fatalf("unexpected primary region: %s", region)
+	}
+	if region := d.Get("standby_region").(string); region != "us-east-1" {
+		t.Fatalf("unexpected standby region: %s", region)
+	}
+
+	d.SetId("listener-a_listener-b")
+	if _, err := resourceAwsLbFailoverPairImport(d, nil); err == nil {
+		t.Fatal("expected an error for listener IDs that are not ARNs")
+	}
+}
+
+func TestAccAWSLBFailoverPair_basic(t *testing.T) {
+	var providers []*schema.Provider
+	rName := fmt.Sprintf("tf-failover-%s", acctest.RandStringFromCharSet(8, acctest.CharSetAlphaNum))
+	resourceName := "aws_lb_failover_pair.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories(&providers),
+		CheckDestroy:      testAccCheckAWSLBFailoverPairDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBFailoverPairConfig(rName, "/api/*"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "in_sync", "true"),
+					resource.TestCheckResourceAttr(resourceName, "standby_region", "us-east-1"),
+					resource.TestCheckResourceAttr(resourceName, "standby_rule_arns.%", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "standby_rule_arns.100"),
+					resource.TestCheckResourceAttr(resourceName, "created_rule_arns.#", "1"),
+				),
+			},
+			{
+				Config: testAccAWSLBFailoverPairConfig(rName, "/v2/*"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "in_sync", "true"),
+					resource.TestCheckResourceAttr(resourceName, "standby_rule_arns.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "created_rule_arns.#", "1"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"created_rule_arns", "target_group_mapping", "triggers"},
+			},
+		},
+	})
+}
+
+func testAccCheckAWSLBFailoverPairDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_lb_failover_pair" {
+			continue
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).regionalClient(rs.Primary.Attributes["standby_region"]).elbv2conn
+		for k, arn := range rs.Primary.Attributes {
+			if k == "created_rule_arns.#" || !strings.HasPrefix(k, "created_rule_arns.") {
+				continue
+			}
+
+			resp, err := conn.DescribeRules(&elbv2.DescribeRulesInput{
+				RuleArns: []*string{aws.String(arn)},
+			})
+			if err == nil && len(resp.Rules) != 0 {
+				return fmt.Errorf("Replicated rule %s still exists", arn)
+			}
+			if err != nil && !isAWSErrCode(err, elbv2.ErrCodeRuleNotFoundException, elbv2.ErrCodeListenerNotFoundException) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccAWSLBFailoverPairConfig(rName, pathPattern string) string {
+	return fmt.Sprintf(`
+provider "aws" {
+  alias  = "standby"
+  region = "us-east-1"
+}
+
+data "aws_availability_zones" "primary" {}
+
+data "aws_availability_zones" "standby" {
+  provider = "aws.standby"
+}
+
+resource "aws_vpc" "primary" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-failover-pair-primary"
+  }
+}
+
+resource "aws_subnet" "primary" {
+  count             = 2
+  vpc_id            = "${aws_vpc.primary.id}"
+  cidr_block        = "10.0.${count.index}.0/24"
+  availability_zone = "${data.aws_availability_zones.primary.names[count.index]}"
+
+  tags = {
+    Name = "tf-acc-lb-failover-pair-primary-${count.index}"
+  }
+}
+
+resource "aws_vpc" "standby" {
+  provider   = "aws.standby"
+  cidr_block = "10.1.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-failover-pair-standby"
+  }
+}
+
+resource "aws_subnet" "standby" {
+  provider          = "aws.standby"
+  count             = 2
+  vpc_id            = "${aws_vpc.standby.id}"
+  cidr_block        = "10.1.${count.index}.0/24"
+  availability_zone = "${data.aws_availability_zones.standby.names[count.index]}"
+
+  tags = {
+    Name = "tf-acc-lb-failover-pair-standby-${count.index}"
+  }
+}
+
+resource "aws_lb" "primary" {
+  name     = "%[1]s-p"
+  internal = true
+  subnets  = ["${aws_subnet.primary.*.id}"]
+}
+
+resource "aws_lb" "standby" {
+  name     = "%[1]s-s"
+  region   = "us-east-1"
+  internal = true
+  subnets  = ["${aws_subnet.standby.*.id}"]
+}
+
+resource "aws_lb_target_group" "primary" {
+  name     = "%[1]s-p"
+  port     = 80
+  protocol = "HTTP"
+  vpc_id   = "${aws_vpc.primary.id}"
+}
+
+resource "aws_lb_target_group" "standby" {
+  name     = "%[1]s-s"
+  region   = "us-east-1"
+  port     = 80
+  protocol = "HTTP"
+  vpc_id   = "${aws_vpc.standby.id}"
+}
+
+resource "aws_lb_listener" "primary" {
+  load_balancer_arn = "${aws_lb.primary.arn}"
+  port              = 80
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.primary.arn}"
+  }
+}
+
+resource "aws_lb_listener" "standby" {
+  load_balancer_arn = "${aws_lb.standby.arn}"
+  region            = "us-east-1"
+  port              = 80
+
+  default_action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.standby.arn}"
+  }
+}
+
+resource "aws_lb_listener_rule" "primary" {
+  listener_arn = "${aws_lb_listener.primary.arn}"
+  priority     = 100
+
+  action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.primary.arn}"
+  }
+
+  condition {
+    field  = "path-pattern"
+    values = [%[2]q]
+  }
+}
+
+resource "aws_lb_failover_pair" "test" {
+  primary_listener_arn = "${aws_lb_listener_rule.primary.listener_arn}"
+  standby_listener_arn = "${aws_lb_listener.standby.arn}"
+  standby_region       = "us-east-1"
+
+  target_group_mapping = {
+    "${aws_lb_target_group.primary.arn}" = "${aws_lb_target_group.standby.arn}"
+  }
+
+  triggers = {
+    path_pattern = %[2]q
+  }
+}
+`, rName, pathPattern)
+}
@@ -5,13 +5,13 @@ import (
 	"fmt"
 	"log"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/flatten"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
@@ -27,6 +27,8 @@ func resourceAwsLbListener() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: resourceAwsLbListenerCustomizeDiff,
+
 		Timeouts: &schema.ResourceTimeout{
 			Read: schema.DefaultTimeout(10 * time.Minute),
 		},
@@ -202,8 +204,9 @@ func resourceAwsLbListener() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"authentication_request_extra_params": {
-										Type:     schema.TypeMap,
-										Optional: true,
+										Type:             schema.TypeMap,
+										Optional:         true,
+										DiffSuppressFunc: suppressLbAuthenticateDefaultExtraParams,
 									},
 									"on_unauthenticated_request": {
 										Type:     schema.TypeString,
@@ -254,8 +257,9 @@ func resourceAwsLbListener() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"authentication_request_extra_params": {
-										Type:     schema.TypeMap,
-										Optional: true,
+										Type:             schema.TypeMap,
+										Optional:         true,
+										DiffSuppressFunc: suppressLbAuthenticateDefaultExtraParams,
 									},
 									"authorization_endpoint": {
 										Type:     schema.TypeString,
@@ -313,6 +317,8 @@ func resourceAwsLbListener() *schema.Resource {
 					},
 				},
 			},
+
+			"region": regionSchema(),
 		},
 	}
 }
@@ -333,7 +339,7 @@ func suppressIfDefaultActionTypeNot(t string) schema.SchemaDiffSuppressFunc {
 }
 
 func resourceAwsLbListenerCreate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	lbArn := d.Get("load_balancer_arn").(string)
 
@@ -487,7 +493,7 @@ func resourceAwsLbListenerCreate(d *schema.ResourceData, meta interface{}) error
 		log.Printf("[DEBUG] Creating LB listener for ARN: %s", d.Get("load_balancer_arn").(string))
 		resp, err = elbconn.CreateListener(params)
 		if err != nil {
-			if isAWSErr(err, elbv2.ErrCodeCertificateNotFoundException, "") {
+			if isAWSErrCode(err, elbv2.ErrCodeCertificateNotFoundException) {
 				return resource.RetryableError(err)
 			}
 			return resource.NonRetryableError(err)
@@ -513,7 +519,7 @@ func resourceAwsLbListenerCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceAwsLbListenerRead(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	var resp *elbv2.DescribeListenersOutput
 	var request = &elbv2.DescribeListenersInput{
@@ -523,7 +529,7 @@ func resourceAwsLbListenerRead(d *schema.ResourceData, meta interface{}) error {
 	err := resource.Retry(1*time.Minute, func() *resource.RetryError {
 		var err error
 		resp, err = elbconn.DescribeListeners(request)
-		if d.IsNewResource() && isAWSErr(err, elbv2.ErrCodeListenerNotFoundException, "") {
+		if d.IsNewResource() && isAWSErrCode(err, elbv2.ErrCodeListenerNotFoundException) {
 			return resource.RetryableError(err)
 		}
 		if err != nil {
@@ -536,7 +542,7 @@ func resourceAwsLbListenerRead(d *schema.ResourceData, meta interface{}) error {
 		_, err = elbconn.DescribeListeners(request)
 	}
 
-	if isAWSErr(err, elbv2.ErrCodeListenerNotFoundException, "") {
+	if isAWSErrCode(err, elbv2.ErrCodeListenerNotFoundException) {
 		log.Printf("[WARN] ELBv2 Listener (%s) not found - removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -552,98 +558,24 @@ func resourceAwsLbListenerRead(d *schema.ResourceData, meta interface{}) error {
 
 	listener := resp.Listeners[0]
 
-	d.Set("arn", listener.ListenerArn)
-	d.Set("load_balancer_arn", listener.LoadBalancerArn)
-	d.Set("port", listener.Port)
-	d.Set("protocol", listener.Protocol)
-	d.Set("ssl_policy", listener.SslPolicy)
-
-	if listener.Certificates != nil && len(listener.Certificates) == 1 && listener.Certificates[0] != nil {
-		d.Set("certificate_arn", listener.Certificates[0].CertificateArn)
-	}
-
-	sort.Slice(listener.DefaultActions, func(i, j int) bool {
-		return aws.Int64Value(listener.DefaultActions[i].Order) < aws.Int64Value(listener.DefaultActions[j].Order)
+	m := flatten.Listener(listener, func(i int) string {
+		// The LB API currently provides no way to read the ClientSecret
+		// Instead we passthrough the configuration value into the state
+		return d.Get("default_action." + strconv.Itoa(i) + ".authenticate_oidc.0.client_secret").(string)
 	})
-	defaultActions := make([]interface{}, len(listener.DefaultActions))
-	for i, defaultAction := range listener.DefaultActions {
-		defaultActionMap := make(map[string]interface{})
-		defaultActionMap["type"] = aws.StringValue(defaultAction.Type)
-		defaultActionMap["order"] = aws.Int64Value(defaultAction.Order)
-
-		switch aws.StringValue(defaultAction.Type) {
-		case "forward":
-			defaultActionMap["target_group_arn"] = aws.StringValue(defaultAction.TargetGroupArn)
-
-		case "redirect":
-			defaultActionMap["redirect"] = []map[string]interface{}{
-				{
-					"host":        aws.StringValue(defaultAction.RedirectConfig.Host),
-					"path":        aws.StringValue(defaultAction.RedirectConfig.Path),
-					"port":        aws.StringValue(defaultAction.RedirectConfig.Port),
-					"protocol":    aws.StringValue(defaultAction.RedirectConfig.Protocol),
-					"query":       aws.StringValue(defaultAction.RedirectConfig.Query),
-					"status_code": aws.StringValue(defaultAction.RedirectConfig.StatusCode),
-				},
-			}
-
-		case "fixed-response":
-			defaultActionMap["fixed_response"] = []map[string]interface{}{
-				{
-					"content_type": aws.StringValue(defaultAction.FixedResponseConfig.ContentType),
-					"message_body": aws.StringValue(defaultAction.FixedResponseConfig.MessageBody),
-					"status_code":  aws.StringValue(defaultAction.FixedResponseConfig.StatusCode),
-				},
-			}
-
-		case "authenticate-cognito":
-			authenticationRequestExtraParams := make(map[string]interface{})
-			for key, value := range defaultAction.AuthenticateCognitoConfig.AuthenticationRequestExtraParams {
-				authenticationRequestExtraParams[key] = aws.StringValue(value)
-			}
-			defaultActionMap["authenticate_cognito"] = []map[string]interface{}{
-				{
-					"authentication_request_extra_params": authenticationRequestExtraParams,
-					"on_unauthenticated_request":          aws.StringValue(defaultAction.AuthenticateCognitoConfig.OnUnauthenticatedRequest),
-					"scope":                               aws.StringValue(defaultAction.AuthenticateCognitoConfig.Scope),
-					"session_cookie_name":                 aws.StringValue(defaultAction.AuthenticateCognitoConfig.SessionCookieName),
-					"session_timeout":                     aws.Int64Value(defaultAction.AuthenticateCognitoConfig.SessionTimeout),
-					"user_pool_arn":                       aws.StringValue(defaultAction.AuthenticateCognitoConfig.UserPoolArn),
-					"user_pool_client_id":                 aws.StringValue(defaultAction.AuthenticateCognitoConfig.UserPoolClientId),
-					"user_pool_domain":                    aws.StringValue(defaultAction.AuthenticateCognitoConfig.UserPoolDomain),
-				},
-			}
 
-		case "authenticate-oidc":
-			authenticationRequestExtraParams := make(map[string]interface{})
-			for key, value := range defaultAction.AuthenticateOidcConfig.AuthenticationRequestExtraParams {
-				authenticationRequestExtraParams[key] = aws.StringValue(value)
-			}
+	d.Set("arn", m["arn"])
+	d.Set("region", resourceAWSClient(d, meta).region)
+	d.Set("load_balancer_arn", m["load_balancer_arn"])
+	d.Set("port", m["port"])
+	d.Set("protocol", m["protocol"])
+	d.Set("ssl_policy", m["ssl_policy"])
 
-			// The LB API currently provides no way to read the ClientSecret
-			// Instead we passthrough the configuration value into the state
-			clientSecret := d.Get("default_action." + strconv.Itoa(i) + ".authenticate_oidc.0.client_secret").(string)
-
-			defaultActionMap["authenticate_oidc"] = []map[string]interface{}{
-				{
-					"authentication_request_extra_params": authenticationRequestExtraParams,
-					"authorization_endpoint":              aws.StringValue(defaultAction.AuthenticateOidcConfig.AuthorizationEndpoint),
-					"client_id":                           aws.StringValue(defaultAction.AuthenticateOidcConfig.ClientId),
-					"client_secret":                       clientSecret,
-					"issuer":                              aws.StringValue(defaultAction.AuthenticateOidcConfig.Issuer),
-					"on_unauthenticated_request":          aws.StringValue(defaultAction.AuthenticateOidcConfig.OnUnauthenticatedRequest),
-					"scope":                               aws.StringValue(defaultAction.AuthenticateOidcConfig.Scope),
-					"session_cookie_name":                 aws.StringValue(defaultAction.AuthenticateOidcConfig.SessionCookieName),
-					"session_timeout":                     aws.Int64Value(defaultAction.AuthenticateOidcConfig.SessionTimeout),
-					"token_endpoint":                      aws.StringValue(defaultAction.AuthenticateOidcConfig.TokenEndpoint),
-					"user_info_endpoint":                  aws.StringValue(defaultAction.AuthenticateOidcConfig.UserInfoEndpoint),
-				},
-			}
-		}
-
-		defaultActions[i] = defaultActionMap
+	if certificateArn, ok := m["certificate_arn"]; ok {
+		d.Set("certificate_arn", certificateArn)
 	}
-	if err := d.Set("default_action", defaultActions); err != nil {
+
+	if err := d.Set("default_action", m["default_action"]); err != nil {
 		return fmt.Errorf("error setting default_action: %s", err)
 	}
 
@@ -651,7 +583,7 @@ func resourceAwsLbListenerRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceAwsLbListenerUpdate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	params := &elbv2.ModifyListenerInput{
 		ListenerArn: aws.String(d.Id()),
@@ -802,7 +734,7 @@ func resourceAwsLbListenerUpdate(d *schema.ResourceData, meta interface{}) error
 	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
 		_, err := elbconn.ModifyListener(params)
 		if err != nil {
-			if isAWSErr(err, elbv2.ErrCodeCertificateNotFoundException, "") {
+			if isAWSErrCode(err, elbv2.ErrCodeCertificateNotFoundException) {
 				return resource.RetryableError(err)
 			}
 			return resource.NonRetryableError(err)
@@ -821,13 +753,32 @@ func resourceAwsLbListenerUpdate(d *schema.ResourceData, meta interface{}) error
 	return resourceAwsLbListenerRead(d, meta)
 }
 
+// resourceAwsLbListenerCustomizeDiff applies the rule action limits to the
+// listener's default rule.
+func resourceAwsLbListenerCustomizeDiff(diff *schema.ResourceDiff, v interface{}) error {
+	actions := diff.Get("default_action").([]interface{})
+	if err := validateLbListenerRuleActionLimits(actions); err != nil {
+		return fmt.Errorf("default_action: %s", err)
+	}
+	if err := validateLbListenerRuleActions(actions); err != nil {
+		return fmt.Errorf("default_action: %s", err)
+	}
+	if client, ok := v.(*AWSClient); ok {
+		if err := client.checkLocalstackActions(actions); err != nil {
+			return fmt.Errorf("default_action: %s", err)
+		}
+	}
+
+	return nil
+}
+
 func resourceAwsLbListenerDelete(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	_, err := elbconn.DeleteListener(&elbv2.DeleteListenerInput{
 		ListenerArn: aws.String(d.Id()),
 	})
-	if err != nil {
+	if err != nil && !isLbNotFoundErr(err) {
 		return fmt.Errorf("Error deleting Listener: %s", err)
 	}
 
@@ -29,12 +29,14 @@ func resourceAwsLbListenerCertificate() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+
+			"region": regionSchema(),
 		},
 	}
 }
 
 func resourceAwsLbListenerCertificateCreate(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*AWSClient).elbv2conn
+	conn := resourceAWSClient(d, meta).elbv2conn
 
 	params := &elbv2.AddListenerCertificatesInput{
 		ListenerArn: aws.String(d.Get("listener_arn").(string)),
@@ -61,7 +63,7 @@ func resourceAwsLbListenerCertificateCreate(d *schema.ResourceData, meta interfa
 }
 
 func resourceAwsLbListenerCertificateRead(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*AWSClient).elbv2conn
+	conn := resourceAWSClient(d, meta).elbv2conn
 
 	certificateArn := d.Get("certificate_arn").(string)
 	listenerArn := d.Get("listener_arn").(string)
@@ -71,7 +73,7 @@ func resourceAwsLbListenerCertificateRead(d *schema.ResourceData, meta interface
 	var certificate *elbv2.Certificate
 	err := resource.Retry(1*time.Minute, func() *resource.RetryError {
 		var err error
-		certificate, err = findAwsLbListenerCertificate(certificateArn, listenerArn, true, nil, conn)
+		certificate, err = findAwsLbListenerCertificate(certificateArn, listenerArn, true, conn)
 		if err != nil {
 			return resource.NonRetryableError(err)
 		}
@@ -95,11 +97,13 @@ func resourceAwsLbListenerCertificateRead(d *schema.ResourceData, meta interface
 		return err
 	}
 
+	d.Set("region", resourceAWSClient(d, meta).region)
+
 	return nil
 }
 
 func resourceAwsLbListenerCertificateDelete(d *schema.ResourceData, meta interface{}) error {
-	conn := meta.(*AWSClient).elbv2conn
+	conn := resourceAWSClient(d, meta).elbv2conn
 	log.Printf("[DEBUG] Deleting certificate: %s of listener: %s", d.Get("certificate_arn").(string), d.Get("listener_arn").(string))
 
 	params := &elbv2.RemoveListenerCertificatesInput{
@@ -113,10 +117,7 @@ func resourceAwsLbListenerCertificateDelete(d *schema.ResourceData, meta interfa
 
 	_, err := conn.RemoveListenerCertificates(params)
 	if err != nil {
-		if isAWSErr(err, elbv2.ErrCodeCertificateNotFoundException, "") {
-			return nil
-		}
-		if isAWSErr(err, elbv2.ErrCodeListenerNotFoundException, "") {
+		if isAWSErrCode(err, elbv2.ErrCodeCertificateNotFoundException) || isLbNotFoundErr(err) {
 			return nil
 		}
 		return fmt.Errorf("Error removing LB Listener Certificate: %s", err)
@@ -125,32 +126,32 @@ func resourceAwsLbListenerCertificateDelete(d *schema.ResourceData, meta interfa
 	return nil
 }
 
-func findAwsLbListenerCertificate(certificateArn, listenerArn string, skipDefault bool, nextMarker *string, conn *elbv2.ELBV2) (*elbv2.Certificate, error) {
-	params := &elbv2.DescribeListenerCertificatesInput{
-		ListenerArn: aws.String(listenerArn),
-		PageSize:    aws.Int64(400),
-	}
-	if nextMarker != nil {
-		params.Marker = nextMarker
-	}
-
-	resp, err := conn.DescribeListenerCertificates(params)
-	if err != nil {
-		return nil, err
-	}
+func findAwsLbListenerCertificate(certificateArn, listenerArn string, skipDefault bool, conn *elbv2.ELBV2) (*elbv2.Certificate, error) {
+	var certificate *elbv2.Certificate
 
-	for _, cert := range resp.Certificates {
-		if skipDefault && aws.BoolValue(cert.IsDefault) {
-			continue
+	err := paginate("DescribeListenerCertificates", func(marker *string) (*string, error) {
+		resp, err := conn.DescribeListenerCertificates(&elbv2.DescribeListenerCertificatesInput{
+			ListenerArn: aws.String(listenerArn),
+			Marker:      marker,
+			PageSize:    aws.Int64(400),
+		})
+		if err != nil {
+			return nil, err
 		}
 
-		if aws.StringValue(cert.CertificateArn) == certificateArn {
-			return cert, nil
+		for _, cert := range resp.Certificates {
+			if skipDefault && aws.BoolValue(cert.IsDefault) {
+				continue
+			}
+
+			if aws.StringValue(cert.CertificateArn) == certificateArn {
+				certificate = cert
+				return nil, nil
+			}
 		}
-	}
 
-	if resp.NextMarker != nil {
-		return findAwsLbListenerCertificate(certificateArn, listenerArn, skipDefault, resp.NextMarker, conn)
-	}
-	return nil, nil
+		return resp.NextMarker, nil
+	})
+
+	return certificate, err
 }
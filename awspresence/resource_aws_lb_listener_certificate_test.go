@@ -13,13 +13,15 @@ import (
 )
 
 func TestAccAwsLbListenerCertificate_basic(t *testing.T) {
+	keys, certificates := testAccLbListenerCertificateKeyPairs(4)
+
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProvidersWithTLS,
+		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckAwsLbListenerCertificateDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccLbListenerCertificateConfig(acctest.RandString(5), acctest.RandString(5)),
+				Config: testAccLbListenerCertificateConfig(acctest.RandString(5), acctest.RandString(5), keys, certificates),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAwsLbListenerCertificateExists("aws_lb_listener_certificate.default"),
 					testAccCheckAwsLbListenerCertificateExists("aws_lb_listener_certificate.additional_1"),
@@ -39,14 +41,15 @@ func TestAccAwsLbListenerCertificate_basic(t *testing.T) {
 func TestAccAwsLbListenerCertificate_cycle(t *testing.T) {
 	rName := acctest.RandString(5)
 	suffix := acctest.RandString(5)
+	keys, certificates := testAccLbListenerCertificateKeyPairs(4)
 
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProvidersWithTLS,
+		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckAwsLbListenerCertificateDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccLbListenerCertificateConfig(rName, suffix),
+				Config: testAccLbListenerCertificateConfig(rName, suffix, keys, certificates),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAwsLbListenerCertificateExists("aws_lb_listener_certificate.default"),
 					testAccCheckAwsLbListenerCertificateExists("aws_lb_listener_certificate.additional_1"),
@@ -60,7 +63,7 @@ func TestAccAwsLbListenerCertificate_cycle(t *testing.T) {
 				),
 			},
 			{
-				Config: testAccLbListenerCertificateAddNew(rName, suffix),
+				Config: testAccLbListenerCertificateAddNew(rName, suffix, keys, certificates),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAwsLbListenerCertificateExists("aws_lb_listener_certificate.default"),
 					testAccCheckAwsLbListenerCertificateExists("aws_lb_listener_certificate.additional_1"),
@@ -77,7 +80,7 @@ func TestAccAwsLbListenerCertificate_cycle(t *testing.T) {
 				),
 			},
 			{
-				Config: testAccLbListenerCertificateConfig(rName, suffix),
+				Config: testAccLbListenerCertificateConfig(rName, suffix, keys, certificates),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAwsLbListenerCertificateExists("aws_lb_listener_certificate.default"),
 					testAccCheckAwsLbListenerCertificateExists("aws_lb_listener_certificate.additional_1"),
@@ -110,7 +113,7 @@ func testAccCheckAwsLbListenerCertificateDestroy(s *terraform.State) error {
 
 		resp, err := conn.DescribeListenerCertificates(input)
 		if err != nil {
-			if isAWSErr(err, elbv2.ErrCodeListenerNotFoundException, "") {
+			if isAWSErrCode(err, elbv2.ErrCodeListenerNotFoundException) {
 				return nil
 			}
 			return err
@@ -153,33 +156,22 @@ func testAccCheckAwsLbListenerCertificateNotExists(name string) resource.TestChe
 	}
 }
 
-func testAccLbListenerCertificateConfig(rName, suffix string) string {
-	return fmt.Sprintf(`
-resource "tls_private_key" "test" {
-  count = 4
-
-  algorithm = "RSA"
-}
-
-resource "tls_self_signed_cert" "test" {
-  count = 4
+// testAccLbListenerCertificateKeyPairs generates count distinct private keys
+// and matching self-signed certificates.
+func testAccLbListenerCertificateKeyPairs(count int) ([]string, []string) {
+	keys := make([]string, count)
+	certificates := make([]string, count)
 
-  key_algorithm         = "RSA"
-  private_key_pem       = "${tls_private_key.test.*.private_key_pem[count.index]}"
-  validity_period_hours = 12
-
-  allowed_uses = [
-    "key_encipherment",
-    "digital_signature",
-    "server_auth",
-  ]
+	for i := 0; i < count; i++ {
+		keys[i] = tlsRsaPrivateKeyPem(2048)
+		certificates[i] = tlsRsaX509SelfSignedCertificatePem(keys[i], "example.com")
+	}
 
-  subject {
-    common_name  = "example.com"
-    organization = "ACME Examples, Inc"
-  }
+	return keys, certificates
 }
 
+func testAccLbListenerCertificateConfig(rName, suffix string, keys, certificates []string) string {
+	return fmt.Sprintf(`
 resource "aws_lb_listener_certificate" "default" {
   listener_arn    = "${aws_lb_listener.test.arn}"
   certificate_arn = "${aws_iam_server_certificate.default.arn}"
@@ -222,26 +214,26 @@ resource "aws_lb_listener" "test" {
 
 resource "aws_iam_server_certificate" "default" {
   name             = "terraform-default-cert-%s"
-  certificate_body = "${tls_self_signed_cert.test.*.cert_pem[0]}"
-  private_key      = "${tls_private_key.test.*.private_key_pem[0]}"
+  certificate_body = "%s"
+  private_key      = "%s"
 }
 
 resource "aws_iam_server_certificate" "additional_1" {
   name             = "terraform-additional-cert-1-%s"
-  certificate_body = "${tls_self_signed_cert.test.*.cert_pem[1]}"
-  private_key      = "${tls_private_key.test.*.private_key_pem[1]}"
+  certificate_body = "%s"
+  private_key      = "%s"
 }
 
 resource "aws_iam_server_certificate" "additional_2" {
   name             = "terraform-additional-cert-2-%s"
-  certificate_body = "${tls_self_signed_cert.test.*.cert_pem[2]}"
-  private_key      = "${tls_private_key.test.*.private_key_pem[2]}"
+  certificate_body = "%s"
+  private_key      = "%s"
 }
 
 resource "aws_iam_server_certificate" "additional_3" {
   name             = "terraform-additional-cert-3-%s"
-  certificate_body = "${tls_self_signed_cert.test.*.cert_pem[3]}"
-  private_key      = "${tls_private_key.test.*.private_key_pem[3]}"
+  certificate_body = "%s"
+  private_key      = "%s"
 }
 
 data "aws_availability_zones" "available" {}
@@ -268,11 +260,15 @@ resource "aws_subnet" "test" {
     Name = "tf-acc-lb-listener-certificate-${count.index}"
   }
 }
-`, rName, suffix, suffix, suffix, suffix)
+`, rName,
+		suffix, tlsPemEscapeNewlines(certificates[0]), tlsPemEscapeNewlines(keys[0]),
+		suffix, tlsPemEscapeNewlines(certificates[1]), tlsPemEscapeNewlines(keys[1]),
+		suffix, tlsPemEscapeNewlines(certificates[2]), tlsPemEscapeNewlines(keys[2]),
+		suffix, tlsPemEscapeNewlines(certificates[3]), tlsPemEscapeNewlines(keys[3]))
 }
 
-func testAccLbListenerCertificateAddNew(rName, prefix string) string {
-	return fmt.Sprintf(testAccLbListenerCertificateConfig(rName, prefix) + `
+func testAccLbListenerCertificateAddNew(rName, prefix string, keys, certificates []string) string {
+	return fmt.Sprintf(testAccLbListenerCertificateConfig(rName, prefix, keys, certificates) + `
 resource "aws_lb_listener_certificate" "additional_3" {
   listener_arn    = "${aws_lb_listener.test.arn}"
   certificate_arn = "${aws_iam_server_certificate.additional_3.arn}"
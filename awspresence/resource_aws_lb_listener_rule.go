@@ -1,9 +1,12 @@
 package awspresence
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
@@ -12,6 +15,8 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/flatten"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -25,9 +30,11 @@ func resourceAwsLbbListenerRule() *schema.Resource {
 		Update: resourceAwsLbListenerRuleUpdate,
 		Delete: resourceAwsLbListenerRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceAwsLbListenerRuleImport,
 		},
 
+		CustomizeDiff: resourceAwsLbListenerRuleCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"arn": {
 				Type:     schema.TypeString,
@@ -38,12 +45,98 @@ func resourceAwsLbbListenerRule() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"condition_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"action_order_repair_pending": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"target_group_name_arns": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"action_change_summary": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"diagnostics": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"adopt_matching": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"read_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"wait_for_target_health": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"minimum_healthy_targets": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"timeout": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "10m",
+							ValidateFunc: validate.Duration(0),
+						},
+					},
+				},
+			},
+			"matched_request_sample": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"method": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"query": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"headers": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"source_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"priority": {
 				Type:         schema.TypeInt,
 				Optional:     true,
 				Computed:     true,
 				ForceNew:     true,
-				ValidateFunc: validateAwsLbListenerRulePriority,
+				ValidateFunc: validate.LbListenerRulePriority,
 			},
 			"action": {
 				Type:     schema.TypeList,
@@ -74,6 +167,12 @@ func resourceAwsLbbListenerRule() *schema.Resource {
 							DiffSuppressFunc: suppressIfActionTypeNot("forward"),
 						},
 
+						"target_group_name": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressIfActionTypeNot("forward"),
+						},
+
 						"redirect": {
 							Type:             schema.TypeList,
 							Optional:         true,
@@ -170,8 +269,9 @@ func resourceAwsLbbListenerRule() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"authentication_request_extra_params": {
-										Type:     schema.TypeMap,
-										Optional: true,
+										Type:             schema.TypeMap,
+										Optional:         true,
+										DiffSuppressFunc: suppressLbAuthenticateDefaultExtraParams,
 									},
 									"on_unauthenticated_request": {
 										Type:     schema.TypeString,
@@ -222,8 +322,9 @@ func resourceAwsLbbListenerRule() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"authentication_request_extra_params": {
-										Type:     schema.TypeMap,
-										Optional: true,
+										Type:             schema.TypeMap,
+										Optional:         true,
+										DiffSuppressFunc: suppressLbAuthenticateDefaultExtraParams,
 									},
 									"authorization_endpoint": {
 										Type:     schema.TypeString,
@@ -417,9 +518,17 @@ func resourceAwsLbbListenerRule() *schema.Resource {
 										Type: schema.TypeList,
 										Elem: &schema.Schema{
 											Type:         schema.TypeString,
-											ValidateFunc: validateCIDRNetworkAddress,
+											ValidateFunc: validate.CIDRNetworkAddress,
 										},
-										Required: true,
+										Optional: true,
+									},
+									"prefix_list_ids": {
+										Type: schema.TypeList,
+										Elem: &schema.Schema{
+											Type:         schema.TypeString,
+											ValidateFunc: validate.PrefixListId,
+										},
+										Optional: true,
 									},
 								},
 							},
@@ -439,6 +548,30 @@ func resourceAwsLbbListenerRule() *schema.Resource {
 					},
 				},
 			},
+
+			"source_ip_prefix_lists": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"prefix_list_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"cidrs": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
+			"region": regionSchema(),
 		},
 	}
 }
@@ -530,6 +663,11 @@ func lbListenerRuleConditionSetHash(v interface{}) int {
 			for _, l := range sourceIpValues {
 				fmt.Fprint(&buf, l, "-")
 			}
+			if prefixListIds, ok := sourceIpMap["prefix_list_ids"].([]interface{}); ok {
+				for _, l := range prefixListIds {
+					fmt.Fprint(&buf, l, "-")
+				}
+			}
 		}
 	}
 
@@ -577,9 +715,16 @@ func suppressIfConditionFieldNotIn(fs []string) schema.SchemaDiffSuppressFunc {
 }
 
 func resourceAwsLbListenerRuleCreate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 	listenerArn := d.Get("listener_arn").(string)
 
+	// The listener ARN may be a plain string rather than a reference to a
+	// listener in the configuration, so check the listener exists before
+	// building the rule.
+	if err := checkLbListenerExists(elbconn, listenerArn); err != nil {
+		return err
+	}
+
 	params := &elbv2.CreateRuleInput{
 		ListenerArn: aws.String(listenerArn),
 	}
@@ -600,7 +745,11 @@ func resourceAwsLbListenerRuleCreate(d *schema.ResourceData, meta interface{}) e
 
 		switch actionMap["type"].(string) {
 		case "forward":
-			action.TargetGroupArn = aws.String(actionMap["target_group_arn"].(string))
+			targetGroupArn, err := lbListenerRuleActionTargetGroupArn(elbconn, actionMap)
+			if err != nil {
+				return err
+			}
+			action.TargetGroupArn = aws.String(targetGroupArn)
 
 		case "redirect":
 			redirectList := actionMap["redirect"].([]interface{})
@@ -710,17 +859,60 @@ func resourceAwsLbListenerRuleCreate(d *schema.ResourceData, meta interface{}) e
 		params.Actions[i] = action
 	}
 
+	conditions := d.Get("condition").(*schema.Set).List()
 	var err error
-	params.Conditions, err = lbListenerRuleConditions(d.Get("condition").(*schema.Set).List())
+	params.Conditions, err = lbListenerRuleConditions(conditions)
+	if err != nil {
+		return err
+	}
+
+	prefixLists, err := expandLbListenerRuleSourceIpPrefixLists(resourceAWSClient(d, meta).ec2conn, conditions, params.Conditions)
 	if err != nil {
 		return err
 	}
 
+	if !d.Get("read_only").(bool) {
+		if err := waitForLbListenerRuleTargetHealth(elbconn, d.Get("wait_for_target_health").([]interface{}), lbActionTargetGroupArns(params.Actions)); err != nil {
+			return fmt.Errorf("Error creating LB Listener Rule: %s", err)
+		}
+	}
+
 	var resp *elbv2.CreateRuleOutput
-	if v, ok := d.GetOk("priority"); ok {
+	if d.Get("read_only").(bool) {
+		// A read-only rule is never created: it tracks the rule already at
+		// the configured priority, which must match the configuration.
+		params.Priority = aws.Int64(int64(d.Get("priority").(int)))
+		rule, err := lbListenerRuleMatchingAtPriority(elbconn, params)
+		if err != nil {
+			return fmt.Errorf("Error looking up LB Listener Rule at priority %d: %s", aws.Int64Value(params.Priority), err)
+		}
+		if rule == nil {
+			return fmt.Errorf("Error reading LB Listener Rule: no rule at priority %d of listener %s matches the configuration", aws.Int64Value(params.Priority), listenerArn)
+		}
+		log.Printf("[INFO] Tracking read-only LB Listener Rule %s", aws.StringValue(rule.RuleArn))
+		resp = &elbv2.CreateRuleOutput{Rules: []*elbv2.Rule{rule}}
+	} else if v, ok := d.GetOk("priority"); ok {
 		var err error
 		params.Priority = aws.Int64(int64(v.(int)))
 		resp, err = elbconn.CreateRule(params)
+		if isAWSErrCode(err, elbv2.ErrCodePriorityInUseException) && d.Get("adopt_matching").(bool) {
+			rule, adoptErr := lbListenerRuleMatchingAtPriority(elbconn, params)
+			if adoptErr != nil {
+				return fmt.Errorf("Error looking up LB Listener Rule at priority %d: %s", v.(int), adoptErr)
+			}
+			if rule != nil {
+				log.Printf("[INFO] Adopting LB Listener Rule %s, which matches the configuration", aws.StringValue(rule.RuleArn))
+				resp, err = &elbv2.CreateRuleOutput{Rules: []*elbv2.Rule{rule}}, nil
+				// The client secret of authenticate-oidc actions could not be
+				// compared, so the configured actions are written to the rule.
+				if lbActionsHaveType(params.Actions, elbv2.ActionTypeEnumAuthenticateOidc) {
+					_, err = elbconn.ModifyRule(&elbv2.ModifyRuleInput{
+						RuleArn: rule.RuleArn,
+						Actions: params.Actions,
+					})
+				}
+			}
+		}
 		if err != nil {
 			return fmt.Errorf("Error creating LB Listener Rule: %v", err)
 		}
@@ -734,7 +926,7 @@ func resourceAwsLbListenerRuleCreate(d *schema.ResourceData, meta interface{}) e
 			params.Priority = aws.Int64(priority + 1)
 			resp, err = elbconn.CreateRule(params)
 			if err != nil {
-				if isAWSErr(err, elbv2.ErrCodePriorityInUseException, "") {
+				if isAWSErrCode(err, elbv2.ErrCodePriorityInUseException) {
 					return resource.RetryableError(err)
 				}
 				return resource.NonRetryableError(err)
@@ -751,12 +943,21 @@ func resourceAwsLbListenerRuleCreate(d *schema.ResourceData, meta interface{}) e
 	}
 
 	d.SetId(aws.StringValue(resp.Rules[0].RuleArn))
+	if err := d.Set("source_ip_prefix_lists", prefixLists); err != nil {
+		return fmt.Errorf("error setting source_ip_prefix_lists: %s", err)
+	}
+
+	if !d.Get("read_only").(bool) {
+		if err := tagLbListenerRuleFingerprint(elbconn, d.Id(), params.Conditions); err != nil {
+			return fmt.Errorf("Error tagging LB Listener Rule %s: %s", d.Id(), err)
+		}
+	}
 
 	return resourceAwsLbListenerRuleRead(d, meta)
 }
 
 func resourceAwsLbListenerRuleRead(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	var resp *elbv2.DescribeRulesOutput
 	var req = &elbv2.DescribeRulesInput{
@@ -767,7 +968,7 @@ func resourceAwsLbListenerRuleRead(d *schema.ResourceData, meta interface{}) err
 		var err error
 		resp, err = elbconn.DescribeRules(req)
 		if err != nil {
-			if d.IsNewResource() && isAWSErr(err, elbv2.ErrCodeRuleNotFoundException, "") {
+			if d.IsNewResource() && isAWSErrCode(err, elbv2.ErrCodeRuleNotFoundException) {
 				return resource.RetryableError(err)
 			} else {
 				return resource.NonRetryableError(err)
@@ -777,7 +978,7 @@ func resourceAwsLbListenerRuleRead(d *schema.ResourceData, meta interface{}) err
 	})
 
 	if err != nil {
-		if isAWSErr(err, elbv2.ErrCodeRuleNotFoundException, "") {
+		if isAWSErrCode(err, elbv2.ErrCodeRuleNotFoundException) {
 			log.Printf("[WARN] DescribeRules - removing %s from state", d.Id())
 			d.SetId("")
 			return nil
@@ -791,179 +992,91 @@ func resourceAwsLbListenerRuleRead(d *schema.ResourceData, meta interface{}) err
 
 	rule := resp.Rules[0]
 
-	d.Set("arn", rule.RuleArn)
-
-	// The listener arn isn't in the response but can be derived from the rule arn
-	d.Set("listener_arn", lbListenerARNFromRuleARN(aws.StringValue(rule.RuleArn)))
-
-	// Rules are evaluated in priority order, from the lowest value to the highest value. The default rule has the lowest priority.
-	if aws.StringValue(rule.Priority) == "default" {
-		d.Set("priority", 99999)
+	// Console edits can leave duplicate or missing orders in the action sequence.
+	// Store the renumbered sequence and flag the rule so the next plan repairs it.
+	if normalizeLbListenerRuleActionOrder(rule.Actions) {
+		log.Printf("[WARN] LB Listener Rule %s has a broken action order sequence, planning a repair", d.Id())
+		d.Set("action_order_repair_pending", true)
 	} else {
-		if priority, err := strconv.Atoi(aws.StringValue(rule.Priority)); err != nil {
-			return fmt.Errorf("Cannot convert rule priority %q to int: %s", aws.StringValue(rule.Priority), err)
-		} else {
-			d.Set("priority", priority)
-		}
+		d.Set("action_order_repair_pending", false)
 	}
 
-	sort.Slice(rule.Actions, func(i, j int) bool {
-		return aws.Int64Value(rule.Actions[i].Order) < aws.Int64Value(rule.Actions[j].Order)
+	m, err := flatten.Rule(rule, func(i int) string {
+		// The LB API currently provides no way to read the ClientSecret
+		// Instead we passthrough the configuration value into the state
+		return d.Get("action." + strconv.Itoa(i) + ".authenticate_oidc.0.client_secret").(string)
 	})
-	actions := make([]interface{}, len(rule.Actions))
-	for i, action := range rule.Actions {
-		actionMap := make(map[string]interface{})
-		actionMap["type"] = aws.StringValue(action.Type)
-		actionMap["order"] = aws.Int64Value(action.Order)
-
-		switch actionMap["type"] {
-		case "forward":
-			actionMap["target_group_arn"] = aws.StringValue(action.TargetGroupArn)
-
-		case "redirect":
-			actionMap["redirect"] = []map[string]interface{}{
-				{
-					"host":        aws.StringValue(action.RedirectConfig.Host),
-					"path":        aws.StringValue(action.RedirectConfig.Path),
-					"port":        aws.StringValue(action.RedirectConfig.Port),
-					"protocol":    aws.StringValue(action.RedirectConfig.Protocol),
-					"query":       aws.StringValue(action.RedirectConfig.Query),
-					"status_code": aws.StringValue(action.RedirectConfig.StatusCode),
-				},
-			}
-
-		case "fixed-response":
-			actionMap["fixed_response"] = []map[string]interface{}{
-				{
-					"content_type": aws.StringValue(action.FixedResponseConfig.ContentType),
-					"message_body": aws.StringValue(action.FixedResponseConfig.MessageBody),
-					"status_code":  aws.StringValue(action.FixedResponseConfig.StatusCode),
-				},
-			}
+	if err != nil {
+		return err
+	}
 
-		case "authenticate-cognito":
-			authenticationRequestExtraParams := make(map[string]interface{})
-			for key, value := range action.AuthenticateCognitoConfig.AuthenticationRequestExtraParams {
-				authenticationRequestExtraParams[key] = aws.StringValue(value)
-			}
+	d.Set("arn", m["arn"])
 
-			actionMap["authenticate_cognito"] = []map[string]interface{}{
-				{
-					"authentication_request_extra_params": authenticationRequestExtraParams,
-					"on_unauthenticated_request":          aws.StringValue(action.AuthenticateCognitoConfig.OnUnauthenticatedRequest),
-					"scope":                               aws.StringValue(action.AuthenticateCognitoConfig.Scope),
-					"session_cookie_name":                 aws.StringValue(action.AuthenticateCognitoConfig.SessionCookieName),
-					"session_timeout":                     aws.Int64Value(action.AuthenticateCognitoConfig.SessionTimeout),
-					"user_pool_arn":                       aws.StringValue(action.AuthenticateCognitoConfig.UserPoolArn),
-					"user_pool_client_id":                 aws.StringValue(action.AuthenticateCognitoConfig.UserPoolClientId),
-					"user_pool_domain":                    aws.StringValue(action.AuthenticateCognitoConfig.UserPoolDomain),
-				},
-			}
+	// The listener arn isn't in the response but can be derived from the rule arn
+	d.Set("listener_arn", lbListenerARNFromRuleARN(aws.StringValue(rule.RuleArn)))
+	d.Set("region", resourceAWSClient(d, meta).region)
 
-		case "authenticate-oidc":
-			authenticationRequestExtraParams := make(map[string]interface{})
-			for key, value := range action.AuthenticateOidcConfig.AuthenticationRequestExtraParams {
-				authenticationRequestExtraParams[key] = aws.StringValue(value)
-			}
+	// Rules are evaluated in priority order, from the lowest value to the highest value. The default rule has the lowest priority.
+	d.Set("priority", m["priority"])
 
-			// The LB API currently provides no way to read the ClientSecret
-			// Instead we passthrough the configuration value into the state
-			clientSecret := d.Get("action." + strconv.Itoa(i) + ".authenticate_oidc.0.client_secret").(string)
+	// Forward actions configured with a target group name keep referring to
+	// it by name, so that forwarding to another target group shows as a diff.
+	// Their ARN is only kept in target_group_name_arns, so that
+	// target_group_arn is only ever set by the configuration.
+	stateActions := d.Get("action").([]interface{})
+	targetGroupNameArns := make(map[string]interface{})
 
-			actionMap["authenticate_oidc"] = []map[string]interface{}{
-				{
-					"authentication_request_extra_params": authenticationRequestExtraParams,
-					"authorization_endpoint":              aws.StringValue(action.AuthenticateOidcConfig.AuthorizationEndpoint),
-					"client_id":                           aws.StringValue(action.AuthenticateOidcConfig.ClientId),
-					"client_secret":                       clientSecret,
-					"issuer":                              aws.StringValue(action.AuthenticateOidcConfig.Issuer),
-					"on_unauthenticated_request":          aws.StringValue(action.AuthenticateOidcConfig.OnUnauthenticatedRequest),
-					"scope":                               aws.StringValue(action.AuthenticateOidcConfig.Scope),
-					"session_cookie_name":                 aws.StringValue(action.AuthenticateOidcConfig.SessionCookieName),
-					"session_timeout":                     aws.Int64Value(action.AuthenticateOidcConfig.SessionTimeout),
-					"token_endpoint":                      aws.StringValue(action.AuthenticateOidcConfig.TokenEndpoint),
-					"user_info_endpoint":                  aws.StringValue(action.AuthenticateOidcConfig.UserInfoEndpoint),
-				},
-			}
+	actions := m["action"].([]interface{})
+	for i, action := range rule.Actions {
+		if aws.StringValue(action.Type) != elbv2.ActionTypeEnumForward || i >= len(stateActions) {
+			continue
+		}
+		stateAction, _ := stateActions[i].(map[string]interface{})
+		if name, _ := stateAction["target_group_name"].(string); name != "" {
+			name = lbTargetGroupNameFromARN(aws.StringValue(action.TargetGroupArn))
+			actions[i].(map[string]interface{})["target_group_name"] = name
+			actions[i].(map[string]interface{})["target_group_arn"] = ""
+			targetGroupNameArns[name] = aws.StringValue(action.TargetGroupArn)
 		}
-
-		actions[i] = actionMap
 	}
 	d.Set("action", actions)
+	d.Set("target_group_name_arns", targetGroupNameArns)
 
-	conditions := make([]interface{}, len(rule.Conditions))
+	// Source IP conditions resolved from prefix lists are stored as the
+	// prefix lists they were configured with.
+	stateConditions := d.Get("condition").(*schema.Set).List()
+	prefixLists := d.Get("source_ip_prefix_lists").([]interface{})
+	conditions := m["condition"].([]interface{})
 	for i, condition := range rule.Conditions {
-		conditionMap := make(map[string]interface{})
-		conditionMap["field"] = aws.StringValue(condition.Field)
-
-		// Deprecated: remove in next major version of provider
-		conditionMap["values"] = aws.StringValueSlice(condition.Values)
-
-		if condition.HostHeaderConfig != nil {
-			conditionMap["host_header"] = []interface{}{
-				map[string]interface{}{
-					"values": aws.StringValueSlice(condition.HostHeaderConfig.Values),
-				},
-			}
-		}
-
-		if condition.HttpHeaderConfig != nil {
-			conditionMap["http_header"] = []interface{}{
-				map[string]interface{}{
-					"http_header_name": aws.StringValue(condition.HttpHeaderConfig.HttpHeaderName),
-					"values":           aws.StringValueSlice(condition.HttpHeaderConfig.Values),
-				},
-			}
-		}
-
-		if condition.HttpRequestMethodConfig != nil {
-			conditionMap["http_request_method"] = []interface{}{
-				map[string]interface{}{
-					"values": aws.StringValueSlice(condition.HttpRequestMethodConfig.Values),
-				},
-			}
-		}
-
-		if condition.PathPatternConfig != nil {
-			conditionMap["path_pattern"] = []interface{}{
-				map[string]interface{}{
-					"values": aws.StringValueSlice(condition.PathPatternConfig.Values),
-				},
-			}
-		}
-
-		if condition.QueryStringConfig != nil {
-			values := make([]interface{}, len(condition.QueryStringConfig.Values))
-			for k, value := range condition.QueryStringConfig.Values {
-				values[k] = map[string]interface{}{
-					"key":   aws.StringValue(value.Key),
-					"value": aws.StringValue(value.Value),
-				}
-			}
-			conditionMap["query_string"] = []interface{}{
-				map[string]interface{}{
-					"values": values,
-				},
-			}
-		}
-
 		if condition.SourceIpConfig != nil {
-			conditionMap["source_ip"] = []interface{}{
-				map[string]interface{}{
-					"values": aws.StringValueSlice(condition.SourceIpConfig.Values),
-				},
+			conditions[i].(map[string]interface{})["source_ip"] = []interface{}{
+				flattenLbListenerRuleSourceIp(aws.StringValueSlice(condition.SourceIpConfig.Values), stateConditions, prefixLists),
 			}
 		}
-
-		conditions[i] = conditionMap
 	}
 	d.Set("condition", conditions)
+	d.Set("condition_fingerprint", lbListenerRuleConditionFingerprint(rule.Conditions))
+	d.Set("action_change_summary", "")
+
+	var sample *lbListenerRuleRequestSample
+	if d.Get("diagnostics").(bool) {
+		sample = simulateLbListenerRuleRequest(rule.Conditions)
+	}
+	if err := d.Set("matched_request_sample", flattenLbListenerRuleRequestSample(sample)); err != nil {
+		return fmt.Errorf("error setting matched_request_sample: %s", err)
+	}
 
 	return nil
 }
 
 func resourceAwsLbListenerRuleUpdate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
+
+	// CustomizeDiff refuses any change to the rule itself while it is read
+	// only, so only the provider-side arguments can have changed.
+	if d.Get("read_only").(bool) {
+		return resourceAwsLbListenerRuleRead(d, meta)
+	}
 
 	d.Partial(true)
 
@@ -990,7 +1103,7 @@ func resourceAwsLbListenerRuleUpdate(d *schema.ResourceData, meta interface{}) e
 		RuleArn: aws.String(d.Id()),
 	}
 
-	if d.HasChange("action") {
+	if d.HasChange("action") || d.HasChange("action_order_repair_pending") || d.HasChange("target_group_name_arns") {
 		actions := d.Get("action").([]interface{})
 		params.Actions = make([]*elbv2.Action, len(actions))
 		for i, action := range actions {
@@ -1007,7 +1120,11 @@ func resourceAwsLbListenerRuleUpdate(d *schema.ResourceData, meta interface{}) e
 
 			switch actionMap["type"].(string) {
 			case "forward":
-				action.TargetGroupArn = aws.String(actionMap["target_group_arn"].(string))
+				targetGroupArn, err := lbListenerRuleActionTargetGroupArn(elbconn, actionMap)
+				if err != nil {
+					return err
+				}
+				action.TargetGroupArn = aws.String(targetGroupArn)
 
 			case "redirect":
 				redirectList := actionMap["redirect"].([]interface{})
@@ -1116,13 +1233,44 @@ func resourceAwsLbListenerRuleUpdate(d *schema.ResourceData, meta interface{}) e
 
 			params.Actions[i] = action
 		}
+		// Only the target groups the rule does not forward to yet are waited
+		// for, so that other changes to the actions are not held up.
+		o, _ := d.GetChange("action")
+		oldNameArns, _ := d.GetChange("target_group_name_arns")
+		current := make(map[string]bool)
+		for _, action := range o.([]interface{}) {
+			if actionMap, ok := action.(map[string]interface{}); ok {
+				current[actionMap["target_group_arn"].(string)] = true
+				if name, _ := actionMap["target_group_name"].(string); name != "" {
+					arn, _ := oldNameArns.(map[string]interface{})[name].(string)
+					current[arn] = true
+				}
+			}
+		}
+		var switched []string
+		for _, arn := range lbActionTargetGroupArns(params.Actions) {
+			if !current[arn] {
+				switched = append(switched, arn)
+			}
+		}
+		if err := waitForLbListenerRuleTargetHealth(elbconn, d.Get("wait_for_target_health").([]interface{}), switched); err != nil {
+			return fmt.Errorf("Error modifying LB Listener Rule: %s", err)
+		}
+
 		requestUpdate = true
 		d.SetPartial("action")
 	}
 
-	if d.HasChange("condition") {
+	var prefixLists []interface{}
+	if d.HasChange("condition") || d.HasChange("source_ip_prefix_lists") {
+		conditions := d.Get("condition").(*schema.Set).List()
 		var err error
-		params.Conditions, err = lbListenerRuleConditions(d.Get("condition").(*schema.Set).List())
+		params.Conditions, err = lbListenerRuleConditions(conditions)
+		if err != nil {
+			return err
+		}
+
+		prefixLists, err = expandLbListenerRuleSourceIpPrefixLists(resourceAWSClient(d, meta).ec2conn, conditions, params.Conditions)
 		if err != nil {
 			return err
 		}
@@ -1139,6 +1287,18 @@ func resourceAwsLbListenerRuleUpdate(d *schema.ResourceData, meta interface{}) e
 		if len(resp.Rules) == 0 {
 			return errors.New("Error modifying creating LB Listener Rule: no rules returned in response")
 		}
+
+		if params.Conditions != nil {
+			if err := tagLbListenerRuleFingerprint(elbconn, d.Id(), params.Conditions); err != nil {
+				return fmt.Errorf("Error tagging LB Listener Rule %s: %s", d.Id(), err)
+			}
+		}
+
+		if prefixLists != nil {
+			if err := d.Set("source_ip_prefix_lists", prefixLists); err != nil {
+				return fmt.Errorf("error setting source_ip_prefix_lists: %s", err)
+			}
+		}
 	}
 
 	d.Partial(false)
@@ -1146,83 +1306,790 @@ func resourceAwsLbListenerRuleUpdate(d *schema.ResourceData, meta interface{}) e
 	return resourceAwsLbListenerRuleRead(d, meta)
 }
 
-func resourceAwsLbListenerRuleDelete(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
-
-	_, err := elbconn.DeleteRule(&elbv2.DeleteRuleInput{
-		RuleArn: aws.String(d.Id()),
-	})
-	if err != nil && !isAWSErr(err, elbv2.ErrCodeRuleNotFoundException, "") {
-		return fmt.Errorf("Error deleting LB Listener Rule: %s", err)
+// resourceAwsLbListenerRuleCustomizeDiff validates the composition of the
+// action list, and plans a ModifyRule when the last read found a broken action
+// order sequence, as the renumbered actions in state otherwise match the
+// configuration. The plan of a read-only rule fails instead when the rule
+// differs from the configuration.
+func resourceAwsLbListenerRuleCustomizeDiff(diff *schema.ResourceDiff, v interface{}) error {
+	actions := diff.Get("action").([]interface{})
+	if err := validateLbListenerRuleActionLimits(actions); err != nil {
+		return err
 	}
-	return nil
-}
-
-func validateAwsLbListenerRulePriority(v interface{}, k string) (ws []string, errors []error) {
-	value := v.(int)
-	if value < 1 || (value > 50000 && value != 99999) {
-		errors = append(errors, fmt.Errorf("%q must be in the range 1-50000 for normal rule or 99999 for default rule", k))
+	if err := validateLbListenerRuleActions(actions); err != nil {
+		return err
 	}
-	return
-}
-
-// from arn:
-// arn:aws:elasticloadbalancing:us-east-1:012345678912:listener-rule/app/name/0123456789abcdef/abcdef0123456789/456789abcedf1234
-// select submatches:
-// (arn:aws:elasticloadbalancing:us-east-1:012345678912:listener)-rule(/app/name/0123456789abcdef/abcdef0123456789)/456789abcedf1234
-// concat to become:
-// arn:aws:elasticloadbalancing:us-east-1:012345678912:listener/app/name/0123456789abcdef/abcdef0123456789
-var lbListenerARNFromRuleARNRegexp = regexp.MustCompile(`^(arn:.+:listener)-rule(/.+)/[^/]+$`)
-
-func lbListenerARNFromRuleARN(ruleArn string) string {
-	if arnComponents := lbListenerARNFromRuleARNRegexp.FindStringSubmatch(ruleArn); len(arnComponents) > 1 {
-		return arnComponents[1] + arnComponents[2]
+	if client, ok := v.(*AWSClient); ok {
+		if err := client.checkLocalstackActions(actions); err != nil {
+			return err
+		}
+		if err := client.checkLocalstackConditions(diff.Get("condition").(*schema.Set).List()); err != nil {
+			return err
+		}
+	}
+	if err := validateLbListenerRuleActionTargetGroups(actions); err != nil {
+		return err
 	}
 
-	return ""
-}
+	// Target group names are resolved on every plan, so that the rule forwards
+	// again to a target group recreated under the same name.
+	if client, ok := v.(*AWSClient); ok {
+		if err := planLbListenerRuleTargetGroupNameArns(diff, client, actions); err != nil {
+			return err
+		}
+	}
 
-func highestListenerRulePriority(conn *elbv2.ELBV2, arn string) (priority int64, err error) {
-	var priorities []int
-	var nextMarker *string
+	readOnly := diff.Get("read_only").(bool)
+	if diff.Id() == "" {
+		if readOnly && diff.Get("priority").(int) == 0 {
+			return errors.New("priority must be set when read_only is true")
+		}
+		return nil
+	}
+	if readOnly {
+		return checkLbListenerRuleReadOnly(diff)
+	}
 
-	for {
-		out, aerr := conn.DescribeRules(&elbv2.DescribeRulesInput{
-			ListenerArn: aws.String(arn),
-			Marker:      nextMarker,
-		})
-		if aerr != nil {
-			err = aerr
-			return
+	// Conditions are re-resolved whenever they change, and whenever one of
+	// the prefix lists they reference has a new version.
+	if prefixLists := diff.Get("source_ip_prefix_lists").([]interface{}); diff.HasChange("condition") {
+		if err := diff.SetNewComputed("source_ip_prefix_lists"); err != nil {
+			return err
 		}
-		for _, rule := range out.Rules {
-			if aws.StringValue(rule.Priority) != "default" {
-				p, _ := strconv.Atoi(aws.StringValue(rule.Priority))
-				priorities = append(priorities, p)
+	} else if client, ok := v.(*AWSClient); ok && len(prefixLists) > 0 {
+		conn := client.regionalClient(diff.Get("region").(string)).ec2conn
+		stale, err := lbListenerRuleSourceIpPrefixListsStale(conn, prefixLists)
+		if err != nil {
+			return fmt.Errorf("Error checking prefix lists of LB Listener Rule %s: %s", diff.Id(), err)
+		}
+		if stale {
+			if err := diff.SetNewComputed("source_ip_prefix_lists"); err != nil {
+				return err
 			}
 		}
-		if out.NextMarker == nil {
-			break
+	}
+
+	if diff.HasChange("action") {
+		o, n := diff.GetChange("action")
+		if summary := summarizeLbListenerRuleActionChanges(o.([]interface{}), n.([]interface{})); summary != "" {
+			if err := diff.SetNew("action_change_summary", summary); err != nil {
+				return err
+			}
 		}
-		nextMarker = out.NextMarker
 	}
 
-	if len(priorities) == 0 {
-		priority = 0
-		return
+	if diff.Get("action_order_repair_pending").(bool) {
+		return diff.SetNew("action_order_repair_pending", false)
 	}
 
-	sort.IntSlice(priorities).Sort()
-	priority = int64(priorities[len(priorities)-1])
+	return nil
+}
 
-	return
+// lbListenerRuleActionSummaryFields are the fields of each action type shown,
+// in this order, in action_change_summary. Client secrets are left out.
+var lbListenerRuleActionSummaryFields = map[string][]string{
+	elbv2.ActionTypeEnumForward:             {"tg"},
+	elbv2.ActionTypeEnumRedirect:            {"status_code", "protocol", "host", "port", "path", "query"},
+	elbv2.ActionTypeEnumFixedResponse:       {"status_code", "content_type", "message_body"},
+	elbv2.ActionTypeEnumAuthenticateCognito: {"user_pool_arn", "user_pool_client_id", "user_pool_domain", "on_unauthenticated_request", "scope", "session_cookie_name", "session_timeout"},
+	elbv2.ActionTypeEnumAuthenticateOidc:    {"issuer", "client_id", "authorization_endpoint", "token_endpoint", "user_info_endpoint", "on_unauthenticated_request", "scope", "session_cookie_name", "session_timeout"},
 }
 
-// lbListenerRuleConditions converts data source generated by Terraform into
-// an elbv2.RuleCondition object suitable for submitting to AWS API.
-func lbListenerRuleConditions(conditions []interface{}) ([]*elbv2.RuleCondition, error) {
-	elbConditions := make([]*elbv2.RuleCondition, len(conditions))
-	for i, condition := range conditions {
+// summarizeLbListenerRuleActionChanges describes the changes between two
+// action lists, position by position, e.g. "forward tg blue→green".
+func summarizeLbListenerRuleActionChanges(o, n []interface{}) string {
+	var changes []string
+	for i := 0; i < len(o) || i < len(n); i++ {
+		switch {
+		case i >= len(n):
+			changes = append(changes, "-"+describeLbListenerRuleAction(o[i]))
+		case i >= len(o):
+			changes = append(changes, "+"+describeLbListenerRuleAction(n[i]))
+		default:
+			if change := summarizeLbListenerRuleActionChange(o[i], n[i]); change != "" {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return strings.Join(changes, "; ")
+}
+
+func summarizeLbListenerRuleActionChange(o, n interface{}) string {
+	oType, oValues := lbListenerRuleActionSummaryValues(o)
+	nType, nValues := lbListenerRuleActionSummaryValues(n)
+	if !strings.EqualFold(oType, nType) {
+		return describeLbListenerRuleAction(o) + "→" + describeLbListenerRuleAction(n)
+	}
+
+	var changes []string
+	for _, field := range lbListenerRuleActionSummaryFields[nType] {
+		if oValues[field] != nValues[field] {
+			changes = append(changes, fmt.Sprintf("%s %s→%s", field, oValues[field], nValues[field]))
+		}
+	}
+	if oOrder, nOrder := lbListenerRuleActionSummaryOrder(o), lbListenerRuleActionSummaryOrder(n); nOrder != 0 && oOrder != nOrder {
+		changes = append(changes, fmt.Sprintf("order %d→%d", oOrder, nOrder))
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+	return nType + " " + strings.Join(changes, ", ")
+}
+
+func describeLbListenerRuleAction(action interface{}) string {
+	actionType, values := lbListenerRuleActionSummaryValues(action)
+	parts := []string{actionType}
+	for _, field := range lbListenerRuleActionSummaryFields[actionType] {
+		if values[field] != "" {
+			parts = append(parts, field+" "+values[field])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// lbListenerRuleActionSummaryValues returns the type of an action and the
+// values of its summary fields. The target group of a forward action is
+// shown by name.
+func lbListenerRuleActionSummaryValues(action interface{}) (string, map[string]string) {
+	m, _ := action.(map[string]interface{})
+	actionType, _ := m["type"].(string)
+	actionType = strings.ToLower(actionType)
+	values := make(map[string]string)
+
+	if actionType == elbv2.ActionTypeEnumForward {
+		name, _ := m["target_group_name"].(string)
+		if name == "" {
+			arn, _ := m["target_group_arn"].(string)
+			name = lbTargetGroupNameFromARN(arn)
+			if name == "" {
+				name = arn
+			}
+		}
+		if name == "" {
+			name = "(known after apply)"
+		}
+		values["tg"] = name
+		return actionType, values
+	}
+
+	block := strings.Replace(actionType, "-", "_", -1)
+	if blocks, ok := m[block].([]interface{}); ok && len(blocks) > 0 {
+		if blockMap, ok := blocks[0].(map[string]interface{}); ok {
+			for _, field := range lbListenerRuleActionSummaryFields[actionType] {
+				if v, ok := blockMap[field]; ok && v != nil {
+					values[field] = fmt.Sprintf("%v", v)
+				}
+			}
+		}
+	}
+	if values["session_timeout"] == "0" {
+		values["session_timeout"] = ""
+	}
+
+	return actionType, values
+}
+
+func lbListenerRuleActionSummaryOrder(action interface{}) int {
+	m, _ := action.(map[string]interface{})
+	order, _ := m["order"].(int)
+	return order
+}
+
+func resourceAwsLbListenerRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	elbconn := resourceAWSClient(d, meta).elbv2conn
+
+	if d.Get("read_only").(bool) {
+		log.Printf("[INFO] LB Listener Rule %s is read only, removing it from state without deleting it", d.Id())
+		return nil
+	}
+
+	_, err := elbconn.DeleteRule(&elbv2.DeleteRuleInput{
+		RuleArn: aws.String(d.Id()),
+	})
+	if err != nil && !isLbNotFoundErr(err) {
+		return fmt.Errorf("Error deleting LB Listener Rule: %s", err)
+	}
+	return nil
+}
+
+// lbActionTargetGroupArns returns the target groups the forward actions
+// forward to.
+func lbActionTargetGroupArns(actions []*elbv2.Action) []string {
+	var arns []string
+	for _, action := range actions {
+		if aws.StringValue(action.Type) == elbv2.ActionTypeEnumForward && aws.StringValue(action.TargetGroupArn) != "" {
+			arns = append(arns, aws.StringValue(action.TargetGroupArn))
+		}
+	}
+	return arns
+}
+
+// waitForLbListenerRuleTargetHealth waits, as configured in the
+// wait_for_target_health block of a rule, until each of the target groups has
+// enough healthy targets for the rule to forward traffic to it.
+func waitForLbListenerRuleTargetHealth(conn *elbv2.ELBV2, config []interface{}, targetGroupArns []string) error {
+	if len(config) == 0 || config[0] == nil || len(targetGroupArns) == 0 {
+		return nil
+	}
+	m := config[0].(map[string]interface{})
+	minimum := m["minimum_healthy_targets"].(int)
+	timeout, err := time.ParseDuration(m["timeout"].(string))
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, arn := range targetGroupArns {
+		// Health checks only run once a target group is used by a load
+		// balancer, which may well be this rule, so an unused group would
+		// never become healthy before the rule is created.
+		inUse, err := lbTargetGroupInUse(conn, arn)
+		if err != nil {
+			return err
+		}
+		if !inUse {
+			log.Printf("[WARN] Target group %s is not used by any load balancer, not waiting for its targets to be healthy", arn)
+			continue
+		}
+
+		log.Printf("[INFO] Waiting for %d healthy targets in target group %s", minimum, arn)
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{"unhealthy"},
+			Target:     []string{"healthy"},
+			Refresh:    lbTargetGroupHealthyRefreshFunc(conn, arn, minimum),
+			Timeout:    lbTargetHealthWaitTimeout(deadline, 5*time.Second),
+			MinTimeout: 5 * time.Second,
+		}
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf("waiting for %d healthy targets in target group %s: %s", minimum, arn, err)
+		}
+	}
+
+	return nil
+}
+
+// lbTargetGroupHealthyRefreshFunc reports a target group as healthy once it
+// has at least minimum healthy targets.
+func lbTargetGroupHealthyRefreshFunc(conn *elbv2.ELBV2, arn string, minimum int) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := conn.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(arn),
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		healthy := lbHealthyTargetCount(resp.TargetHealthDescriptions)
+		log.Printf("[DEBUG] %d of %d targets healthy in target group %s", healthy, len(resp.TargetHealthDescriptions), arn)
+		if healthy < minimum && lbTargetsAllUnused(resp.TargetHealthDescriptions) {
+			return nil, "", fmt.Errorf("all %d targets in target group %s are unused, e.g. in an Availability Zone the load balancer does not use", len(resp.TargetHealthDescriptions), arn)
+		}
+		if healthy < minimum {
+			return resp, "unhealthy", nil
+		}
+		return resp, "healthy", nil
+	}
+}
+
+// lbTargetGroupInUse reports whether a target group is used by any load
+// balancer.
+func lbTargetGroupInUse(conn *elbv2.ELBV2, arn string) (bool, error) {
+	resp, err := conn.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: []*string{aws.String(arn)},
+	})
+	if err != nil {
+		return false, fmt.Errorf("Error retrieving Target Group %s: %s", arn, err)
+	}
+	if len(resp.TargetGroups) == 0 {
+		return false, fmt.Errorf("Target Group %s not found", arn)
+	}
+	return len(resp.TargetGroups[0].LoadBalancerArns) > 0, nil
+}
+
+// lbTargetHealthWaitTimeout returns the time left until deadline, but at least
+// minimum, so that a target group is still checked once after the time for
+// the groups waited for before it has run out.
+func lbTargetHealthWaitTimeout(deadline time.Time, minimum time.Duration) time.Duration {
+	if remaining := time.Until(deadline); remaining > minimum {
+		return remaining
+	}
+	return minimum
+}
+
+// lbTargetsAllUnused reports whether there are targets in descriptions and
+// none of them are checked by the load balancer, so that none of them can
+// become healthy.
+func lbTargetsAllUnused(descriptions []*elbv2.TargetHealthDescription) bool {
+	if len(descriptions) == 0 {
+		return false
+	}
+	for _, description := range descriptions {
+		if description.TargetHealth == nil || aws.StringValue(description.TargetHealth.State) != elbv2.TargetHealthStateEnumUnused {
+			return false
+		}
+	}
+	return true
+}
+
+// lbHealthyTargetCount counts the healthy targets in descriptions.
+func lbHealthyTargetCount(descriptions []*elbv2.TargetHealthDescription) int {
+	healthy := 0
+	for _, description := range descriptions {
+		if description.TargetHealth != nil && aws.StringValue(description.TargetHealth.State) == elbv2.TargetHealthStateEnumHealthy {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// validateLbListenerRuleActionTargetGroups checks that forward actions do not
+// set both target_group_arn and target_group_name.
+func validateLbListenerRuleActionTargetGroups(actions []interface{}) error {
+	for i, action := range actions {
+		actionMap, ok := action.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		arn, _ := actionMap["target_group_arn"].(string)
+		name, _ := actionMap["target_group_name"].(string)
+		if arn != "" && name != "" {
+			return fmt.Errorf("action.%d: only one of target_group_arn or target_group_name can be set", i)
+		}
+	}
+	return nil
+}
+
+// lbListenerRuleActionTargetGroupNames returns the distinct target group
+// names the forward actions refer to.
+func lbListenerRuleActionTargetGroupNames(actions []interface{}) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, action := range actions {
+		actionMap, ok := action.(map[string]interface{})
+		if !ok || actionMap["type"] != "forward" {
+			continue
+		}
+		if name, _ := actionMap["target_group_name"].(string); name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// planLbListenerRuleTargetGroupNameArns plans target_group_name_arns from the
+// target group names of the forward actions. It is left to be computed while
+// a name is not known yet, or names a target group that does not exist yet,
+// e.g. one created in the same apply.
+func planLbListenerRuleTargetGroupNameArns(diff *schema.ResourceDiff, client *AWSClient, actions []interface{}) error {
+	for i := range actions {
+		if !diff.NewValueKnown(fmt.Sprintf("action.%d.target_group_name", i)) {
+			return diff.SetNewComputed("target_group_name_arns")
+		}
+	}
+
+	names := lbListenerRuleActionTargetGroupNames(actions)
+	if len(names) == 0 {
+		return nil
+	}
+
+	resolved, err := resolveLbTargetGroupNames(client.regionalClient(diff.Get("region").(string)).elbv2conn, names)
+	if err != nil {
+		return err
+	}
+	if len(resolved) < len(names) {
+		return diff.SetNewComputed("target_group_name_arns")
+	}
+	if !reflect.DeepEqual(resolved, diff.Get("target_group_name_arns").(map[string]interface{})) {
+		return diff.SetNew("target_group_name_arns", resolved)
+	}
+	return nil
+}
+
+// resolveLbTargetGroupNames maps each of the target group names to its ARN.
+// Names of target groups that do not exist are left out. They are looked up
+// one by one, as DescribeTargetGroups fails for all the names when one of
+// them does not exist.
+func resolveLbTargetGroupNames(conn *elbv2.ELBV2, names []string) (map[string]interface{}, error) {
+	arns := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		resp, err := conn.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+			Names: aws.StringSlice([]string{name}),
+		})
+		if isAWSErrCode(err, elbv2.ErrCodeTargetGroupNotFoundException) {
+			log.Printf("[DEBUG] Target group %s not found", name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error resolving target group name %s: %s", name, err)
+		}
+
+		for _, targetGroup := range resp.TargetGroups {
+			arns[aws.StringValue(targetGroup.TargetGroupName)] = aws.StringValue(targetGroup.TargetGroupArn)
+		}
+	}
+	return arns, nil
+}
+
+// lbListenerRuleActionTargetGroupArn returns the ARN of the target group of a
+// forward action, resolving its target_group_name when set.
+func lbListenerRuleActionTargetGroupArn(conn *elbv2.ELBV2, actionMap map[string]interface{}) (string, error) {
+	name, _ := actionMap["target_group_name"].(string)
+	if name == "" {
+		return actionMap["target_group_arn"].(string), nil
+	}
+
+	arns, err := resolveLbTargetGroupNames(conn, []string{name})
+	if err != nil {
+		return "", err
+	}
+	arn, _ := arns[name].(string)
+	if arn == "" {
+		return "", fmt.Errorf("Error resolving target group name %s: not found", name)
+	}
+
+	log.Printf("[DEBUG] Resolved target group name %s to %s", name, arn)
+	return arn, nil
+}
+
+// checkLbListenerRuleReadOnly fails the plan of a read-only rule whose last
+// read differs from the configuration, rather than planning the writes that
+// would bring it back in line.
+func checkLbListenerRuleReadOnly(diff *schema.ResourceDiff) error {
+	var changed []string
+	for _, k := range []string{"listener_arn", "priority", "action", "condition", "target_group_name_arns"} {
+		if diff.HasChange(k) {
+			changed = append(changed, k)
+		}
+	}
+	if diff.Get("action_order_repair_pending").(bool) && !diff.HasChange("action") {
+		changed = append(changed, "action")
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("LB Listener Rule %s is read only and no longer matches the configuration, changed: %s", diff.Id(), strings.Join(changed, ", "))
+}
+
+// lbTerminalActionTypes are the action types that end rule evaluation.
+var lbTerminalActionTypes = map[string]bool{
+	"forward":        true,
+	"redirect":       true,
+	"fixed-response": true,
+}
+
+// lbAuthenticateActionTypes are the action types that authenticate users.
+var lbAuthenticateActionTypes = map[string]bool{
+	"authenticate-cognito": true,
+	"authenticate-oidc":    true,
+}
+
+const (
+	lbMaxActionsPerRule             = 5
+	lbMaxAuthenticateActionsPerRule = 2
+)
+
+// validateLbListenerRuleActionLimits checks the elbv2 limits on the number of
+// actions, and of authenticate actions, in a single rule.
+func validateLbListenerRuleActionLimits(actions []interface{}) error {
+	if len(actions) > lbMaxActionsPerRule {
+		return fmt.Errorf("a rule can have at most %d actions, found %d", lbMaxActionsPerRule, len(actions))
+	}
+
+	var authenticate []string
+	for i, action := range actions {
+		actionMap, ok := action.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if actionType, _ := actionMap["type"].(string); lbAuthenticateActionTypes[actionType] {
+			authenticate = append(authenticate, fmt.Sprintf("action.%d", i))
+		}
+	}
+
+	if len(authenticate) > lbMaxAuthenticateActionsPerRule {
+		return fmt.Errorf("a rule can have at most %d authenticate actions, found %d (%s)",
+			lbMaxAuthenticateActionsPerRule, len(authenticate), strings.Join(authenticate, ", "))
+	}
+
+	return nil
+}
+
+// lbListenerRuleActionsInOrder returns the indexes of actions in the order they
+// are evaluated: by their order argument when set, otherwise by position.
+func lbListenerRuleActionsInOrder(actions []interface{}) []int {
+	indexes := make([]int, len(actions))
+	orders := make([]int, len(actions))
+	for i, action := range actions {
+		indexes[i] = i
+		orders[i] = i + 1
+		if actionMap, ok := action.(map[string]interface{}); ok {
+			if order, ok := actionMap["order"].(int); ok && order != 0 {
+				orders[i] = order
+			}
+		}
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return orders[indexes[i]] < orders[indexes[j]]
+	})
+
+	return indexes
+}
+
+// validateLbListenerRuleActions checks that a rule has exactly one terminal
+// action and that it is evaluated last. Actions whose type is not yet known
+// are skipped.
+func validateLbListenerRuleActions(actions []interface{}) error {
+	var terminal []string
+	last := -1
+	unknown := len(actions) == 0
+
+	for _, i := range lbListenerRuleActionsInOrder(actions) {
+		actionMap, ok := actions[i].(map[string]interface{})
+		if !ok {
+			unknown = true
+			continue
+		}
+		actionType, _ := actionMap["type"].(string)
+		if actionType == "" {
+			unknown = true
+			continue
+		}
+
+		if lbTerminalActionTypes[actionType] {
+			terminal = append(terminal, fmt.Sprintf("%s at action.%d", actionType, i))
+			last = i
+			continue
+		}
+
+		if last != -1 {
+			terminalType := actions[last].(map[string]interface{})["type"]
+			if lbAuthenticateActionTypes[actionType] {
+				return fmt.Errorf("action.%d (%s) is evaluated after the terminal %s action at action.%d; authenticate actions must precede the terminal action",
+					i, actionType, terminalType, last)
+			}
+			return fmt.Errorf("action.%d (%s) is evaluated after the terminal %s action at action.%d; the forward, redirect or fixed-response action must be last",
+				i, actionType, terminalType, last)
+		}
+	}
+
+	switch {
+	case len(terminal) > 1:
+		return fmt.Errorf("a rule must have exactly one forward, redirect or fixed-response action, found %d (%s)",
+			len(terminal), strings.Join(terminal, ", "))
+	case len(terminal) == 0 && !unknown:
+		return fmt.Errorf("a rule must have exactly one forward, redirect or fixed-response action, found none")
+	}
+
+	return nil
+}
+
+// from arn:
+// arn:aws:elasticloadbalancing:us-east-1:012345678912:listener-rule/app/name/0123456789abcdef/abcdef0123456789/456789abcedf1234
+// select submatches:
+// (arn:aws:elasticloadbalancing:us-east-1:012345678912:listener)-rule(/app/name/0123456789abcdef/abcdef0123456789)/456789abcedf1234
+// concat to become:
+// arn:aws:elasticloadbalancing:us-east-1:012345678912:listener/app/name/0123456789abcdef/abcdef0123456789
+var lbListenerARNFromRuleARNRegexp = regexp.MustCompile(`^(arn:.+:listener)-rule(/.+)/[^/]+$`)
+
+func lbListenerARNFromRuleARN(ruleArn string) string {
+	if arnComponents := lbListenerARNFromRuleARNRegexp.FindStringSubmatch(ruleArn); len(arnComponents) > 1 {
+		return arnComponents[1] + arnComponents[2]
+	}
+
+	return ""
+}
+
+// lbListenerRuleMatchingAtPriority returns the rule at the priority of params
+// on its listener when its conditions and actions are those of params, and nil
+// otherwise.
+func lbListenerRuleMatchingAtPriority(conn *elbv2.ELBV2, params *elbv2.CreateRuleInput) (*elbv2.Rule, error) {
+	rules, err := describeLbListenerRules(conn, aws.StringValue(params.ListenerArn))
+	if err != nil {
+		return nil, err
+	}
+
+	rule, ok := lbListenerRulesByPriority(rules)[strconv.FormatInt(aws.Int64Value(params.Priority), 10)]
+	if !ok {
+		return nil, nil
+	}
+
+	if !lbListenerRuleMatches(rule, params) {
+		return nil, nil
+	}
+
+	return rule, nil
+}
+
+// lbListenerRuleMatches reports whether the conditions and actions of an
+// existing rule are those of params. The conditions are compared as they are,
+// regardless of the fingerprint tag of the rule, which is not updated when
+// they are changed outside of Terraform.
+func lbListenerRuleMatches(rule *elbv2.Rule, params *elbv2.CreateRuleInput) bool {
+	if lbListenerRuleConditionFingerprint(rule.Conditions) != lbListenerRuleConditionFingerprint(params.Conditions) {
+		log.Printf("[DEBUG] Conditions of LB Listener Rule %s differ from the configuration", aws.StringValue(rule.RuleArn))
+		return false
+	}
+	if !lbListenerRuleActionsMatch(params.Actions, rule.Actions) {
+		log.Printf("[DEBUG] Actions of LB Listener Rule %s differ from the configuration", aws.StringValue(rule.RuleArn))
+		return false
+	}
+	return true
+}
+
+// lbActionsHaveType reports whether any of the actions is of the given type.
+func lbActionsHaveType(actions []*elbv2.Action, actionType string) bool {
+	for _, action := range actions {
+		if aws.StringValue(action.Type) == actionType {
+			return true
+		}
+	}
+	return false
+}
+
+// lbListenerRuleActionsMatch reports whether the actions of an existing rule
+// are the configured actions. The client secret of authenticate-oidc actions
+// is not compared, as the API does not return it.
+func lbListenerRuleActionsMatch(configured, existing []*elbv2.Action) bool {
+	if len(configured) != len(existing) {
+		return false
+	}
+
+	byOrder := make(map[int64]*elbv2.Action, len(existing))
+	for _, action := range existing {
+		byOrder[aws.Int64Value(action.Order)] = action
+	}
+
+	for _, a := range configured {
+		b, ok := byOrder[aws.Int64Value(a.Order)]
+		if !ok || aws.StringValue(a.Type) != aws.StringValue(b.Type) {
+			return false
+		}
+
+		switch aws.StringValue(a.Type) {
+		case elbv2.ActionTypeEnumForward:
+			if aws.StringValue(a.TargetGroupArn) != aws.StringValue(b.TargetGroupArn) {
+				return false
+			}
+		case elbv2.ActionTypeEnumRedirect:
+			if a.RedirectConfig == nil || b.RedirectConfig == nil {
+				return false
+			}
+			x, y := a.RedirectConfig, b.RedirectConfig
+			if aws.StringValue(x.Host) != aws.StringValue(y.Host) ||
+				aws.StringValue(x.Path) != aws.StringValue(y.Path) ||
+				aws.StringValue(x.Port) != aws.StringValue(y.Port) ||
+				aws.StringValue(x.Protocol) != aws.StringValue(y.Protocol) ||
+				aws.StringValue(x.Query) != aws.StringValue(y.Query) ||
+				aws.StringValue(x.StatusCode) != aws.StringValue(y.StatusCode) {
+				return false
+			}
+		case elbv2.ActionTypeEnumFixedResponse:
+			if a.FixedResponseConfig == nil || b.FixedResponseConfig == nil {
+				return false
+			}
+			x, y := a.FixedResponseConfig, b.FixedResponseConfig
+			if aws.StringValue(x.ContentType) != aws.StringValue(y.ContentType) ||
+				aws.StringValue(x.MessageBody) != aws.StringValue(y.MessageBody) ||
+				aws.StringValue(x.StatusCode) != aws.StringValue(y.StatusCode) {
+				return false
+			}
+		case elbv2.ActionTypeEnumAuthenticateCognito:
+			if a.AuthenticateCognitoConfig == nil || b.AuthenticateCognitoConfig == nil {
+				return false
+			}
+			x, y := a.AuthenticateCognitoConfig, b.AuthenticateCognitoConfig
+			if aws.StringValue(x.UserPoolArn) != aws.StringValue(y.UserPoolArn) ||
+				aws.StringValue(x.UserPoolClientId) != aws.StringValue(y.UserPoolClientId) ||
+				aws.StringValue(x.UserPoolDomain) != aws.StringValue(y.UserPoolDomain) ||
+				aws.StringValue(x.OnUnauthenticatedRequest) != aws.StringValue(y.OnUnauthenticatedRequest) ||
+				aws.StringValue(x.Scope) != aws.StringValue(y.Scope) ||
+				aws.StringValue(x.SessionCookieName) != aws.StringValue(y.SessionCookieName) ||
+				aws.Int64Value(x.SessionTimeout) != aws.Int64Value(y.SessionTimeout) ||
+				!reflect.DeepEqual(aws.StringValueMap(x.AuthenticationRequestExtraParams), aws.StringValueMap(y.AuthenticationRequestExtraParams)) {
+				return false
+			}
+		case elbv2.ActionTypeEnumAuthenticateOidc:
+			if a.AuthenticateOidcConfig == nil || b.AuthenticateOidcConfig == nil {
+				return false
+			}
+			x, y := a.AuthenticateOidcConfig, b.AuthenticateOidcConfig
+			if aws.StringValue(x.Issuer) != aws.StringValue(y.Issuer) ||
+				aws.StringValue(x.ClientId) != aws.StringValue(y.ClientId) ||
+				aws.StringValue(x.AuthorizationEndpoint) != aws.StringValue(y.AuthorizationEndpoint) ||
+				aws.StringValue(x.TokenEndpoint) != aws.StringValue(y.TokenEndpoint) ||
+				aws.StringValue(x.UserInfoEndpoint) != aws.StringValue(y.UserInfoEndpoint) ||
+				aws.StringValue(x.OnUnauthenticatedRequest) != aws.StringValue(y.OnUnauthenticatedRequest) ||
+				aws.StringValue(x.Scope) != aws.StringValue(y.Scope) ||
+				aws.StringValue(x.SessionCookieName) != aws.StringValue(y.SessionCookieName) ||
+				aws.Int64Value(x.SessionTimeout) != aws.Int64Value(y.SessionTimeout) ||
+				!reflect.DeepEqual(aws.StringValueMap(x.AuthenticationRequestExtraParams), aws.StringValueMap(y.AuthenticationRequestExtraParams)) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkLbListenerExists returns an error naming the listener when no listener
+// has the ARN arn.
+func checkLbListenerExists(conn *elbv2.ELBV2, arn string) error {
+	_, err := conn.DescribeListeners(&elbv2.DescribeListenersInput{
+		ListenerArns: []*string{aws.String(arn)},
+	})
+	if isAWSErrCode(err, elbv2.ErrCodeListenerNotFoundException) {
+		return fmt.Errorf("Error creating LB Listener Rule: listener %s not found", arn)
+	}
+	if err != nil {
+		return fmt.Errorf("Error describing LB Listener %s: %s", arn, err)
+	}
+	return nil
+}
+
+func highestListenerRulePriority(conn *elbv2.ELBV2, arn string) (priority int64, err error) {
+	var priorities []int
+
+	err = paginate("DescribeRules", func(marker *string) (*string, error) {
+		out, err := conn.DescribeRules(&elbv2.DescribeRulesInput{
+			ListenerArn: aws.String(arn),
+			Marker:      marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range out.Rules {
+			if aws.StringValue(rule.Priority) != "default" {
+				p, _ := strconv.Atoi(aws.StringValue(rule.Priority))
+				priorities = append(priorities, p)
+			}
+		}
+		return out.NextMarker, nil
+	})
+	if err != nil {
+		return
+	}
+
+	if len(priorities) == 0 {
+		priority = 0
+		return
+	}
+
+	sort.IntSlice(priorities).Sort()
+	priority = int64(priorities[len(priorities)-1])
+
+	return
+}
+
+// lbListenerRuleConditions converts data source generated by Terraform into
+// an elbv2.RuleCondition object suitable for submitting to AWS API.
+func lbListenerRuleConditions(conditions []interface{}) ([]*elbv2.RuleCondition, error) {
+	elbConditions := make([]*elbv2.RuleCondition, len(conditions))
+	for i, condition := range conditions {
 		conditionMap := condition.(map[string]interface{})
 		conditionValues := conditionMap["values"].([]interface{})
 
@@ -1304,10 +2171,14 @@ func lbListenerRuleConditions(conditions []interface{}) ([]*elbv2.RuleCondition,
 			}
 		case "source-ip":
 			sourceIp := conditionMap["source_ip"].([]interface{})
-			if len(sourceIp) == 0 {
+			if len(sourceIp) == 0 || sourceIp[0] == nil {
 				return nil, errors.New("source_ip must be set when condition field is source-ip")
 			}
-			values := sourceIp[0].(map[string]interface{})["values"].([]interface{})
+			sourceIpMap := sourceIp[0].(map[string]interface{})
+			values := sourceIpMap["values"].([]interface{})
+			if len(values) == 0 && len(sourceIpMap["prefix_list_ids"].([]interface{})) == 0 {
+				return nil, errors.New("source_ip must specify values or prefix_list_ids")
+			}
 
 			elbConditions[i].SourceIpConfig = &elbv2.SourceIpConditionConfig{
 				Values: interfaceStringSlice(values),
@@ -1316,3 +2187,292 @@ func lbListenerRuleConditions(conditions []interface{}) ([]*elbv2.RuleCondition,
 	}
 	return elbConditions, nil
 }
+
+// normalizeLbListenerRuleActionOrder sorts actions by Order and reports
+// whether the sequence is broken, i.e. an action has no order or shares its
+// order with another action. Only a broken sequence is renumbered from 1;
+// valid orders, gapped or not, are kept as configured.
+func normalizeLbListenerRuleActionOrder(actions []*elbv2.Action) bool {
+	sort.SliceStable(actions, func(i, j int) bool {
+		return aws.Int64Value(actions[i].Order) < aws.Int64Value(actions[j].Order)
+	})
+
+	broken := false
+	for i, action := range actions {
+		if aws.Int64Value(action.Order) == 0 || (i > 0 && aws.Int64Value(action.Order) == aws.Int64Value(actions[i-1].Order)) {
+			broken = true
+			break
+		}
+	}
+
+	if broken {
+		for i, action := range actions {
+			action.Order = aws.Int64(int64(i + 1))
+		}
+	}
+
+	return broken
+}
+
+// resourceAwsLbListenerRuleImport accepts either a rule ARN or, in
+// import_by_conditions mode, a listener ARN followed by "?" and the rule's
+// conditions, e.g. "<listener-arn>?host-header=example.com&path-pattern=/api/*".
+// The listener is searched for the single rule whose condition fingerprint
+// matches, so rules can be adopted without first looking up their ARNs. A rule
+// whose fingerprint tag matches is preferred over one whose conditions do.
+func resourceAwsLbListenerRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "?", 2)
+	if len(parts) != 2 {
+		return []*schema.ResourceData{d}, nil
+	}
+	listenerArn, spec := parts[0], parts[1]
+
+	fingerprint, err := lbListenerRuleImportFingerprint(spec)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing import ID %q: %s", d.Id(), err)
+	}
+
+	conn := resourceAWSClient(d, meta).elbv2conn
+	rules, err := describeLbListenerRules(conn, listenerArn)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving rules for listener %q: %s", listenerArn, err)
+	}
+
+	matchingRules, err := findLbListenerRulesByFingerprint(conn, rules, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving tags of rules for listener %q: %s", listenerArn, err)
+	}
+
+	var matches []string
+	for _, rule := range matchingRules {
+		matches = append(matches, aws.StringValue(rule.RuleArn))
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no rule on listener %q matches conditions %q", listenerArn, spec)
+	case 1:
+		log.Printf("[DEBUG] Conditions %q matched rule %s", spec, matches[0])
+		d.SetId(matches[0])
+		return []*schema.ResourceData{d}, nil
+	default:
+		return nil, fmt.Errorf("%d rules on listener %q match conditions %q: %s", len(matches), listenerArn, spec, strings.Join(matches, ", "))
+	}
+}
+
+// lbListenerRuleImportFingerprint returns the condition fingerprint described
+// by the query part of an import_by_conditions ID. The query is either
+// "fingerprint=<hex>" or "&"-separated "<field>=<value>[,<value>...]" pairs,
+// where HTTP header conditions are written as "http-header.<name>" and query
+// string values as "<key>:<value>".
+func lbListenerRuleImportFingerprint(spec string) (string, error) {
+	if strings.HasPrefix(spec, "fingerprint=") {
+		return strings.TrimPrefix(spec, "fingerprint="), nil
+	}
+
+	var conditions []*elbv2.RuleCondition
+	for _, pair := range strings.Split(spec, "&") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return "", fmt.Errorf("expected <field>=<values>, got %q", pair)
+		}
+		field, values := kv[0], aws.StringSlice(strings.Split(kv[1], ","))
+
+		condition := &elbv2.RuleCondition{
+			Field: aws.String(field),
+		}
+		switch {
+		case field == "host-header":
+			condition.HostHeaderConfig = &elbv2.HostHeaderConditionConfig{Values: values}
+		case strings.HasPrefix(field, "http-header."):
+			condition.Field = aws.String("http-header")
+			condition.HttpHeaderConfig = &elbv2.HttpHeaderConditionConfig{
+				HttpHeaderName: aws.String(strings.TrimPrefix(field, "http-header.")),
+				Values:         values,
+			}
+		case field == "http-request-method":
+			condition.HttpRequestMethodConfig = &elbv2.HttpRequestMethodConditionConfig{Values: values}
+		case field == "path-pattern":
+			condition.PathPatternConfig = &elbv2.PathPatternConditionConfig{Values: values}
+		case field == "query-string":
+			condition.QueryStringConfig = &elbv2.QueryStringConditionConfig{}
+			for _, v := range values {
+				pair := &elbv2.QueryStringKeyValuePair{}
+				if kv := strings.SplitN(aws.StringValue(v), ":", 2); len(kv) == 2 {
+					if kv[0] != "" {
+						pair.Key = aws.String(kv[0])
+					}
+					pair.Value = aws.String(kv[1])
+				} else {
+					pair.Value = v
+				}
+				condition.QueryStringConfig.Values = append(condition.QueryStringConfig.Values, pair)
+			}
+		case field == "source-ip":
+			condition.SourceIpConfig = &elbv2.SourceIpConditionConfig{Values: values}
+		default:
+			return "", fmt.Errorf("unsupported condition field %q", field)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return lbListenerRuleConditionFingerprint(conditions), nil
+}
+
+// lbListenerRuleConditionFingerprint returns a stable hash of a rule's
+// conditions. Neither the order of the conditions nor the order of the values
+// within them affects the result.
+func lbListenerRuleConditionFingerprint(conditions []*elbv2.RuleCondition) string {
+	entries := make([]string, len(conditions))
+	for i, condition := range conditions {
+		key, values := lbListenerRuleConditionKeyValues(condition)
+		for j, v := range values {
+			values[j] = strconv.Quote(v)
+		}
+		sort.Strings(values)
+		entries[i] = key + "=" + strings.Join(values, ",")
+	}
+	sort.Strings(entries)
+
+	sum := sha256.Sum256([]byte(strings.Join(entries, "&")))
+	return hex.EncodeToString(sum[:])
+}
+
+// lbListenerRuleFingerprintTagKey is the tag holding the fingerprint of the
+// conditions a rule was created or last updated with. It identifies the rule
+// when the API returns its conditions in a different form than they were
+// written in, which changes their fingerprint.
+const lbListenerRuleFingerprintTagKey = "awspresence:fingerprint"
+
+// tagLbListenerRuleFingerprint sets the fingerprint tag of a rule to the
+// fingerprint of conditions.
+func tagLbListenerRuleFingerprint(conn *elbv2.ELBV2, ruleArn string, conditions []*elbv2.RuleCondition) error {
+	fingerprint := lbListenerRuleConditionFingerprint(conditions)
+	log.Printf("[DEBUG] Tagging LB Listener Rule %s with fingerprint %s", ruleArn, fingerprint)
+	_, err := conn.AddTags(&elbv2.AddTagsInput{
+		ResourceArns: []*string{aws.String(ruleArn)},
+		Tags: []*elbv2.Tag{
+			{
+				Key:   aws.String(lbListenerRuleFingerprintTagKey),
+				Value: aws.String(fingerprint),
+			},
+		},
+	})
+	return err
+}
+
+// findLbListenerRulesByFingerprint returns the rules whose fingerprint tag or
+// conditions have the fingerprint.
+func findLbListenerRulesByFingerprint(conn *elbv2.ELBV2, rules []*elbv2.Rule, fingerprint string) ([]*elbv2.Rule, error) {
+	tags, err := describeLbListenerRuleFingerprintTags(conn, rules)
+	if err != nil {
+		return nil, err
+	}
+	return lbListenerRulesMatchingFingerprint(rules, tags, fingerprint), nil
+}
+
+// describeLbListenerRuleFingerprintTags returns the fingerprint tag of each
+// rule that has one, by rule ARN.
+func describeLbListenerRuleFingerprintTags(conn *elbv2.ELBV2, rules []*elbv2.Rule) (map[string]string, error) {
+	tags := make(map[string]string)
+	if len(rules) == 0 {
+		return tags, nil
+	}
+
+	// DescribeTags accepts at most 20 resources per call, so the rules are
+	// described in batches, the marker holding the index of the next batch.
+	err := paginate("DescribeTags", func(marker *string) (*string, error) {
+		start := 0
+		if marker != nil {
+			start, _ = strconv.Atoi(aws.StringValue(marker))
+		}
+		end := start + 20
+		if end > len(rules) {
+			end = len(rules)
+		}
+		arns := make([]*string, 0, end-start)
+		for _, rule := range rules[start:end] {
+			arns = append(arns, rule.RuleArn)
+		}
+
+		resp, err := conn.DescribeTags(&elbv2.DescribeTagsInput{
+			ResourceArns: arns,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, description := range resp.TagDescriptions {
+			for _, tag := range description.Tags {
+				if aws.StringValue(tag.Key) == lbListenerRuleFingerprintTagKey {
+					tags[aws.StringValue(description.ResourceArn)] = aws.StringValue(tag.Value)
+				}
+			}
+		}
+
+		if end == len(rules) {
+			return nil, nil
+		}
+		return aws.String(strconv.Itoa(end)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// lbListenerRulesMatchingFingerprint returns the rules whose conditions have
+// the fingerprint, preferring those whose fingerprint tag also has it. Only
+// when the conditions of no rule match are the rules returned whose tag alone
+// has the fingerprint, as the conditions of a tagged rule may since have been
+// changed outside of Terraform.
+func lbListenerRulesMatchingFingerprint(rules []*elbv2.Rule, tags map[string]string, fingerprint string) []*elbv2.Rule {
+	var matchedTagged, matched, tagged []*elbv2.Rule
+	for _, rule := range rules {
+		hasTag := tags[aws.StringValue(rule.RuleArn)] == fingerprint
+		switch {
+		case lbListenerRuleConditionFingerprint(rule.Conditions) == fingerprint:
+			matched = append(matched, rule)
+			if hasTag {
+				matchedTagged = append(matchedTagged, rule)
+			}
+		case hasTag:
+			tagged = append(tagged, rule)
+		}
+	}
+
+	switch {
+	case len(matchedTagged) > 0:
+		return matchedTagged
+	case len(matched) > 0:
+		return matched
+	}
+	return tagged
+}
+
+func lbListenerRuleConditionKeyValues(condition *elbv2.RuleCondition) (string, []string) {
+	field := aws.StringValue(condition.Field)
+
+	switch {
+	case condition.HostHeaderConfig != nil:
+		return field, aws.StringValueSlice(condition.HostHeaderConfig.Values)
+	case condition.HttpHeaderConfig != nil:
+		// Header names are matched case-insensitively by the load balancer.
+		name := strings.ToLower(aws.StringValue(condition.HttpHeaderConfig.HttpHeaderName))
+		return field + "." + name, aws.StringValueSlice(condition.HttpHeaderConfig.Values)
+	case condition.HttpRequestMethodConfig != nil:
+		return field, aws.StringValueSlice(condition.HttpRequestMethodConfig.Values)
+	case condition.PathPatternConfig != nil:
+		return field, aws.StringValueSlice(condition.PathPatternConfig.Values)
+	case condition.QueryStringConfig != nil:
+		values := make([]string, len(condition.QueryStringConfig.Values))
+		for i, pair := range condition.QueryStringConfig.Values {
+			values[i] = aws.StringValue(pair.Key) + ":" + aws.StringValue(pair.Value)
+		}
+		return field, values
+	case condition.SourceIpConfig != nil:
+		return field, aws.StringValueSlice(condition.SourceIpConfig.Values)
+	}
+
+	return field, aws.StringValueSlice(condition.Values)
+}
@@ -3,13 +3,20 @@ package awspresence
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/flatten"
+	awsbase "github.com/hashicorp/aws-sdk-go-base"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -54,6 +61,866 @@ func TestLBListenerARNFromRuleARN(t *testing.T) {
 	}
 }
 
+func TestLbListenerRuleConditionFingerprint(t *testing.T) {
+	conditions := []*elbv2.RuleCondition{
+		{
+			Field: aws.String("path-pattern"),
+			PathPatternConfig: &elbv2.PathPatternConditionConfig{
+				Values: aws.StringSlice([]string{"/api/*", "/v2/*"}),
+			},
+		},
+		{
+			Field: aws.String("http-header"),
+			HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+				HttpHeaderName: aws.String("X-Env"),
+				Values:         aws.StringSlice([]string{"prod"}),
+			},
+		},
+	}
+	reordered := []*elbv2.RuleCondition{
+		{
+			Field: aws.String("http-header"),
+			HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+				HttpHeaderName: aws.String("x-env"),
+				Values:         aws.StringSlice([]string{"prod"}),
+			},
+		},
+		{
+			Field:  aws.String("path-pattern"),
+			Values: aws.StringSlice([]string{"/v2/*", "/api/*"}),
+		},
+	}
+	different := []*elbv2.RuleCondition{
+		{
+			Field: aws.String("path-pattern"),
+			PathPatternConfig: &elbv2.PathPatternConditionConfig{
+				Values: aws.StringSlice([]string{"/api/*"}),
+			},
+		},
+	}
+
+	fingerprint := lbListenerRuleConditionFingerprint(conditions)
+	if got := lbListenerRuleConditionFingerprint(reordered); got != fingerprint {
+		t.Fatalf("expected reordered conditions to share fingerprint %s, got %s", fingerprint, got)
+	}
+	if got := lbListenerRuleConditionFingerprint(different); got == fingerprint {
+		t.Fatalf("expected different conditions to have a different fingerprint")
+	}
+	if got := aws.StringValue(conditions[0].PathPatternConfig.Values[0]); got != "/api/*" {
+		t.Fatalf("expected conditions to be left untouched, got %s", got)
+	}
+}
+
+// The conditions are stored as a set, so the order in which the API returns
+// them does not change the state.
+func TestLbListenerRuleConditionSetOrder(t *testing.T) {
+	conditions := []*elbv2.RuleCondition{
+		{
+			Field:             aws.String("path-pattern"),
+			PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice([]string{"/static/*"})},
+		},
+		{
+			Field:            aws.String("host-header"),
+			HostHeaderConfig: &elbv2.HostHeaderConditionConfig{Values: aws.StringSlice([]string{"example.com"})},
+		},
+		{
+			Field: aws.String("http-header"),
+			HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+				HttpHeaderName: aws.String("X-Env"),
+				Values:         aws.StringSlice([]string{"prod"}),
+			},
+		},
+	}
+	reversed := []*elbv2.RuleCondition{conditions[2], conditions[1], conditions[0]}
+
+	conditionState := func(conditions []*elbv2.RuleCondition) map[string]string {
+		m, err := flatten.Rule(&elbv2.Rule{Priority: aws.String("10"), Conditions: conditions}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := schema.TestResourceDataRaw(t, resourceAwsLbbListenerRule().Schema, map[string]interface{}{})
+		d.SetId("rule")
+		if err := d.Set("condition", m["condition"]); err != nil {
+			t.Fatal(err)
+		}
+
+		attributes := make(map[string]string)
+		for k, v := range d.State().Attributes {
+			if strings.HasPrefix(k, "condition.") {
+				attributes[k] = v
+			}
+		}
+		return attributes
+	}
+
+	expected := conditionState(conditions)
+	if expected["condition.#"] != "3" {
+		t.Fatalf("expected 3 conditions in state, got %v", expected)
+	}
+	if actual := conditionState(reversed); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected condition state %v regardless of API order, got %v", expected, actual)
+	}
+}
+
+func TestLbListenerRuleImportFingerprint(t *testing.T) {
+	conditions := []*elbv2.RuleCondition{
+		{
+			Field: aws.String("host-header"),
+			HostHeaderConfig: &elbv2.HostHeaderConditionConfig{
+				Values: aws.StringSlice([]string{"example.com"}),
+			},
+		},
+		{
+			Field: aws.String("http-header"),
+			HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{
+				HttpHeaderName: aws.String("X-Env"),
+				Values:         aws.StringSlice([]string{"prod", "staging"}),
+			},
+		},
+		{
+			Field: aws.String("query-string"),
+			QueryStringConfig: &elbv2.QueryStringConditionConfig{
+				Values: []*elbv2.QueryStringKeyValuePair{
+					{Key: aws.String("version"), Value: aws.String("2")},
+					{Value: aws.String("beta")},
+				},
+			},
+		},
+	}
+	expected := lbListenerRuleConditionFingerprint(conditions)
+
+	cases := []struct {
+		Spec     string
+		Expected string
+		ErrCount int
+	}{
+		{
+			Spec:     "host-header=example.com&http-header.X-Env=staging,prod&query-string=version:2,beta",
+			Expected: expected,
+		},
+		{
+			Spec:     "query-string=:beta,version:2&http-header.x-env=prod,staging&host-header=example.com",
+			Expected: expected,
+		},
+		{
+			Spec:     "fingerprint=" + expected,
+			Expected: expected,
+		},
+		{
+			Spec:     "host-header",
+			ErrCount: 1,
+		},
+		{
+			Spec:     "cookie=session",
+			ErrCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		fingerprint, err := lbListenerRuleImportFingerprint(tc.Spec)
+		if tc.ErrCount == 0 && err != nil {
+			t.Fatalf("%q: unexpected error: %s", tc.Spec, err)
+		}
+		if tc.ErrCount > 0 && err == nil {
+			t.Fatalf("%q: expected an error", tc.Spec)
+		}
+		if fingerprint != tc.Expected {
+			t.Fatalf("%q: expected fingerprint %s, got %s", tc.Spec, tc.Expected, fingerprint)
+		}
+	}
+}
+
+func TestDescribeLbListenerRuleFingerprintTags(t *testing.T) {
+	var rules []*elbv2.Rule
+	for i := 1; i <= 21; i++ {
+		rules = append(rules, &elbv2.Rule{RuleArn: aws.String(fmt.Sprintf("rule-%d", i))})
+	}
+
+	// Rules are described 20 at a time.
+	describeTags := func(rules []*elbv2.Rule, response string) *awsbase.MockEndpoint {
+		body := url.Values{
+			"Action":  []string{"DescribeTags"},
+			"Version": []string{"2015-12-01"},
+		}
+		for i, rule := range rules {
+			body.Set(fmt.Sprintf("ResourceArns.member.%d", i+1), aws.StringValue(rule.RuleArn))
+		}
+		return &awsbase.MockEndpoint{
+			Request: &awsbase.MockRequest{
+				Method: "POST",
+				Uri:    "/",
+				Body:   body.Encode(),
+			},
+			Response: &awsbase.MockResponse{
+				StatusCode:  200,
+				Body:        response,
+				ContentType: "text/xml",
+			},
+		}
+	}
+	closeFunc, sess, err := awsbase.GetMockedAwsApiSession("ELBv2", []*awsbase.MockEndpoint{
+		describeTags(rules[:20], fmt.Sprintf(test_elbv2_describeTags_response, "rule-1", "abc")),
+		describeTags(rules[20:], fmt.Sprintf(test_elbv2_describeTags_response, "rule-21", "def")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFunc()
+
+	tags, err := describeLbListenerRuleFingerprintTags(elbv2.New(sess), rules)
+	if err != nil {
+		t.Fatalf("Expected no error, received: %s", err)
+	}
+	if expected := map[string]string{"rule-1": "abc", "rule-21": "def"}; !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("Received tags: %v\nExpected: %v", tags, expected)
+	}
+}
+
+var test_elbv2_describeTags_response = `<DescribeTagsResponse xmlns="http://elasticloadbalancing.amazonaws.com/doc/2015-12-01/">
+  <DescribeTagsResult>
+    <TagDescriptions>
+      <member>
+        <ResourceArn>%s</ResourceArn>
+        <Tags>
+          <member>
+            <Key>awspresence:fingerprint</Key>
+            <Value>%s</Value>
+          </member>
+        </Tags>
+      </member>
+    </TagDescriptions>
+  </DescribeTagsResult>
+  <ResponseMetadata>
+    <RequestId>6c4a9a5e-6d0e-11e9-9c5f-1b2a3c4d5e6f</RequestId>
+  </ResponseMetadata>
+</DescribeTagsResponse>`
+
+func TestLbListenerRulesMatchingFingerprint(t *testing.T) {
+	rule := func(arn, path string) *elbv2.Rule {
+		return &elbv2.Rule{
+			RuleArn: aws.String(arn),
+			Conditions: []*elbv2.RuleCondition{
+				{
+					Field:             aws.String("path-pattern"),
+					PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice([]string{path})},
+				},
+			},
+		}
+	}
+	static := rule("static", "/static/*")
+	staticCopy := rule("static-copy", "/static/*")
+	legacy := rule("legacy", "/assets/*")
+	rules := []*elbv2.Rule{static, staticCopy, legacy}
+	fingerprint := lbListenerRuleConditionFingerprint(static.Conditions)
+
+	cases := []struct {
+		name     string
+		rules    []*elbv2.Rule
+		tags     map[string]string
+		expected []*elbv2.Rule
+	}{
+		{
+			name:     "untagged",
+			rules:    rules,
+			tags:     map[string]string{},
+			expected: []*elbv2.Rule{static, staticCopy},
+		},
+		{
+			name:     "tag preferred among matching conditions",
+			rules:    rules,
+			tags:     map[string]string{"static-copy": fingerprint},
+			expected: []*elbv2.Rule{staticCopy},
+		},
+		{
+			name:     "stale tag loses to matching conditions",
+			rules:    rules,
+			tags:     map[string]string{"legacy": fingerprint},
+			expected: []*elbv2.Rule{static, staticCopy},
+		},
+		{
+			name:     "tag only when no conditions match",
+			rules:    []*elbv2.Rule{legacy},
+			tags:     map[string]string{"legacy": fingerprint},
+			expected: []*elbv2.Rule{legacy},
+		},
+		{
+			name:     "other fingerprint",
+			rules:    rules,
+			tags:     map[string]string{"static": "0123"},
+			expected: []*elbv2.Rule{static, staticCopy},
+		},
+	}
+
+	for _, tc := range cases {
+		if actual := lbListenerRulesMatchingFingerprint(tc.rules, tc.tags, fingerprint); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %d rules, got %d", tc.name, len(tc.expected), len(actual))
+		}
+	}
+}
+
+func TestNormalizeLbListenerRuleActionOrder(t *testing.T) {
+	cases := []struct {
+		Orders         []int64
+		Types          []string
+		Broken         bool
+		ExpectedOrders []int64
+		ExpectedTypes  []string
+	}{
+		{
+			Orders:         []int64{1, 2},
+			Types:          []string{"authenticate-oidc", "forward"},
+			Broken:         false,
+			ExpectedOrders: []int64{1, 2},
+			ExpectedTypes:  []string{"authenticate-oidc", "forward"},
+		},
+		{
+			Orders:         []int64{20, 10},
+			Types:          []string{"forward", "authenticate-oidc"},
+			Broken:         false,
+			ExpectedOrders: []int64{10, 20},
+			ExpectedTypes:  []string{"authenticate-oidc", "forward"},
+		},
+		{
+			Orders:         []int64{2},
+			Types:          []string{"forward"},
+			Broken:         false,
+			ExpectedOrders: []int64{2},
+			ExpectedTypes:  []string{"forward"},
+		},
+		{
+			Orders:         []int64{1, 1, 2},
+			Types:          []string{"authenticate-cognito", "authenticate-oidc", "forward"},
+			Broken:         true,
+			ExpectedOrders: []int64{1, 2, 3},
+			ExpectedTypes:  []string{"authenticate-cognito", "authenticate-oidc", "forward"},
+		},
+		{
+			Orders:         []int64{5, 0},
+			Types:          []string{"forward", "authenticate-oidc"},
+			Broken:         true,
+			ExpectedOrders: []int64{1, 2},
+			ExpectedTypes:  []string{"authenticate-oidc", "forward"},
+		},
+	}
+
+	for i, tc := range cases {
+		actions := make([]*elbv2.Action, len(tc.Orders))
+		for j := range tc.Orders {
+			actions[j] = &elbv2.Action{
+				Order: aws.Int64(tc.Orders[j]),
+				Type:  aws.String(tc.Types[j]),
+			}
+		}
+
+		if broken := normalizeLbListenerRuleActionOrder(actions); broken != tc.Broken {
+			t.Fatalf("case %d: expected broken to be %t, got %t", i, tc.Broken, broken)
+		}
+
+		for j, action := range actions {
+			if aws.Int64Value(action.Order) != tc.ExpectedOrders[j] {
+				t.Fatalf("case %d: expected action %d to have order %d, got %d", i, j, tc.ExpectedOrders[j], aws.Int64Value(action.Order))
+			}
+			if aws.StringValue(action.Type) != tc.ExpectedTypes[j] {
+				t.Fatalf("case %d: expected action %d to be %s, got %s", i, j, tc.ExpectedTypes[j], aws.StringValue(action.Type))
+			}
+		}
+	}
+}
+
+func TestValidateLbListenerRuleActionTargetGroups(t *testing.T) {
+	forward := func(arn, name string) interface{} {
+		return map[string]interface{}{
+			"type":              "forward",
+			"target_group_arn":  arn,
+			"target_group_name": name,
+		}
+	}
+
+	valid := []interface{}{
+		forward("arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/test/1", ""),
+		forward("", "test"),
+	}
+	if err := validateLbListenerRuleActionTargetGroups(valid); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	invalid := []interface{}{
+		forward("arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/test/1", "test"),
+	}
+	if err := validateLbListenerRuleActionTargetGroups(invalid); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestLbListenerRuleActionTargetGroupNames(t *testing.T) {
+	actions := []interface{}{
+		map[string]interface{}{"type": "authenticate-oidc", "target_group_name": "ignored"},
+		map[string]interface{}{"type": "forward", "target_group_name": "blue"},
+		map[string]interface{}{"type": "forward", "target_group_name": ""},
+		map[string]interface{}{"type": "forward", "target_group_name": "blue"},
+		map[string]interface{}{"type": "forward", "target_group_name": "green"},
+	}
+
+	expected := []string{"blue", "green"}
+	if actual := lbListenerRuleActionTargetGroupNames(actions); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestLbActionTargetGroupArns(t *testing.T) {
+	actions := []*elbv2.Action{
+		{Type: aws.String("authenticate-oidc")},
+		{Type: aws.String("forward"), TargetGroupArn: aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/blue/1")},
+		{Type: aws.String("fixed-response")},
+	}
+
+	expected := []string{"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/blue/1"}
+	if actual := lbActionTargetGroupArns(actions); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestSummarizeLbListenerRuleActionChanges(t *testing.T) {
+	forward := func(order int, arn string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":             "forward",
+			"order":            order,
+			"target_group_arn": arn,
+		}
+	}
+	fixedResponse := func(order int, statusCode string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":  "fixed-response",
+			"order": order,
+			"fixed_response": []interface{}{
+				map[string]interface{}{
+					"content_type": "text/plain",
+					"message_body": "",
+					"status_code":  statusCode,
+				},
+			},
+		}
+	}
+	blue := "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/blue/1"
+	green := "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/green/2"
+
+	cases := []struct {
+		name     string
+		o, n     []interface{}
+		expected string
+	}{
+		{
+			name:     "target group",
+			o:        []interface{}{forward(1, blue)},
+			n:        []interface{}{forward(1, green)},
+			expected: "forward tg blue→green",
+		},
+		{
+			name: "target group by name",
+			o:    []interface{}{forward(1, blue)},
+			n: []interface{}{map[string]interface{}{
+				"type":              "forward",
+				"order":             1,
+				"target_group_arn":  "",
+				"target_group_name": "green",
+			}},
+			expected: "forward tg blue→green",
+		},
+		{
+			name:     "unknown target group",
+			o:        []interface{}{forward(1, blue)},
+			n:        []interface{}{forward(1, "")},
+			expected: "forward tg blue→(known after apply)",
+		},
+		{
+			name:     "type",
+			o:        []interface{}{forward(1, blue)},
+			n:        []interface{}{fixedResponse(1, "503")},
+			expected: "forward tg blue→fixed-response status_code 503 content_type text/plain",
+		},
+		{
+			name:     "fields and order",
+			o:        []interface{}{fixedResponse(1, "200")},
+			n:        []interface{}{fixedResponse(2, "503")},
+			expected: "fixed-response status_code 200→503, order 1→2",
+		},
+		{
+			name:     "added and removed",
+			o:        []interface{}{forward(1, blue)},
+			n:        []interface{}{fixedResponse(1, "503"), forward(2, green)},
+			expected: "forward tg blue→fixed-response status_code 503 content_type text/plain; +forward tg green",
+		},
+		{
+			name:     "removed",
+			o:        []interface{}{fixedResponse(1, "503"), forward(2, green)},
+			n:        []interface{}{fixedResponse(1, "503")},
+			expected: "-forward tg green",
+		},
+		{
+			name:     "unchanged",
+			o:        []interface{}{forward(1, blue)},
+			n:        []interface{}{forward(1, blue)},
+			expected: "",
+		},
+	}
+
+	for _, tc := range cases {
+		if actual := summarizeLbListenerRuleActionChanges(tc.o, tc.n); actual != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestLbHealthyTargetCount(t *testing.T) {
+	target := func(state string) *elbv2.TargetHealthDescription {
+		return &elbv2.TargetHealthDescription{
+			TargetHealth: &elbv2.TargetHealth{State: aws.String(state)},
+		}
+	}
+	descriptions := []*elbv2.TargetHealthDescription{
+		target(elbv2.TargetHealthStateEnumHealthy),
+		target(elbv2.TargetHealthStateEnumInitial),
+		target(elbv2.TargetHealthStateEnumHealthy),
+		target(elbv2.TargetHealthStateEnumUnused),
+		{},
+	}
+
+	if actual := lbHealthyTargetCount(descriptions); actual != 2 {
+		t.Fatalf("expected 2 healthy targets, got %d", actual)
+	}
+}
+
+func TestLbTargetsAllUnused(t *testing.T) {
+	target := func(state string) *elbv2.TargetHealthDescription {
+		return &elbv2.TargetHealthDescription{
+			TargetHealth: &elbv2.TargetHealth{State: aws.String(state)},
+		}
+	}
+
+	testCases := []struct {
+		Descriptions []*elbv2.TargetHealthDescription
+		Expected     bool
+	}{
+		{
+			Descriptions: nil,
+			Expected:     false,
+		},
+		{
+			Descriptions: []*elbv2.TargetHealthDescription{
+				target(elbv2.TargetHealthStateEnumUnused),
+				target(elbv2.TargetHealthStateEnumUnused),
+			},
+			Expected: true,
+		},
+		{
+			Descriptions: []*elbv2.TargetHealthDescription{
+				target(elbv2.TargetHealthStateEnumUnused),
+				target(elbv2.TargetHealthStateEnumInitial),
+			},
+			Expected: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		if actual := lbTargetsAllUnused(tc.Descriptions); actual != tc.Expected {
+			t.Errorf("case %d: expected %t, got %t", i, tc.Expected, actual)
+		}
+	}
+}
+
+func TestLbTargetHealthWaitTimeout(t *testing.T) {
+	if actual := lbTargetHealthWaitTimeout(time.Now().Add(-time.Minute), 5*time.Second); actual != 5*time.Second {
+		t.Errorf("expected a passed deadline to leave the minimum, got %s", actual)
+	}
+	if actual := lbTargetHealthWaitTimeout(time.Now().Add(time.Hour), 5*time.Second); actual <= 59*time.Minute {
+		t.Errorf("expected about an hour left, got %s", actual)
+	}
+}
+
+func TestLbListenerRuleMatches(t *testing.T) {
+	pathPattern := func(path string) []*elbv2.RuleCondition {
+		return []*elbv2.RuleCondition{
+			{
+				Field:             aws.String("path-pattern"),
+				PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice([]string{path})},
+			},
+		}
+	}
+	actions := []*elbv2.Action{
+		{
+			Order:          aws.Int64(1),
+			Type:           aws.String("forward"),
+			TargetGroupArn: aws.String("tg-a"),
+		},
+	}
+
+	// The rule was created for /static/*, and so tagged with the fingerprint
+	// of those conditions, before they were changed outside of Terraform.
+	rule := &elbv2.Rule{
+		RuleArn:    aws.String("rule"),
+		Conditions: pathPattern("/assets/*"),
+		Actions:    actions,
+	}
+
+	if lbListenerRuleMatches(rule, &elbv2.CreateRuleInput{Conditions: pathPattern("/static/*"), Actions: actions}) {
+		t.Error("expected a rule with other conditions not to match, whatever its fingerprint tag")
+	}
+	if !lbListenerRuleMatches(rule, &elbv2.CreateRuleInput{Conditions: pathPattern("/assets/*"), Actions: actions}) {
+		t.Error("expected a rule with the same conditions and actions to match")
+	}
+}
+
+func TestLbListenerRuleActionsMatch(t *testing.T) {
+	forward := func(order int64, arn string) *elbv2.Action {
+		return &elbv2.Action{
+			Order:          aws.Int64(order),
+			Type:           aws.String("forward"),
+			TargetGroupArn: aws.String(arn),
+		}
+	}
+	fixedResponse := func(order int64, body *string) *elbv2.Action {
+		return &elbv2.Action{
+			Order: aws.Int64(order),
+			Type:  aws.String("fixed-response"),
+			FixedResponseConfig: &elbv2.FixedResponseActionConfig{
+				ContentType: aws.String("text/plain"),
+				MessageBody: body,
+				StatusCode:  aws.String("503"),
+			},
+		}
+	}
+	cognito := func(modify func(*elbv2.AuthenticateCognitoActionConfig)) *elbv2.Action {
+		config := &elbv2.AuthenticateCognitoActionConfig{
+			UserPoolArn:                      aws.String("arn:aws:cognito-idp:us-west-2:123456789012:userpool/us-west-2_abc"),
+			UserPoolClientId:                 aws.String("client"),
+			UserPoolDomain:                   aws.String("domain"),
+			OnUnauthenticatedRequest:         aws.String("authenticate"),
+			Scope:                            aws.String("openid"),
+			SessionCookieName:                aws.String("AWSELBAuthSessionCookie"),
+			SessionTimeout:                   aws.Int64(604800),
+			AuthenticationRequestExtraParams: aws.StringMap(map[string]string{"display": "page"}),
+		}
+		if modify != nil {
+			modify(config)
+		}
+		return &elbv2.Action{
+			Order:                     aws.Int64(1),
+			Type:                      aws.String("authenticate-cognito"),
+			AuthenticateCognitoConfig: config,
+		}
+	}
+	oidc := func(modify func(*elbv2.AuthenticateOidcActionConfig)) *elbv2.Action {
+		config := &elbv2.AuthenticateOidcActionConfig{
+			Issuer:                           aws.String("https://example.com"),
+			ClientId:                         aws.String("client"),
+			AuthorizationEndpoint:            aws.String("https://example.com/authorization"),
+			TokenEndpoint:                    aws.String("https://example.com/token"),
+			UserInfoEndpoint:                 aws.String("https://example.com/userinfo"),
+			OnUnauthenticatedRequest:         aws.String("authenticate"),
+			Scope:                            aws.String("openid"),
+			SessionCookieName:                aws.String("AWSELBAuthSessionCookie"),
+			SessionTimeout:                   aws.Int64(604800),
+			AuthenticationRequestExtraParams: aws.StringMap(map[string]string{"display": "page"}),
+		}
+		if modify != nil {
+			modify(config)
+		}
+		return &elbv2.Action{
+			Order:                  aws.Int64(1),
+			Type:                   aws.String("authenticate-oidc"),
+			AuthenticateOidcConfig: config,
+		}
+	}
+
+	cases := []struct {
+		Name       string
+		Configured []*elbv2.Action
+		Existing   []*elbv2.Action
+		Expected   bool
+	}{
+		{"same forward", []*elbv2.Action{forward(1, "tg-a")}, []*elbv2.Action{forward(1, "tg-a")}, true},
+		{"other target group", []*elbv2.Action{forward(1, "tg-a")}, []*elbv2.Action{forward(1, "tg-b")}, false},
+		{"other order", []*elbv2.Action{forward(1, "tg-a")}, []*elbv2.Action{forward(2, "tg-a")}, false},
+		{"extra action", []*elbv2.Action{forward(1, "tg-a")}, []*elbv2.Action{fixedResponse(1, nil), forward(2, "tg-a")}, false},
+		{"empty and missing message body", []*elbv2.Action{fixedResponse(1, aws.String(""))}, []*elbv2.Action{fixedResponse(1, nil)}, true},
+		{"other message body", []*elbv2.Action{fixedResponse(1, aws.String("down"))}, []*elbv2.Action{fixedResponse(1, nil)}, false},
+		{"same cognito", []*elbv2.Action{cognito(nil), forward(2, "tg-a")}, []*elbv2.Action{cognito(nil), forward(2, "tg-a")}, true},
+		{"other cognito scope", []*elbv2.Action{cognito(nil)}, []*elbv2.Action{cognito(func(c *elbv2.AuthenticateCognitoActionConfig) { c.Scope = aws.String("openid email") })}, false},
+		{"other cognito session cookie name", []*elbv2.Action{cognito(nil)}, []*elbv2.Action{cognito(func(c *elbv2.AuthenticateCognitoActionConfig) { c.SessionCookieName = aws.String("session") })}, false},
+		{"other cognito session timeout", []*elbv2.Action{cognito(nil)}, []*elbv2.Action{cognito(func(c *elbv2.AuthenticateCognitoActionConfig) { c.SessionTimeout = aws.Int64(3600) })}, false},
+		{"other cognito on unauthenticated request", []*elbv2.Action{cognito(nil)}, []*elbv2.Action{cognito(func(c *elbv2.AuthenticateCognitoActionConfig) { c.OnUnauthenticatedRequest = aws.String("deny") })}, false},
+		{"other cognito extra params", []*elbv2.Action{cognito(nil)}, []*elbv2.Action{cognito(func(c *elbv2.AuthenticateCognitoActionConfig) {
+			c.AuthenticationRequestExtraParams = aws.StringMap(map[string]string{"display": "popup"})
+		})}, false},
+		{"same oidc", []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) { c.ClientSecret = aws.String("secret") }), forward(2, "tg-a")}, []*elbv2.Action{oidc(nil), forward(2, "tg-a")}, true},
+		{"other oidc issuer", []*elbv2.Action{oidc(nil)}, []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) { c.Issuer = aws.String("https://example.org") })}, false},
+		{"other oidc client id", []*elbv2.Action{oidc(nil)}, []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) { c.ClientId = aws.String("other") })}, false},
+		{"other oidc token endpoint", []*elbv2.Action{oidc(nil)}, []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) { c.TokenEndpoint = aws.String("https://example.com/other") })}, false},
+		{"other oidc scope", []*elbv2.Action{oidc(nil)}, []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) { c.Scope = aws.String("openid email") })}, false},
+		{"other oidc session cookie name", []*elbv2.Action{oidc(nil)}, []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) { c.SessionCookieName = aws.String("session") })}, false},
+		{"other oidc session timeout", []*elbv2.Action{oidc(nil)}, []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) { c.SessionTimeout = aws.Int64(3600) })}, false},
+		{"other oidc on unauthenticated request", []*elbv2.Action{oidc(nil)}, []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) { c.OnUnauthenticatedRequest = aws.String("deny") })}, false},
+		{"other oidc extra params", []*elbv2.Action{oidc(nil)}, []*elbv2.Action{oidc(func(c *elbv2.AuthenticateOidcActionConfig) {
+			c.AuthenticationRequestExtraParams = aws.StringMap(map[string]string{"display": "popup"})
+		})}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := lbListenerRuleActionsMatch(tc.Configured, tc.Existing); got != tc.Expected {
+				t.Errorf("expected %t, got %t", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateLbListenerRuleActions(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Actions []interface{}
+		Error   string
+	}{
+		{
+			Name: "forward only",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "forward"},
+			},
+		},
+		{
+			Name: "authenticate then forward",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "authenticate-oidc"},
+				map[string]interface{}{"type": "forward"},
+			},
+		},
+		{
+			Name: "order puts forward last",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "forward", "order": 2},
+				map[string]interface{}{"type": "authenticate-cognito", "order": 1},
+			},
+		},
+		{
+			Name: "unknown type",
+			Actions: []interface{}{
+				map[string]interface{}{"type": ""},
+			},
+		},
+		{
+			Name: "forward and redirect",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "forward"},
+				map[string]interface{}{"type": "redirect"},
+			},
+			Error: "found 2 (forward at action.0, redirect at action.1)",
+		},
+		{
+			Name: "no terminal action",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "authenticate-oidc"},
+			},
+			Error: "found none",
+		},
+		{
+			Name: "terminal action not last",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "fixed-response"},
+				map[string]interface{}{"type": "authenticate-oidc"},
+			},
+			Error: "action.1 (authenticate-oidc) is evaluated after the terminal fixed-response action at action.0",
+		},
+		{
+			Name: "order puts forward first",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "authenticate-cognito", "order": 5},
+				map[string]interface{}{"type": "forward", "order": 1},
+			},
+			Error: "action.0 (authenticate-cognito) is evaluated after the terminal forward action at action.1",
+		},
+	}
+
+	for _, tc := range cases {
+		err := validateLbListenerRuleActions(tc.Actions)
+		if tc.Error == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", tc.Name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Fatalf("%s: expected error containing %q", tc.Name, tc.Error)
+		}
+		if !strings.Contains(err.Error(), tc.Error) {
+			t.Fatalf("%s: expected error containing %q, got %q", tc.Name, tc.Error, err)
+		}
+	}
+}
+
+func TestValidateLbListenerRuleActionLimits(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Actions []interface{}
+		Error   string
+	}{
+		{
+			Name: "two authenticate actions",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "authenticate-oidc"},
+				map[string]interface{}{"type": "authenticate-cognito"},
+				map[string]interface{}{"type": "forward"},
+			},
+		},
+		{
+			Name: "five actions",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "authenticate-oidc"},
+				map[string]interface{}{"type": "authenticate-cognito"},
+				map[string]interface{}{"type": ""},
+				map[string]interface{}{"type": ""},
+				map[string]interface{}{"type": "forward"},
+			},
+		},
+		{
+			Name: "three authenticate actions",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "authenticate-oidc"},
+				map[string]interface{}{"type": "forward"},
+				map[string]interface{}{"type": "authenticate-cognito"},
+				map[string]interface{}{"type": "authenticate-oidc"},
+			},
+			Error: "at most 2 authenticate actions, found 3 (action.0, action.2, action.3)",
+		},
+		{
+			Name: "six actions",
+			Actions: []interface{}{
+				map[string]interface{}{"type": "authenticate-oidc"},
+				map[string]interface{}{"type": "authenticate-oidc"},
+				map[string]interface{}{"type": ""},
+				map[string]interface{}{"type": ""},
+				map[string]interface{}{"type": ""},
+				map[string]interface{}{"type": "forward"},
+			},
+			Error: "at most 5 actions, found 6",
+		},
+	}
+
+	for _, tc := range cases {
+		err := validateLbListenerRuleActionLimits(tc.Actions)
+		if tc.Error == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", tc.Name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Fatalf("%s: expected error containing %q", tc.Name, tc.Error)
+		}
+		if !strings.Contains(err.Error(), tc.Error) {
+			t.Fatalf("%s: expected error containing %q, got %q", tc.Name, tc.Error, err)
+		}
+	}
+}
+
 func TestAccAWSLBListenerRule_basic(t *testing.T) {
 	var conf elbv2.Rule
 	lbName := fmt.Sprintf("testrule-basic-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
@@ -80,6 +947,7 @@ func TestAccAWSLBListenerRule_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "action.0.fixed_response.#", "0"),
 					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "action.0.authenticate_cognito.#", "0"),
 					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "action.0.authenticate_oidc.#", "0"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "action_change_summary", ""),
 					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "condition.#", "1"),
 					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "condition.447032695.field", "path-pattern"),
 					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "condition.447032695.host_header.#", "0"),
@@ -96,6 +964,51 @@ func TestAccAWSLBListenerRule_basic(t *testing.T) {
 	})
 }
 
+func TestAccAWSLBListenerRule_importByConditions(t *testing.T) {
+	lbName := fmt.Sprintf("testrule-import-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	resourceName := "aws_lb_listener_rule.static"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "condition_fingerprint"),
+					testAccCheckAWSLBListenerRuleFingerprintTag(resourceName),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("Not found: %s", resourceName)
+					}
+					return rs.Primary.Attributes["listener_arn"] + "?path-pattern=/static/*", nil
+				},
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("Not found: %s", resourceName)
+					}
+					return rs.Primary.Attributes["listener_arn"] + "?fingerprint=" + rs.Primary.Attributes["condition_fingerprint"], nil
+				},
+			},
+		},
+	})
+}
+
 func TestAccAWSLBListenerRuleBackwardsCompatibility(t *testing.T) {
 	var conf elbv2.Rule
 	lbName := fmt.Sprintf("testrule-basic-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
@@ -285,6 +1198,45 @@ func TestAccAWSLBListenerRule_multipleConditionThrowsError(t *testing.T) {
 	})
 }
 
+func TestAccAWSLBListenerRule_listenerArnOnly(t *testing.T) {
+	var conf elbv2.Rule
+	lbName := fmt.Sprintf("testrule-arn-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	resourceName := "aws_lb_listener_rule.static"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBListenerRuleConfig_listenerArnOnly(lbName, targetGroupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBListenerRuleExists(resourceName, &conf),
+					resource.TestCheckResourceAttrPair(resourceName, "listener_arn", "data.aws_lb_listener.front_end", "arn"),
+					resource.TestCheckResourceAttr(resourceName, "priority", "100"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSLBListenerRule_listenerArnNotFound(t *testing.T) {
+	lbName := fmt.Sprintf("testrule-arn-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAWSLBListenerRuleConfig_listenerArnNotFound(lbName),
+				ExpectError: regexp.MustCompile(`Error creating LB Listener Rule: listener arn:[^ ]+:listener/app/` + lbName + `/[^ ]+ not found`),
+			},
+		},
+	})
+}
+
 func TestAccAWSLBListenerRule_priority(t *testing.T) {
 	var rule elbv2.Rule
 	lbName := fmt.Sprintf("testrule-basic-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
@@ -359,21 +1311,125 @@ func TestAccAWSLBListenerRule_priority(t *testing.T) {
 	})
 }
 
+func TestAccAWSLBListenerRule_adoptMatching(t *testing.T) {
+	var rule elbv2.Rule
+	lbName := fmt.Sprintf("testrule-adopt-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAWSLBListenerRuleConfig_adoptMatching(lbName, targetGroupName, "/50000_in_use/*"),
+				ExpectError: regexp.MustCompile(`Error creating LB Listener Rule: PriorityInUse`),
+			},
+			{
+				Config: testAccAWSLBListenerRuleConfig_adoptMatching(lbName, targetGroupName, "/50000/*"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBListenerRuleExists("aws_lb_listener_rule.adopted", &rule),
+					resource.TestCheckResourceAttrPair("aws_lb_listener_rule.adopted", "arn", "aws_lb_listener_rule.priority50000", "arn"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.adopted", "priority", "50000"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSLBListenerRule_targetGroupName(t *testing.T) {
+	var rule elbv2.Rule
+	lbName := fmt.Sprintf("testrule-tgname-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBListenerRuleConfig_targetGroupName(lbName, targetGroupName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBListenerRuleExists("aws_lb_listener_rule.by_name", &rule),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.by_name", "action.0.target_group_name", targetGroupName),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.by_name", "action.0.target_group_arn", ""),
+					resource.TestCheckResourceAttrPair("aws_lb_listener_rule.by_name", "target_group_name_arns."+targetGroupName, "aws_lb_target_group.test", "arn"),
+				),
+			},
+			{
+				Config:      testAccAWSLBListenerRuleConfig_targetGroupNameAndArn(lbName, targetGroupName),
+				ExpectError: regexp.MustCompile(`only one of target_group_arn or target_group_name can be set`),
+			},
+		},
+	})
+}
+
+func TestAccAWSLBListenerRule_waitForTargetHealth(t *testing.T) {
+	lbName := fmt.Sprintf("testrule-health-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				// The target group has no targets, so never becomes healthy.
+				Config:      testAccAWSLBListenerRuleConfig_waitForTargetHealth(lbName, targetGroupName),
+				ExpectError: regexp.MustCompile(`waiting for 1 healthy targets in target group`),
+			},
+		},
+	})
+}
+
+func TestAccAWSLBListenerRule_readOnly(t *testing.T) {
+	var rule elbv2.Rule
+	lbName := fmt.Sprintf("testrule-readonly-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAWSLBListenerRuleConfig_readOnly(lbName, targetGroupName, "/50000_in_use/*"),
+				ExpectError: regexp.MustCompile(`no rule at priority 50000 of listener .* matches the configuration`),
+			},
+			{
+				Config: testAccAWSLBListenerRuleConfig_readOnly(lbName, targetGroupName, "/50000/*"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBListenerRuleExists("aws_lb_listener_rule.read_only", &rule),
+					resource.TestCheckResourceAttrPair("aws_lb_listener_rule.read_only", "arn", "aws_lb_listener_rule.priority50000", "arn"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.read_only", "read_only", "true"),
+				),
+			},
+			{
+				Config:      testAccAWSLBListenerRuleConfig_readOnly(lbName, targetGroupName, "/changed/*"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`is read only and no longer matches the configuration, changed: condition`),
+			},
+		},
+	})
+}
+
 func TestAccAWSLBListenerRule_cognito(t *testing.T) {
 	var conf elbv2.Rule
 	lbName := fmt.Sprintf("testrule-cognito-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
 	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
 	certificateName := fmt.Sprintf("testcert-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
 	cognitoPrefix := fmt.Sprintf("testcog-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
 
 	resource.ParallelTest(t, resource.TestCase{
-		PreCheck:      func() { testAccPreCheck(t) },
+		PreCheck:      func() { testAccPreCheck(t); testAccPreCheckLocalstackFeature(t, localstackFeatureAuthenticateCognito) },
 		IDRefreshName: "aws_lb_listener_rule.cognito",
-		Providers:     testAccProvidersWithTLS,
+		Providers:     testAccProviders,
 		CheckDestroy:  testAccCheckAWSLBListenerRuleDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccAWSLBListenerRuleConfig_cognito(lbName, targetGroupName, certificateName, cognitoPrefix),
+				Config: testAccAWSLBListenerRuleConfig_cognito(lbName, targetGroupName, certificateName, cognitoPrefix, key, certificate),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					testAccCheckAWSLBListenerRuleExists("aws_lb_listener_rule.cognito", &conf),
 					resource.TestCheckResourceAttrSet("aws_lb_listener_rule.cognito", "arn"),
@@ -402,15 +1458,17 @@ func TestAccAWSLBListenerRule_oidc(t *testing.T) {
 	lbName := fmt.Sprintf("testrule-oidc-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
 	targetGroupName := fmt.Sprintf("testtargetgroup-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
 	certificateName := fmt.Sprintf("testcert-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
 
 	resource.ParallelTest(t, resource.TestCase{
-		PreCheck:      func() { testAccPreCheck(t) },
+		PreCheck:      func() { testAccPreCheck(t); testAccPreCheckLocalstackFeature(t, localstackFeatureAuthenticateOidc) },
 		IDRefreshName: "aws_lb_listener_rule.oidc",
-		Providers:     testAccProvidersWithTLS,
+		Providers:     testAccProviders,
 		CheckDestroy:  testAccCheckAWSLBListenerRuleDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccAWSLBListenerRuleConfig_oidc(lbName, targetGroupName, certificateName),
+				Config: testAccAWSLBListenerRuleConfig_oidc(lbName, targetGroupName, certificateName, key, certificate),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					testAccCheckAWSLBListenerRuleExists("aws_lb_listener_rule.oidc", &conf),
 					resource.TestCheckResourceAttrSet("aws_lb_listener_rule.oidc", "arn"),
@@ -441,14 +1499,16 @@ func TestAccAWSLBListenerRule_Action_Order(t *testing.T) {
 	var rule elbv2.Rule
 	rName := acctest.RandomWithPrefix("tf-acc-test")
 	resourceName := "aws_lb_listener_rule.test"
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
 
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProvidersWithTLS,
+		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccAWSLBListenerRuleConfig_Action_Order(rName),
+				Config: testAccAWSLBListenerRuleConfig_Action_Order(rName, key, certificate),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					testAccCheckAWSLBListenerRuleExists(resourceName, &rule),
 					resource.TestCheckResourceAttr(resourceName, "action.#", "2"),
@@ -461,18 +1521,49 @@ func TestAccAWSLBListenerRule_Action_Order(t *testing.T) {
 }
 
 // Reference: https://github.com/terraform-providers/terraform-provider-aws/issues/6171
+func TestAccAWSLBListenerRule_Action_Order_Gapped(t *testing.T) {
+	var rule elbv2.Rule
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "aws_lb_listener_rule.test"
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBListenerRuleConfig_Action_OrderValues(rName, key, certificate, 10, 20),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBListenerRuleExists(resourceName, &rule),
+					resource.TestCheckResourceAttr(resourceName, "action_order_repair_pending", "false"),
+					resource.TestCheckResourceAttr(resourceName, "action.0.order", "10"),
+					resource.TestCheckResourceAttr(resourceName, "action.1.order", "20"),
+				),
+			},
+			{
+				Config:   testAccAWSLBListenerRuleConfig_Action_OrderValues(rName, key, certificate, 10, 20),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func TestAccAWSLBListenerRule_Action_Order_Recreates(t *testing.T) {
 	var rule elbv2.Rule
 	rName := acctest.RandomWithPrefix("tf-acc-test")
 	resourceName := "aws_lb_listener_rule.test"
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
 
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProvidersWithTLS,
+		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccAWSLBListenerRuleConfig_Action_Order(rName),
+				Config: testAccAWSLBListenerRuleConfig_Action_Order(rName, key, certificate),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					testAccCheckAWSLBListenerRuleExists(resourceName, &rule),
 					resource.TestCheckResourceAttr(resourceName, "action.#", "2"),
@@ -486,6 +1577,28 @@ func TestAccAWSLBListenerRule_Action_Order_Recreates(t *testing.T) {
 	})
 }
 
+func TestAccAWSLBListenerRule_Action_TerminalValidation(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAWSLBListenerRuleConfig_Action_TwoTerminal(),
+				ExpectError: regexp.MustCompile(`exactly one forward, redirect or fixed-response action, found 2`),
+			},
+			{
+				Config:      testAccAWSLBListenerRuleConfig_Action_TerminalNotLast(),
+				ExpectError: regexp.MustCompile(`action.1 \(authenticate-cognito\) is evaluated after the terminal forward action at action.0`),
+			},
+			{
+				Config:      testAccAWSLBListenerRuleConfig_Action_TooManyAuthenticate(),
+				ExpectError: regexp.MustCompile(`at most 2 authenticate actions, found 3`),
+			},
+		},
+	})
+}
+
 func TestAccAWSLBListenerRule_conditionNoField(t *testing.T) {
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -835,6 +1948,41 @@ func TestAccAWSLBListenerRule_conditionSourceIp(t *testing.T) {
 	})
 }
 
+func TestAccAWSLBListenerRule_diagnostics(t *testing.T) {
+	var conf elbv2.Rule
+	lbName := fmt.Sprintf("testrule-diag-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBListenerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBListenerRuleConfig_conditionMultiple(lbName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBListenerRuleExists("aws_lb_listener_rule.static", &conf),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "diagnostics", "false"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "matched_request_sample.#", "0"),
+				),
+			},
+			{
+				Config: testAccAWSLBListenerRuleConfig_diagnostics(lbName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBListenerRuleExists("aws_lb_listener_rule.static", &conf),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "diagnostics", "true"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "matched_request_sample.#", "1"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "matched_request_sample.0.method", "GET"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "matched_request_sample.0.host", "example.com"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "matched_request_sample.0.path", "/public/x"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "matched_request_sample.0.headers.%", "1"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "matched_request_sample.0.headers.X-Forwarded-For", "192.168.1.x"),
+					resource.TestCheckResourceAttr("aws_lb_listener_rule.static", "matched_request_sample.0.source_ip", "192.168.0.0"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccAWSLBListenerRule_conditionMultiple(t *testing.T) {
 	var conf elbv2.Rule
 	lbName := fmt.Sprintf("testrule-condMulti-%s", acctest.RandStringFromCharSet(13, acctest.CharSetAlphaNum))
@@ -951,6 +2099,26 @@ func testAccCheckAWSLbListenerRuleRecreated(t *testing.T,
 	}
 }
 
+func testAccCheckAWSLBListenerRuleFingerprintTag(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).elbv2conn
+		tags, err := describeLbListenerRuleFingerprintTags(conn, []*elbv2.Rule{{RuleArn: aws.String(rs.Primary.ID)}})
+		if err != nil {
+			return err
+		}
+
+		if tags[rs.Primary.ID] != rs.Primary.Attributes["condition_fingerprint"] {
+			return fmt.Errorf("expected fingerprint tag %q, got %q", rs.Primary.Attributes["condition_fingerprint"], tags[rs.Primary.ID])
+		}
+		return nil
+	}
+}
+
 func testAccCheckAWSLBListenerRuleExists(n string, res *elbv2.Rule) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -1002,7 +2170,7 @@ func testAccCheckAWSLBListenerRuleDestroy(s *terraform.State) error {
 		}
 
 		// Verify the error
-		if isAWSErr(err, elbv2.ErrCodeRuleNotFoundException, "") {
+		if isAWSErrCode(err, elbv2.ErrCodeRuleNotFoundException) {
 			return nil
 		} else {
 			return fmt.Errorf("Unexpected error checking LB Listener Rule destroyed: %s", err)
@@ -1238,6 +2406,179 @@ resource "aws_security_group" "alb_test" {
 `, lbName, targetGroupName)
 }
 
+// testAccAWSLBListenerRuleConfig_listenerArnOnly creates the rule with the ARN
+// of the listener looked up by the load balancer and port, as when the
+// listener is managed outside of the configuration.
+func testAccAWSLBListenerRuleConfig_listenerArnOnly(lbName, targetGroupName string) string {
+	return fmt.Sprintf(`
+resource "aws_lb_listener_rule" "static" {
+  listener_arn = "${data.aws_lb_listener.front_end.arn}"
+  priority     = 100
+
+  action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.test.arn}"
+  }
+
+  condition {
+    field  = "path-pattern"
+    values = ["/static/*"]
+  }
+}
+
+data "aws_lb_listener" "front_end" {
+  load_balancer_arn = "${aws_lb.alb_test.arn}"
+  port              = 80
+
+  depends_on = ["aws_lb_listener.front_end"]
+}
+
+resource "aws_lb_listener" "front_end" {
+  load_balancer_arn = "${aws_lb.alb_test.id}"
+  protocol          = "HTTP"
+  port              = "80"
+
+  default_action {
+    target_group_arn = "${aws_lb_target_group.test.id}"
+    type             = "forward"
+  }
+}
+
+resource "aws_lb" "alb_test" {
+  name            = "%s"
+  internal        = true
+  security_groups = ["${aws_security_group.alb_test.id}"]
+  subnets         = ["${aws_subnet.alb_test.*.id[0]}", "${aws_subnet.alb_test.*.id[1]}"]
+
+  idle_timeout               = 30
+  enable_deletion_protection = false
+
+  tags = {
+    Name = "TestAccAWSALB_listenerArnOnly"
+  }
+}
+
+resource "aws_lb_target_group" "test" {
+  name     = "%s"
+  port     = 8080
+  protocol = "HTTP"
+  vpc_id   = "${aws_vpc.alb_test.id}"
+}
+
+variable "subnets" {
+  default = ["10.0.1.0/24", "10.0.2.0/24"]
+  type    = "list"
+}
+
+data "aws_availability_zones" "available" {}
+
+resource "aws_vpc" "alb_test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-listener-rule-listener-arn-only"
+  }
+}
+
+resource "aws_subnet" "alb_test" {
+  count                   = 2
+  vpc_id                  = "${aws_vpc.alb_test.id}"
+  cidr_block              = "${element(var.subnets, count.index)}"
+  map_public_ip_on_launch = true
+  availability_zone       = "${element(data.aws_availability_zones.available.names, count.index)}"
+
+  tags = {
+    Name = "tf-acc-lb-listener-rule-listener-arn-only-${count.index}"
+  }
+}
+
+resource "aws_security_group" "alb_test" {
+  name        = "allow_all_alb_test"
+  description = "Used for ALB Testing"
+  vpc_id      = "${aws_vpc.alb_test.id}"
+
+  ingress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = {
+    Name = "TestAccAWSALB_listenerArnOnly"
+  }
+}
+`, lbName, targetGroupName)
+}
+
+// testAccAWSLBListenerRuleConfig_listenerArnNotFound creates the rule on a
+// listener ARN of an existing load balancer that has no such listener.
+func testAccAWSLBListenerRuleConfig_listenerArnNotFound(lbName string) string {
+	return fmt.Sprintf(`
+resource "aws_lb_listener_rule" "static" {
+  listener_arn = "${replace(aws_lb.alb_test.arn, ":loadbalancer/", ":listener/")}/0123456789abcdef"
+  priority     = 100
+
+  action {
+    type = "fixed-response"
+
+    fixed_response {
+      content_type = "text/plain"
+      status_code  = "404"
+    }
+  }
+
+  condition {
+    field  = "path-pattern"
+    values = ["/static/*"]
+  }
+}
+
+resource "aws_lb" "alb_test" {
+  name     = "%s"
+  internal = true
+  subnets  = ["${aws_subnet.alb_test.*.id[0]}", "${aws_subnet.alb_test.*.id[1]}"]
+
+  tags = {
+    Name = "TestAccAWSALB_listenerArnNotFound"
+  }
+}
+
+variable "subnets" {
+  default = ["10.0.1.0/24", "10.0.2.0/24"]
+  type    = "list"
+}
+
+data "aws_availability_zones" "available" {}
+
+resource "aws_vpc" "alb_test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-listener-rule-listener-arn-not-found"
+  }
+}
+
+resource "aws_subnet" "alb_test" {
+  count             = 2
+  vpc_id            = "${aws_vpc.alb_test.id}"
+  cidr_block        = "${element(var.subnets, count.index)}"
+  availability_zone = "${element(data.aws_availability_zones.available.names, count.index)}"
+
+  tags = {
+    Name = "tf-acc-lb-listener-rule-listener-arn-not-found-${count.index}"
+  }
+}
+`, lbName)
+}
+
 func testAccAWSLBListenerRuleConfigBackwardsCompatibility(lbName, targetGroupName string) string {
 	return fmt.Sprintf(`
 resource "aws_alb_listener_rule" "static" {
@@ -1770,32 +3111,149 @@ resource "aws_subnet" "alb_test" {
   tags = {
     Name = "tf-acc-lb-listener-rule-change-rule-arn-${count.index}"
   }
-}
+}
+
+resource "aws_security_group" "alb_test" {
+  name        = "allow_all_alb_test"
+  description = "Used for ALB Testing"
+  vpc_id      = "${aws_vpc.alb_test.id}"
+
+  ingress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = {
+    Name = "TestAccAWSALB_basic"
+  }
+}
+`, lbName, targetGroupName)
+}
+
+func testAccAWSLBListenerRuleConfig_Action_TwoTerminal() string {
+	return `
+resource "aws_lb_listener_rule" "test" {
+  listener_arn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/test/8e4497da625e2d8a/9ab28ade35828f96"
+
+  action {
+    type             = "forward"
+    target_group_arn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/test/8e4497da625e2d8a"
+  }
+
+  action {
+    type = "redirect"
+
+    redirect {
+      status_code = "HTTP_301"
+    }
+  }
+
+  condition {
+    field = "path-pattern"
+
+    path_pattern {
+      values = ["/static/*"]
+    }
+  }
+}
+`
+}
+
+func testAccAWSLBListenerRuleConfig_Action_TerminalNotLast() string {
+	return `
+resource "aws_lb_listener_rule" "test" {
+  listener_arn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/test/8e4497da625e2d8a/9ab28ade35828f96"
+
+  action {
+    type             = "forward"
+    target_group_arn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/test/8e4497da625e2d8a"
+  }
+
+  action {
+    type = "authenticate-cognito"
+
+    authenticate_cognito {
+      user_pool_arn       = "arn:aws:cognito-idp:us-west-2:123456789012:userpool/us-west-2_abcdefghi"
+      user_pool_client_id = "client"
+      user_pool_domain    = "domain"
+    }
+  }
+
+  condition {
+    field = "path-pattern"
+
+    path_pattern {
+      values = ["/static/*"]
+    }
+  }
+}
+`
+}
+
+func testAccAWSLBListenerRuleConfig_Action_TooManyAuthenticate() string {
+	return `
+resource "aws_lb_listener_rule" "test" {
+  listener_arn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/test/8e4497da625e2d8a/9ab28ade35828f96"
+
+  action {
+    type = "authenticate-cognito"
+
+    authenticate_cognito {
+      user_pool_arn       = "arn:aws:cognito-idp:us-west-2:123456789012:userpool/us-west-2_abcdefghi"
+      user_pool_client_id = "client"
+      user_pool_domain    = "domain"
+    }
+  }
+
+  action {
+    type = "authenticate-oidc"
+
+    authenticate_oidc {
+      authorization_endpoint = "https://example.com/authorization_endpoint"
+      client_id              = "s6BhdRkqt3"
+      client_secret          = "7Fjfp0ZBr1KtDRbnfVdmIw"
+      issuer                 = "https://example.com"
+      token_endpoint         = "https://example.com/token_endpoint"
+      user_info_endpoint     = "https://example.com/user_info_endpoint"
+    }
+  }
 
-resource "aws_security_group" "alb_test" {
-  name        = "allow_all_alb_test"
-  description = "Used for ALB Testing"
-  vpc_id      = "${aws_vpc.alb_test.id}"
+  action {
+    type = "authenticate-oidc"
 
-  ingress {
-    from_port   = 0
-    to_port     = 0
-    protocol    = "-1"
-    cidr_blocks = ["0.0.0.0/0"]
+    authenticate_oidc {
+      authorization_endpoint = "https://example.org/authorization_endpoint"
+      client_id              = "s6BhdRkqt3"
+      client_secret          = "7Fjfp0ZBr1KtDRbnfVdmIw"
+      issuer                 = "https://example.org"
+      token_endpoint         = "https://example.org/token_endpoint"
+      user_info_endpoint     = "https://example.org/user_info_endpoint"
+    }
   }
 
-  egress {
-    from_port   = 0
-    to_port     = 0
-    protocol    = "-1"
-    cidr_blocks = ["0.0.0.0/0"]
+  action {
+    type             = "forward"
+    target_group_arn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/test/8e4497da625e2d8a"
   }
 
-  tags = {
-    Name = "TestAccAWSALB_basic"
+  condition {
+    field = "path-pattern"
+
+    path_pattern {
+      values = ["/static/*"]
+    }
   }
 }
-`, lbName, targetGroupName)
+`
 }
 
 func testAccAWSLBListenerRuleConfig_priorityBase(lbName, targetGroupName string) string {
@@ -2045,7 +3503,127 @@ resource "aws_lb_listener_rule" "priority50000_in_use" {
 `)
 }
 
-func testAccAWSLBListenerRuleConfig_cognito(lbName string, targetGroupName string, certificateName string, cognitoPrefix string) string {
+// testAccAWSLBListenerRuleConfig_adoptMatching adds a rule at the priority of
+// the rule of testAccAWSLBListenerRuleConfig_priority50000, which is adopted
+// when the path pattern is the same.
+func testAccAWSLBListenerRuleConfig_adoptMatching(lbName, targetGroupName, pathPattern string) string {
+	return testAccAWSLBListenerRuleConfig_priority50000(lbName, targetGroupName) + fmt.Sprintf(`
+resource "aws_lb_listener_rule" "adopted" {
+  listener_arn   = "${aws_lb_listener.front_end.arn}"
+  priority       = 50000
+  adopt_matching = true
+
+  action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.test.arn}"
+  }
+
+  condition {
+    field  = "path-pattern"
+    values = [%q]
+  }
+
+  depends_on = ["aws_lb_listener_rule.priority50000"]
+}
+`, pathPattern)
+}
+
+// testAccAWSLBListenerRuleConfig_targetGroupName adds a rule forwarding to the
+// target group of testAccAWSLBListenerRuleConfig_basic by name.
+func testAccAWSLBListenerRuleConfig_targetGroupName(lbName, targetGroupName string) string {
+	return testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName) + `
+resource "aws_lb_listener_rule" "by_name" {
+  listener_arn = "${aws_lb_listener.front_end.arn}"
+  priority     = 200
+
+  action {
+    type              = "forward"
+    target_group_name = "${aws_lb_target_group.test.name}"
+  }
+
+  condition {
+    field  = "path-pattern"
+    values = ["/by-name/*"]
+  }
+}
+`
+}
+
+// testAccAWSLBListenerRuleConfig_targetGroupNameAndArn sets both the name and
+// the ARN of the target group on the rule of
+// testAccAWSLBListenerRuleConfig_targetGroupName.
+func testAccAWSLBListenerRuleConfig_targetGroupNameAndArn(lbName, targetGroupName string) string {
+	return testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName) + `
+resource "aws_lb_listener_rule" "by_name" {
+  listener_arn = "${aws_lb_listener.front_end.arn}"
+  priority     = 200
+
+  action {
+    type              = "forward"
+    target_group_arn  = "${aws_lb_target_group.test.arn}"
+    target_group_name = "${aws_lb_target_group.test.name}"
+  }
+
+  condition {
+    field  = "path-pattern"
+    values = ["/by-name/*"]
+  }
+}
+`
+}
+
+// testAccAWSLBListenerRuleConfig_waitForTargetHealth adds a rule that waits
+// for the target group of testAccAWSLBListenerRuleConfig_basic, which has no
+// targets, to become healthy.
+func testAccAWSLBListenerRuleConfig_waitForTargetHealth(lbName, targetGroupName string) string {
+	return testAccAWSLBListenerRuleConfig_basic(lbName, targetGroupName) + `
+resource "aws_lb_listener_rule" "wait" {
+  listener_arn = "${aws_lb_listener.front_end.arn}"
+  priority     = 200
+
+  action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.test.arn}"
+  }
+
+  condition {
+    field  = "path-pattern"
+    values = ["/wait/*"]
+  }
+
+  wait_for_target_health {
+    timeout = "30s"
+  }
+}
+`
+}
+
+// testAccAWSLBListenerRuleConfig_readOnly tracks the rule of
+// testAccAWSLBListenerRuleConfig_priority50000 with a read-only rule, which
+// only matches it when the path pattern is the same.
+func testAccAWSLBListenerRuleConfig_readOnly(lbName, targetGroupName, pathPattern string) string {
+	return testAccAWSLBListenerRuleConfig_priority50000(lbName, targetGroupName) + fmt.Sprintf(`
+resource "aws_lb_listener_rule" "read_only" {
+  listener_arn = "${aws_lb_listener.front_end.arn}"
+  priority     = 50000
+  read_only    = true
+
+  action {
+    type             = "forward"
+    target_group_arn = "${aws_lb_target_group.test.arn}"
+  }
+
+  condition {
+    field  = "path-pattern"
+    values = [%q]
+  }
+
+  depends_on = ["aws_lb_listener_rule.priority50000"]
+}
+`, pathPattern)
+}
+
+func testAccAWSLBListenerRuleConfig_cognito(lbName string, targetGroupName string, certificateName string, cognitoPrefix string, key string, certificate string) string {
 	return fmt.Sprintf(`
 resource "aws_lb_listener_rule" "cognito" {
   listener_arn = "${aws_lb_listener.front_end.arn}"
@@ -2078,30 +3656,8 @@ resource "aws_lb_listener_rule" "cognito" {
 
 resource "aws_iam_server_certificate" "test" {
   name             = "terraform-test-cert-%s"
-  certificate_body = "${tls_self_signed_cert.test.cert_pem}"
-  private_key      = "${tls_private_key.test.private_key_pem}"
-}
-
-resource "tls_private_key" "test" {
-  algorithm = "RSA"
-}
-
-resource "tls_self_signed_cert" "test" {
-  key_algorithm   = "RSA"
-  private_key_pem = "${tls_private_key.test.private_key_pem}"
-
-  subject {
-    common_name  = "example.com"
-    organization = "ACME Examples, Inc"
-  }
-
-  validity_period_hours = 12
-
-  allowed_uses = [
-    "key_encipherment",
-    "digital_signature",
-    "server_auth",
-  ]
+  certificate_body = "%s"
+  private_key      = "%s"
 }
 
 resource "aws_lb_listener" "front_end" {
@@ -2220,10 +3776,10 @@ resource "aws_cognito_user_pool_domain" "test" {
   domain       = "%s-pool-domain"
   user_pool_id = "${aws_cognito_user_pool.test.id}"
 }
-`, lbName, targetGroupName, certificateName, cognitoPrefix, cognitoPrefix, cognitoPrefix)
+`, lbName, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key), targetGroupName, certificateName, cognitoPrefix, cognitoPrefix, cognitoPrefix)
 }
 
-func testAccAWSLBListenerRuleConfig_oidc(lbName string, targetGroupName string, certificateName string) string {
+func testAccAWSLBListenerRuleConfig_oidc(lbName string, targetGroupName string, certificateName string, key string, certificate string) string {
 	return fmt.Sprintf(`
 resource "aws_lb_listener_rule" "oidc" {
   listener_arn = "${aws_lb_listener.front_end.arn}"
@@ -2259,30 +3815,8 @@ resource "aws_lb_listener_rule" "oidc" {
 
 resource "aws_iam_server_certificate" "test" {
   name             = "terraform-test-cert-%s"
-  certificate_body = "${tls_self_signed_cert.test.cert_pem}"
-  private_key      = "${tls_private_key.test.private_key_pem}"
-}
-
-resource "tls_private_key" "test" {
-  algorithm = "RSA"
-}
-
-resource "tls_self_signed_cert" "test" {
-  key_algorithm   = "RSA"
-  private_key_pem = "${tls_private_key.test.private_key_pem}"
-
-  subject {
-    common_name  = "example.com"
-    organization = "ACME Examples, Inc"
-  }
-
-  validity_period_hours = 12
-
-  allowed_uses = [
-    "key_encipherment",
-    "digital_signature",
-    "server_auth",
-  ]
+  certificate_body = "%s"
+  private_key      = "%s"
 }
 
 resource "aws_lb_listener" "front_end" {
@@ -2380,13 +3914,17 @@ resource "aws_security_group" "alb_test" {
     Name = "TestAccAWSALB_cognito"
   }
 }
-`, lbName, targetGroupName, certificateName)
+`, lbName, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key), targetGroupName, certificateName)
+}
+
+func testAccAWSLBListenerRuleConfig_Action_Order(rName, key, certificate string) string {
+	return testAccAWSLBListenerRuleConfig_Action_OrderValues(rName, key, certificate, 1, 2)
 }
 
-func testAccAWSLBListenerRuleConfig_Action_Order(rName string) string {
+func testAccAWSLBListenerRuleConfig_Action_OrderValues(rName, key, certificate string, firstOrder, secondOrder int) string {
 	return fmt.Sprintf(`
 variable "rName" {
-  default = %q
+  default = %[1]q
 }
 
 data "aws_availability_zones" "available" {}
@@ -2395,7 +3933,7 @@ resource "aws_lb_listener_rule" "test" {
   listener_arn = "${aws_lb_listener.test.arn}"
 
   action {
-    order = 1
+    order = %[4]d
     type  = "authenticate-oidc"
 
     authenticate_oidc {
@@ -2413,7 +3951,7 @@ resource "aws_lb_listener_rule" "test" {
   }
 
   action {
-    order            = 2
+    order            = %[5]d
     type             = "forward"
     target_group_arn = "${aws_lb_target_group.test.arn}"
   }
@@ -2425,30 +3963,9 @@ resource "aws_lb_listener_rule" "test" {
 }
 
 resource "aws_iam_server_certificate" "test" {
-  certificate_body = "${tls_self_signed_cert.test.cert_pem}"
+  certificate_body = "%[2]s"
   name             = "${var.rName}"
-  private_key      = "${tls_private_key.test.private_key_pem}"
-}
-
-resource "tls_private_key" "test" {
-  algorithm = "RSA"
-}
-
-resource "tls_self_signed_cert" "test" {
-  key_algorithm         = "RSA"
-  private_key_pem       = "${tls_private_key.test.private_key_pem}"
-  validity_period_hours = 12
-
-  subject {
-    common_name  = "example.com"
-    organization = "ACME Examples, Inc"
-  }
-
-  allowed_uses = [
-    "key_encipherment",
-    "digital_signature",
-    "server_auth",
-  ]
+  private_key      = "%[3]s"
 }
 
 resource "aws_lb_listener" "test" {
@@ -2532,7 +4049,7 @@ resource "aws_security_group" "test" {
     Name = "${var.rName}"
   }
 }
-`, rName)
+`, rName, tlsPemEscapeNewlines(certificate), tlsPemEscapeNewlines(key), firstOrder, secondOrder)
 }
 
 func testAccAWSLBListenerRuleConfig_conditionNoField() string {
@@ -3573,3 +5090,138 @@ resource "aws_security_group" "alb_test" {
   }
 }`, lbName)
 }
+
+func testAccAWSLBListenerRuleConfig_diagnostics(lbName string) string {
+	return fmt.Sprintf(`resource "aws_lb_listener_rule" "static" {
+  listener_arn = "${aws_lb_listener.front_end.arn}"
+  priority = 100
+  diagnostics = true
+
+  action {
+    type = "fixed-response"
+    fixed_response {
+      content_type = "text/plain"
+      message_body = "Static"
+      status_code = 200
+    }
+  }
+
+  condition {
+    field = "host-header"
+    host_header {
+      values = ["example.com"]
+    }
+  }
+
+  condition {
+    field = "http-header"
+    http_header {
+      http_header_name = "X-Forwarded-For"
+      values = ["192.168.1.*"]
+    }
+  }
+
+  condition {
+    field = "http-request-method"
+    http_request_method {
+      values = ["GET"]
+    }
+  }
+
+  condition {
+    field = "path-pattern"
+    path_pattern {
+      values = ["/public/*"]
+    }
+  }
+
+  condition {
+    field = "source-ip"
+    source_ip {
+      values = [
+        "192.168.0.0/16",
+      ]
+    }
+  }
+}
+
+resource "aws_lb_listener" "front_end" {
+   load_balancer_arn = "${aws_lb.alb_test.id}"
+   protocol = "HTTP"
+   port = "80"
+
+   default_action {
+     type = "fixed-response"
+     fixed_response {
+       content_type = "text/plain"
+       message_body = "Not Found"
+       status_code = 404
+     }
+   }
+}
+
+resource "aws_lb" "alb_test" {
+  name            = "%s"
+  internal        = true
+  security_groups = ["${aws_security_group.alb_test.id}"]
+  subnets         = ["${aws_subnet.alb_test.*.id[0]}", "${aws_subnet.alb_test.*.id[1]}"]
+
+  idle_timeout = 30
+  enable_deletion_protection = false
+
+  tags = {
+    Name = "TestAccAWSALB_diagnostics"
+  }
+}
+
+variable "subnets" {
+  default = ["10.0.1.0/24", "10.0.2.0/24"]
+  type    = "list"
+}
+
+data "aws_availability_zones" "available" {}
+
+resource "aws_vpc" "alb_test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "TestAccAWSALB_diagnostics"
+  }
+}
+
+resource "aws_subnet" "alb_test" {
+  count                   = 2
+  vpc_id                  = "${aws_vpc.alb_test.id}"
+  cidr_block              = "${element(var.subnets, count.index)}"
+  map_public_ip_on_launch = true
+  availability_zone       = "${element(data.aws_availability_zones.available.names, count.index)}"
+
+  tags = {
+    Name = "TestAccAWSALB_diagnostics-${count.index}"
+  }
+}
+
+resource "aws_security_group" "alb_test" {
+  name        = "allow_all_alb_test"
+  description = "Used for ALB Testing"
+  vpc_id      = "${aws_vpc.alb_test.id}"
+
+  ingress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = {
+    Name = "TestAccAWSALB_diagnostics"
+  }
+}`, lbName)
+}
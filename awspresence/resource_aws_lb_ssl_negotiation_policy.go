@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -123,7 +122,7 @@ func resourceAwsLBSSLNegotiationPolicyRead(d *schema.ResourceData, meta interfac
 
 	getResp, err := elbconn.DescribeLoadBalancerPolicies(request)
 	if err != nil {
-		if ec2err, ok := err.(awserr.Error); ok && ec2err.Code() == "PolicyNotFound" {
+		if isAWSErrCode(err, "PolicyNotFound") {
 			// The policy is gone.
 			d.SetId("")
 			return nil
@@ -166,6 +165,10 @@ func resourceAwsLBSSLNegotiationPolicyDelete(d *schema.ResourceData, meta interf
 	}
 
 	if _, err := elbconn.SetLoadBalancerPoliciesOfListener(setLoadBalancerOpts); err != nil {
+		if isLbNotFoundErr(err) {
+			log.Printf("[WARN] Listener of SSL negotiation policy %s already deleted", d.Id())
+			return nil
+		}
 		return fmt.Errorf("Error removing SSLNegotiationPolicy: %s", err)
 	}
 
@@ -174,7 +177,7 @@ func resourceAwsLBSSLNegotiationPolicyDelete(d *schema.ResourceData, meta interf
 		PolicyName:       aws.String(policyName),
 	}
 
-	if _, err := elbconn.DeleteLoadBalancerPolicy(request); err != nil {
+	if _, err := elbconn.DeleteLoadBalancerPolicy(request); err != nil && !isLbNotFoundErr(err) {
 		return fmt.Errorf("Error deleting SSL negotiation policy %s: %s", d.Id(), err)
 	}
 	return nil
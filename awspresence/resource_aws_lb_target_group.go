@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
@@ -45,14 +47,14 @@ func resourceAwsLbTargetGroup() *schema.Resource {
 				Computed:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"name_prefix"},
-				ValidateFunc:  validateLbTargetGroupName,
+				ValidateFunc:  validate.LbTargetGroupName,
 			},
 			"name_prefix": {
 				Type:          schema.TypeString,
 				Optional:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"name"},
-				ValidateFunc:  validateLbTargetGroupNamePrefix,
+				ValidateFunc:  validate.LbTargetGroupNamePrefix,
 			},
 
 			"port": {
@@ -93,7 +95,7 @@ func resourceAwsLbTargetGroup() *schema.Resource {
 				Type:         schema.TypeInt,
 				Optional:     true,
 				Default:      0,
-				ValidateFunc: validateSlowStart,
+				ValidateFunc: validate.LbTargetGroupSlowStart,
 			},
 
 			"proxy_protocol_v2": {
@@ -172,14 +174,14 @@ func resourceAwsLbTargetGroup() *schema.Resource {
 							Type:         schema.TypeString,
 							Optional:     true,
 							Computed:     true,
-							ValidateFunc: validateAwsLbTargetGroupHealthCheckPath,
+							ValidateFunc: validate.LbTargetGroupHealthCheckPath,
 						},
 
 						"port": {
 							Type:             schema.TypeString,
 							Optional:         true,
 							Default:          "traffic-port",
-							ValidateFunc:     validateAwsLbTargetGroupHealthCheckPort,
+							ValidateFunc:     validate.LbTargetGroupHealthCheckPort,
 							DiffSuppressFunc: suppressIfTargetType(elbv2.TargetTypeEnumLambda),
 						},
 
@@ -228,7 +230,34 @@ func resourceAwsLbTargetGroup() *schema.Resource {
 				},
 			},
 
+			"security_group_ingress_requirements": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"port": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"traffic": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"health_check": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+
 			"tags": tagsSchema(),
+
+			"region": regionSchema(),
 		},
 	}
 }
@@ -240,7 +269,7 @@ func suppressIfTargetType(t string) schema.SchemaDiffSuppressFunc {
 }
 
 func resourceAwsLbTargetGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	var groupName string
 	if v, ok := d.GetOk("name"); ok {
@@ -320,13 +349,13 @@ func resourceAwsLbTargetGroupCreate(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceAwsLbTargetGroupRead(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	resp, err := elbconn.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
 		TargetGroupArns: []*string{aws.String(d.Id())},
 	})
 	if err != nil {
-		if isAWSErr(err, elbv2.ErrCodeTargetGroupNotFoundException, "") {
+		if isAWSErrCode(err, elbv2.ErrCodeTargetGroupNotFoundException) {
 			log.Printf("[DEBUG] DescribeTargetGroups - removing %s from state", d.Id())
 			d.SetId("")
 			return nil
@@ -342,49 +371,21 @@ func resourceAwsLbTargetGroupRead(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceAwsLbTargetGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	if err := setElbV2Tags(elbconn, d); err != nil {
 		return fmt.Errorf("Error Modifying Tags on LB Target Group: %s", err)
 	}
 
-	if d.HasChange("health_check") {
-		var params *elbv2.ModifyTargetGroupInput
-		healthChecks := d.Get("health_check").([]interface{})
-		if len(healthChecks) == 1 {
-			params = &elbv2.ModifyTargetGroupInput{
-				TargetGroupArn: aws.String(d.Id()),
-			}
-			healthCheck := healthChecks[0].(map[string]interface{})
-
-			params = &elbv2.ModifyTargetGroupInput{
-				TargetGroupArn:          aws.String(d.Id()),
-				HealthCheckEnabled:      aws.Bool(healthCheck["enabled"].(bool)),
-				HealthyThresholdCount:   aws.Int64(int64(healthCheck["healthy_threshold"].(int))),
-				UnhealthyThresholdCount: aws.Int64(int64(healthCheck["unhealthy_threshold"].(int))),
-			}
-
-			t := healthCheck["timeout"].(int)
-			if t != 0 {
-				params.HealthCheckTimeoutSeconds = aws.Int64(int64(t))
-			}
-
-			healthCheckProtocol := healthCheck["protocol"].(string)
-
-			if healthCheckProtocol != "TCP" && !d.IsNewResource() {
-				params.Matcher = &elbv2.Matcher{
-					HttpCode: aws.String(healthCheck["matcher"].(string)),
-				}
-				params.HealthCheckPath = aws.String(healthCheck["path"].(string))
-				params.HealthCheckIntervalSeconds = aws.Int64(int64(healthCheck["interval"].(int)))
-			}
-			if d.Get("target_type").(string) != elbv2.TargetTypeEnumLambda {
-				params.HealthCheckPort = aws.String(healthCheck["port"].(string))
-				params.HealthCheckProtocol = aws.String(healthCheckProtocol)
-			}
-		}
-
+	// New target groups are created with their full health check, so only
+	// existing ones need to be modified.
+	if d.HasChange("health_check") && !d.IsNewResource() {
+		o, n := d.GetChange("health_check")
+		params := expandLbTargetGroupHealthCheckChanges(o.([]interface{}), n.([]interface{}), d.Get("target_type").(string))
 		if params != nil {
+			params.TargetGroupArn = aws.String(d.Id())
+
+			log.Printf("[DEBUG] Modifying LB Target Group (%s) health check: %s", d.Id(), params)
 			_, err := elbconn.ModifyTargetGroup(params)
 			if err != nil {
 				return fmt.Errorf("Error modifying Target Group: %s", err)
@@ -470,62 +471,94 @@ func resourceAwsLbTargetGroupUpdate(d *schema.ResourceData, meta interface{}) er
 	return resourceAwsLbTargetGroupRead(d, meta)
 }
 
-func resourceAwsLbTargetGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+// expandLbTargetGroupHealthCheckChanges returns the ModifyTargetGroup input
+// for the health check settings that differ between the old and new
+// health_check blocks, or nil when none do. Only sending the changed settings
+// lets a single one, such as the protocol, be updated in place without
+// tripping over settings that cannot be modified for the target group.
+func expandLbTargetGroupHealthCheckChanges(o, n []interface{}, targetType string) *elbv2.ModifyTargetGroupInput {
+	if len(n) != 1 || n[0] == nil {
+		return nil
+	}
+	newHealthCheck := n[0].(map[string]interface{})
 
-	_, err := elbconn.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{
-		TargetGroupArn: aws.String(d.Id()),
-	})
-	if err != nil {
-		return fmt.Errorf("Error deleting Target Group: %s", err)
+	oldHealthCheck := map[string]interface{}{}
+	if len(o) == 1 && o[0] != nil {
+		oldHealthCheck = o[0].(map[string]interface{})
 	}
 
-	return nil
-}
+	changed := func(k string) bool {
+		return !reflect.DeepEqual(oldHealthCheck[k], newHealthCheck[k])
+	}
+
+	params := &elbv2.ModifyTargetGroupInput{}
+	modified := false
 
-func validateAwsLbTargetGroupHealthCheckPath(v interface{}, k string) (ws []string, errors []error) {
-	value := v.(string)
-	if len(value) > 1024 {
-		errors = append(errors, fmt.Errorf(
-			"%q cannot be longer than 1024 characters: %q", k, value))
+	if changed("enabled") {
+		params.HealthCheckEnabled = aws.Bool(newHealthCheck["enabled"].(bool))
+		modified = true
 	}
-	if len(value) > 0 && !strings.HasPrefix(value, "/") {
-		errors = append(errors, fmt.Errorf(
-			"%q must begin with a '/' character: %q", k, value))
+	if changed("healthy_threshold") {
+		params.HealthyThresholdCount = aws.Int64(int64(newHealthCheck["healthy_threshold"].(int)))
+		modified = true
+	}
+	if changed("unhealthy_threshold") {
+		params.UnhealthyThresholdCount = aws.Int64(int64(newHealthCheck["unhealthy_threshold"].(int)))
+		modified = true
+	}
+	if changed("interval") {
+		params.HealthCheckIntervalSeconds = aws.Int64(int64(newHealthCheck["interval"].(int)))
+		modified = true
+	}
+	if t := newHealthCheck["timeout"].(int); t != 0 && changed("timeout") {
+		params.HealthCheckTimeoutSeconds = aws.Int64(int64(t))
+		modified = true
 	}
-	return
-}
 
-func validateSlowStart(v interface{}, k string) (ws []string, errors []error) {
-	value := v.(int)
+	healthCheckProtocol := strings.ToUpper(newHealthCheck["protocol"].(string))
 
-	// Check if the value is between 30-900 or 0 (seconds).
-	if value != 0 && !(value >= 30 && value <= 900) {
-		errors = append(errors, fmt.Errorf(
-			"%q contains an invalid Slow Start Duration \"%d\". "+
-				"Valid intervals are 30-900 or 0 to disable.",
-			k, value))
+	if healthCheckProtocol != elbv2.ProtocolEnumTcp {
+		if p := newHealthCheck["path"].(string); p != "" && changed("path") {
+			params.HealthCheckPath = aws.String(p)
+			modified = true
+		}
+		if m := newHealthCheck["matcher"].(string); m != "" && changed("matcher") {
+			params.Matcher = &elbv2.Matcher{
+				HttpCode: aws.String(m),
+			}
+			modified = true
+		}
 	}
-	return
-}
-
-func validateAwsLbTargetGroupHealthCheckPort(v interface{}, k string) (ws []string, errors []error) {
-	value := v.(string)
 
-	if value == "traffic-port" {
-		return
+	if targetType != elbv2.TargetTypeEnumLambda {
+		if changed("port") {
+			params.HealthCheckPort = aws.String(newHealthCheck["port"].(string))
+			modified = true
+		}
+		if !strings.EqualFold(fmt.Sprint(oldHealthCheck["protocol"]), healthCheckProtocol) {
+			params.HealthCheckProtocol = aws.String(healthCheckProtocol)
+			modified = true
+		}
 	}
 
-	port, err := strconv.Atoi(value)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("%q must be a valid port number (1-65536) or %q", k, "traffic-port"))
+	if !modified {
+		return nil
 	}
 
-	if port < 1 || port > 65536 {
-		errors = append(errors, fmt.Errorf("%q must be a valid port number (1-65536) or %q", k, "traffic-port"))
+	return params
+}
+
+func resourceAwsLbTargetGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	elbconn := resourceAWSClient(d, meta).elbv2conn
+
+	_, err := elbconn.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{
+		TargetGroupArn: aws.String(d.Id()),
+	})
+	if err != nil && !isLbNotFoundErr(err) {
+		return fmt.Errorf("Error deleting Target Group: %s", err)
 	}
 
-	return
+	return nil
 }
 
 func lbTargetGroupSuffixFromARN(arn *string) string {
@@ -542,11 +575,21 @@ func lbTargetGroupSuffixFromARN(arn *string) string {
 	return ""
 }
 
+// lbTargetGroupNameFromARN returns the name of the target group with arn.
+func lbTargetGroupNameFromARN(arn string) string {
+	parts := strings.Split(lbTargetGroupSuffixFromARN(&arn), "/")
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[1]
+}
+
 // flattenAwsLbTargetGroupResource takes a *elbv2.TargetGroup and populates all respective resource fields.
 func flattenAwsLbTargetGroupResource(d *schema.ResourceData, meta interface{}, targetGroup *elbv2.TargetGroup) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	d.Set("arn", targetGroup.TargetGroupArn)
+	d.Set("region", resourceAWSClient(d, meta).region)
 	d.Set("arn_suffix", lbTargetGroupSuffixFromARN(targetGroup.TargetGroupArn))
 	d.Set("name", targetGroup.TargetGroupName)
 	d.Set("target_type", targetGroup.TargetType)
@@ -576,6 +619,10 @@ func flattenAwsLbTargetGroupResource(d *schema.ResourceData, meta interface{}, t
 		return fmt.Errorf("error setting health_check: %s", err)
 	}
 
+	if err := d.Set("security_group_ingress_requirements", flattenLbTargetGroupSecurityGroupIngressRequirements(targetGroup)); err != nil {
+		return fmt.Errorf("error setting security_group_ingress_requirements: %s", err)
+	}
+
 	attrResp, err := elbconn.DescribeTargetGroupAttributes(&elbv2.DescribeTargetGroupAttributesInput{
 		TargetGroupArn: aws.String(d.Id()),
 	})
@@ -642,6 +689,68 @@ func flattenAwsLbTargetGroupResource(d *schema.ResourceData, meta interface{}, t
 	return nil
 }
 
+// flattenLbTargetGroupSecurityGroupIngressRequirements returns the ports and
+// IP protocols that the security groups of the targets must allow from the
+// load balancer: the traffic port and the health check port. Lambda target
+// groups have no requirements.
+func flattenLbTargetGroupSecurityGroupIngressRequirements(targetGroup *elbv2.TargetGroup) []interface{} {
+	requirements := []interface{}{}
+	if aws.StringValue(targetGroup.TargetType) == elbv2.TargetTypeEnumLambda || targetGroup.Port == nil {
+		return requirements
+	}
+
+	trafficPort := int(aws.Int64Value(targetGroup.Port))
+	add := func(port int, protocol string, traffic bool) {
+		for _, r := range requirements {
+			requirement := r.(map[string]interface{})
+			if requirement["port"] == port && requirement["protocol"] == protocol {
+				requirement["traffic"] = requirement["traffic"].(bool) || traffic
+				requirement["health_check"] = requirement["health_check"].(bool) || !traffic
+				return
+			}
+		}
+		requirements = append(requirements, map[string]interface{}{
+			"port":         port,
+			"protocol":     protocol,
+			"traffic":      traffic,
+			"health_check": !traffic,
+		})
+	}
+
+	for _, protocol := range lbTargetGroupIpProtocols(aws.StringValue(targetGroup.Protocol)) {
+		add(trafficPort, protocol, true)
+	}
+
+	if aws.BoolValue(targetGroup.HealthCheckEnabled) || targetGroup.HealthCheckEnabled == nil {
+		healthCheckPort := trafficPort
+		if v := aws.StringValue(targetGroup.HealthCheckPort); v != "" && v != "traffic-port" {
+			port, err := strconv.Atoi(v)
+			if err != nil {
+				log.Printf("[WARN] Unexpected health check port %q for Target Group %s", v, aws.StringValue(targetGroup.TargetGroupArn))
+				return requirements
+			}
+			healthCheckPort = port
+		}
+		// Health checks are always made over TCP, even for UDP target groups.
+		add(healthCheckPort, "tcp", false)
+	}
+
+	return requirements
+}
+
+// lbTargetGroupIpProtocols returns the IP protocols carrying the traffic of a
+// target group protocol, as used in security group rules.
+func lbTargetGroupIpProtocols(protocol string) []string {
+	switch strings.ToUpper(protocol) {
+	case "UDP":
+		return []string{"udp"}
+	case "TCP_UDP":
+		return []string{"tcp", "udp"}
+	default:
+		return []string{"tcp"}
+	}
+}
+
 func flattenAwsLbTargetGroupStickiness(d *schema.ResourceData, attributes []*elbv2.TargetGroupAttribute) error {
 	stickinessMap := map[string]interface{}{}
 	for _, attr := range attributes {
@@ -732,6 +841,12 @@ func resourceAwsLbTargetGroupCustomizeDiff(diff *schema.ResourceDiff, v interfac
 		return nil
 	}
 
+	if diff.HasChange("port") || diff.HasChange("protocol") || diff.HasChange("health_check.0.port") || diff.HasChange("health_check.0.protocol") {
+		if err := diff.SetNewComputed("security_group_ingress_requirements"); err != nil {
+			return err
+		}
+	}
+
 	if protocol == "TCP" {
 		if diff.HasChange("health_check.0.interval") {
 			old, new := diff.GetChange("health_check.0.interval")
@@ -8,12 +8,14 @@ import (
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func resourceAwsLbTargetGroupAttachment() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAwsLbAttachmentCreate,
 		Read:   resourceAwsLbAttachmentRead,
+		Update: resourceAwsLbAttachmentUpdate,
 		Delete: resourceAwsLbAttachmentDelete,
 
 		Schema: map[string]*schema.Schema{
@@ -40,12 +42,18 @@ func resourceAwsLbTargetGroupAttachment() *schema.Resource {
 				ForceNew: true,
 				Optional: true,
 			},
+
+			"external_registration": lbTargetGroupAttachmentExternalRegistrationSchema(),
+
+			"tags": tagsSchema(),
+
+			"region": regionSchema(),
 		},
 	}
 }
 
 func resourceAwsLbAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	target := &elbv2.TargetDescription{
 		Id: aws.String(d.Get("target_id").(string)),
@@ -74,11 +82,25 @@ func resourceAwsLbAttachmentCreate(d *schema.ResourceData, meta interface{}) err
 
 	d.SetId(resource.PrefixedUniqueId(fmt.Sprintf("%s-", d.Get("target_group_arn"))))
 
+	if err := setLbTargetGroupAttachmentTags(elbconn, d, lbTargetGroupAttachmentTagNamespace(d.Get("target_id").(string), d.Get("port").(int))); err != nil {
+		return fmt.Errorf("Error tagging target group attachment: %s", err)
+	}
+
 	return nil
 }
 
+func resourceAwsLbAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	elbconn := resourceAWSClient(d, meta).elbv2conn
+
+	if err := setLbTargetGroupAttachmentTags(elbconn, d, lbTargetGroupAttachmentTagNamespace(d.Get("target_id").(string), d.Get("port").(int))); err != nil {
+		return fmt.Errorf("Error updating target group attachment tags: %s", err)
+	}
+
+	return resourceAwsLbAttachmentRead(d, meta)
+}
+
 func resourceAwsLbAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	target := &elbv2.TargetDescription{
 		Id: aws.String(d.Get("target_id").(string)),
@@ -98,17 +120,35 @@ func resourceAwsLbAttachmentDelete(d *schema.ResourceData, meta interface{}) err
 	}
 
 	_, err := elbconn.DeregisterTargets(params)
-	if err != nil && !isAWSErr(err, elbv2.ErrCodeTargetGroupNotFoundException, "") {
+	if isLbNotFoundErr(err) {
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("Error deregistering Targets: %s", err)
 	}
 
+	if tags := d.Get("tags").(map[string]interface{}); len(tags) > 0 {
+		namespace := lbTargetGroupAttachmentTagNamespace(d.Get("target_id").(string), d.Get("port").(int))
+		var tagKeys []*string
+		for k := range tags {
+			tagKeys = append(tagKeys, aws.String(namespace+k))
+		}
+		_, err := elbconn.RemoveTags(&elbv2.RemoveTagsInput{
+			ResourceArns: []*string{aws.String(d.Get("target_group_arn").(string))},
+			TagKeys:      tagKeys,
+		})
+		if err != nil && !isLbNotFoundErr(err) {
+			return fmt.Errorf("Error removing target group attachment tags: %s", err)
+		}
+	}
+
 	return nil
 }
 
 // resourceAwsLbAttachmentRead requires all of the fields in order to describe the correct
 // target, so there is no work to do beyond ensuring that the target and group still exist.
 func resourceAwsLbAttachmentRead(d *schema.ResourceData, meta interface{}) error {
-	elbconn := meta.(*AWSClient).elbv2conn
+	elbconn := resourceAWSClient(d, meta).elbv2conn
 
 	target := &elbv2.TargetDescription{
 		Id: aws.String(d.Get("target_id").(string)),
@@ -127,12 +167,12 @@ func resourceAwsLbAttachmentRead(d *schema.ResourceData, meta interface{}) error
 		Targets:        []*elbv2.TargetDescription{target},
 	})
 	if err != nil {
-		if isAWSErr(err, elbv2.ErrCodeTargetGroupNotFoundException, "") {
+		if isAWSErrCode(err, elbv2.ErrCodeTargetGroupNotFoundException) {
 			log.Printf("[WARN] Target group does not exist, removing target attachment %s", d.Id())
 			d.SetId("")
 			return nil
 		}
-		if isAWSErr(err, elbv2.ErrCodeInvalidTargetException, "") {
+		if isAWSErrCode(err, elbv2.ErrCodeInvalidTargetException) {
 			log.Printf("[WARN] Target does not exist, removing target attachment %s", d.Id())
 			d.SetId("")
 			return nil
@@ -140,11 +180,84 @@ func resourceAwsLbAttachmentRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error reading Target Health: %s", err)
 	}
 
-	if len(resp.TargetHealthDescriptions) != 1 {
+	descriptions := resp.TargetHealthDescriptions
+	if d.Get("external_registration").(string) == lbTargetGroupAttachmentExternalRegistrationIgnore {
+		port, err := lbTargetGroupAttachmentTargetPort(elbconn, d)
+		if err != nil {
+			return err
+		}
+		descriptions = filterLbTargetHealthDescriptionsByPort(descriptions, port)
+	}
+
+	if len(descriptions) != 1 {
 		log.Printf("[WARN] Target does not exist, removing target attachment %s", d.Id())
 		d.SetId("")
 		return nil
 	}
 
+	tags, err := readLbTargetGroupAttachmentTags(elbconn, d.Get("target_group_arn").(string), lbTargetGroupAttachmentTagNamespace(d.Get("target_id").(string), d.Get("port").(int)))
+	if err != nil {
+		return fmt.Errorf("Error retrieving target group attachment tags: %s", err)
+	}
+	if err := d.Set("tags", tags); err != nil {
+		return fmt.Errorf("error setting tags: %s", err)
+	}
+
+	d.Set("region", resourceAWSClient(d, meta).region)
+
 	return nil
 }
+
+const (
+	lbTargetGroupAttachmentExternalRegistrationTrack  = "track"
+	lbTargetGroupAttachmentExternalRegistrationIgnore = "ignore"
+)
+
+// lbTargetGroupAttachmentExternalRegistrationSchema returns the schema of the
+// external_registration argument of the target group attachment resources.
+// With "ignore", registrations of the same target on other ports, such as the
+// dynamic ports ECS and Kubernetes controllers register instances on, are left
+// out when reading the attachment.
+func lbTargetGroupAttachmentExternalRegistrationSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  lbTargetGroupAttachmentExternalRegistrationTrack,
+		ValidateFunc: validation.StringInSlice([]string{
+			lbTargetGroupAttachmentExternalRegistrationTrack,
+			lbTargetGroupAttachmentExternalRegistrationIgnore,
+		}, false),
+	}
+}
+
+// lbTargetGroupAttachmentTargetPort returns the port the targets of an
+// attachment are registered on: its port argument, or the port of the target
+// group when it is not set.
+func lbTargetGroupAttachmentTargetPort(conn *elbv2.ELBV2, d *schema.ResourceData) (int64, error) {
+	if v, ok := d.GetOk("port"); ok {
+		return int64(v.(int)), nil
+	}
+
+	targetGroupArn := d.Get("target_group_arn").(string)
+	resp, err := conn.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: aws.StringSlice([]string{targetGroupArn}),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Error retrieving Target Group %s: %s", targetGroupArn, err)
+	}
+	if len(resp.TargetGroups) != 1 {
+		return 0, fmt.Errorf("Error retrieving Target Group %s: found %d target groups", targetGroupArn, len(resp.TargetGroups))
+	}
+
+	return aws.Int64Value(resp.TargetGroups[0].Port), nil
+}
+
+func filterLbTargetHealthDescriptionsByPort(descriptions []*elbv2.TargetHealthDescription, port int64) []*elbv2.TargetHealthDescription {
+	var filtered []*elbv2.TargetHealthDescription
+	for _, description := range descriptions {
+		if description.Target != nil && aws.Int64Value(description.Target.Port) == port {
+			filtered = append(filtered, description)
+		}
+	}
+	return filtered
+}
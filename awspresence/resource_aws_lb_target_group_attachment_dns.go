@@ -0,0 +1,317 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/cmoreira-daitan/terraform-provider-awspresence/awspresence/validate"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// lbTargetGroupAttachmentDnsLookupIP resolves the DNS name of an attachment.
+// It is a variable so that tests do not depend on real DNS records.
+var lbTargetGroupAttachmentDnsLookupIP = net.LookupIP
+
+func resourceAwsLbTargetGroupAttachmentDns() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsLbTargetGroupAttachmentDnsCreate,
+		Read:   resourceAwsLbTargetGroupAttachmentDnsRead,
+		Update: resourceAwsLbTargetGroupAttachmentDnsUpdate,
+		Delete: resourceAwsLbTargetGroupAttachmentDnsDelete,
+
+		CustomizeDiff: resourceAwsLbTargetGroupAttachmentDnsCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"target_group_arn": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+
+			"dns_name": {
+				Type:         schema.TypeString,
+				ForceNew:     true,
+				Required:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"port": {
+				Type:     schema.TypeInt,
+				ForceNew: true,
+				Optional: true,
+			},
+
+			"availability_zone": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Optional: true,
+			},
+
+			"external_registration": lbTargetGroupAttachmentExternalRegistrationSchema(),
+
+			"refresh_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validate.Duration(0),
+			},
+
+			"ip_addresses": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"resolved_ip_addresses": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"resolved_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"region": regionSchema(),
+		},
+	}
+}
+
+func resourceAwsLbTargetGroupAttachmentDnsCreate(d *schema.ResourceData, meta interface{}) error {
+	elbconn := resourceAWSClient(d, meta).elbv2conn
+	dnsName := d.Get("dns_name").(string)
+
+	ips, err := resolveLbTargetGroupAttachmentDns(dnsName)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Registering %s (%v) with Target Group %s", dnsName, ips, d.Get("target_group_arn").(string))
+	_, err = elbconn.RegisterTargets(&elbv2.RegisterTargetsInput{
+		TargetGroupArn: aws.String(d.Get("target_group_arn").(string)),
+		Targets:        expandLbTargetGroupAttachmentDnsTargets(d, ips),
+	})
+	if err != nil {
+		return fmt.Errorf("Error registering targets with target group: %s", err)
+	}
+
+	d.SetId(resource.PrefixedUniqueId(fmt.Sprintf("%s-", d.Get("target_group_arn"))))
+	d.Set("ip_addresses", ips)
+	d.Set("resolved_ip_addresses", ips)
+	d.Set("resolved_at", time.Now().UTC().Format(time.RFC3339))
+
+	return resourceAwsLbTargetGroupAttachmentDnsRead(d, meta)
+}
+
+// resourceAwsLbTargetGroupAttachmentDnsRead resolves the DNS name again once
+// refresh_interval has passed, and records which of the addresses registered
+// by the resource are still registered with the target group.
+func resourceAwsLbTargetGroupAttachmentDnsRead(d *schema.ResourceData, meta interface{}) error {
+	client := resourceAWSClient(d, meta)
+
+	if lbTargetGroupAttachmentDnsRefreshDue(d.Get("refresh_interval").(string), d.Get("resolved_at").(string), time.Now()) {
+		dnsName := d.Get("dns_name").(string)
+		ips, err := resolveLbTargetGroupAttachmentDns(dnsName)
+		if err != nil {
+			// Keep the last resolution rather than failing every plan while
+			// the name does not resolve.
+			log.Printf("[WARN] %s, keeping addresses resolved at %s", err, d.Get("resolved_at").(string))
+		} else {
+			d.Set("resolved_ip_addresses", ips)
+			d.Set("resolved_at", time.Now().UTC().Format(time.RFC3339))
+		}
+	}
+
+	resp, err := client.elbv2conn.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(d.Get("target_group_arn").(string)),
+	})
+	if isAWSErrCode(err, elbv2.ErrCodeTargetGroupNotFoundException) {
+		log.Printf("[WARN] Target group does not exist, removing target attachment %s", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading Target Health: %s", err)
+	}
+
+	descriptions := resp.TargetHealthDescriptions
+	if v, ok := d.GetOk("port"); ok {
+		descriptions = filterLbTargetHealthDescriptionsByPort(descriptions, int64(v.(int)))
+	} else if d.Get("external_registration").(string) == lbTargetGroupAttachmentExternalRegistrationIgnore {
+		port, err := lbTargetGroupAttachmentTargetPort(client.elbv2conn, d)
+		if err != nil {
+			return err
+		}
+		descriptions = filterLbTargetHealthDescriptionsByPort(descriptions, port)
+	}
+
+	managed := d.Get("ip_addresses").(*schema.Set)
+	registered := make([]string, 0, managed.Len())
+	for _, description := range descriptions {
+		id := aws.StringValue(description.Target.Id)
+		if !managed.Contains(id) {
+			continue
+		}
+		if description.TargetHealth != nil && aws.StringValue(description.TargetHealth.State) == elbv2.TargetHealthStateEnumDraining {
+			continue
+		}
+		registered = append(registered, id)
+	}
+
+	if len(registered) != managed.Len() {
+		log.Printf("[WARN] %d of %d targets of attachment %s no longer registered", managed.Len()-len(registered), managed.Len(), d.Id())
+	}
+	d.Set("ip_addresses", registered)
+	d.Set("region", client.region)
+
+	return nil
+}
+
+func resourceAwsLbTargetGroupAttachmentDnsUpdate(d *schema.ResourceData, meta interface{}) error {
+	elbconn := resourceAWSClient(d, meta).elbv2conn
+	targetGroupArn := d.Get("target_group_arn").(string)
+
+	if d.HasChange("ip_addresses") {
+		o, n := d.GetChange("ip_addresses")
+		os := o.(*schema.Set)
+		ns := n.(*schema.Set)
+
+		add := expandStringSet(ns.Difference(os))
+		if len(add) > 0 {
+			log.Printf("[INFO] Registering %v with Target Group %s", aws.StringValueSlice(add), targetGroupArn)
+			_, err := elbconn.RegisterTargets(&elbv2.RegisterTargetsInput{
+				TargetGroupArn: aws.String(targetGroupArn),
+				Targets:        expandLbTargetGroupAttachmentDnsTargets(d, aws.StringValueSlice(add)),
+			})
+			if err != nil {
+				return fmt.Errorf("Error registering targets with target group: %s", err)
+			}
+		}
+
+		remove := expandStringSet(os.Difference(ns))
+		if len(remove) > 0 {
+			log.Printf("[INFO] Deregistering %v from Target Group %s", aws.StringValueSlice(remove), targetGroupArn)
+			_, err := elbconn.DeregisterTargets(&elbv2.DeregisterTargetsInput{
+				TargetGroupArn: aws.String(targetGroupArn),
+				Targets:        expandLbTargetGroupAttachmentDnsTargets(d, aws.StringValueSlice(remove)),
+			})
+			if err != nil {
+				return fmt.Errorf("Error deregistering Targets: %s", err)
+			}
+		}
+	}
+
+	return resourceAwsLbTargetGroupAttachmentDnsRead(d, meta)
+}
+
+func resourceAwsLbTargetGroupAttachmentDnsDelete(d *schema.ResourceData, meta interface{}) error {
+	elbconn := resourceAWSClient(d, meta).elbv2conn
+
+	ips := expandStringSet(d.Get("ip_addresses").(*schema.Set))
+	if len(ips) == 0 {
+		return nil
+	}
+
+	_, err := elbconn.DeregisterTargets(&elbv2.DeregisterTargetsInput{
+		TargetGroupArn: aws.String(d.Get("target_group_arn").(string)),
+		Targets:        expandLbTargetGroupAttachmentDnsTargets(d, aws.StringValueSlice(ips)),
+	})
+	if err != nil && !isLbNotFoundErr(err) {
+		return fmt.Errorf("Error deregistering Targets: %s", err)
+	}
+
+	return nil
+}
+
+// resourceAwsLbTargetGroupAttachmentDnsCustomizeDiff plans the registration
+// of the addresses the DNS name last resolved to, when they differ from the
+// registered ones.
+func resourceAwsLbTargetGroupAttachmentDnsCustomizeDiff(diff *schema.ResourceDiff, v interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+
+	resolved := diff.Get("resolved_ip_addresses").(*schema.Set)
+	if resolved.Len() == 0 || resolved.Equal(diff.Get("ip_addresses").(*schema.Set)) {
+		return nil
+	}
+
+	return diff.SetNew("ip_addresses", resolved.List())
+}
+
+func expandLbTargetGroupAttachmentDnsTargets(d *schema.ResourceData, ips []string) []*elbv2.TargetDescription {
+	targets := make([]*elbv2.TargetDescription, 0, len(ips))
+	for _, ip := range ips {
+		target := &elbv2.TargetDescription{
+			Id: aws.String(ip),
+		}
+
+		if v, ok := d.GetOk("port"); ok {
+			target.Port = aws.Int64(int64(v.(int)))
+		}
+
+		if v, ok := d.GetOk("availability_zone"); ok {
+			target.AvailabilityZone = aws.String(v.(string))
+		}
+
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// resolveLbTargetGroupAttachmentDns returns the sorted IPv4 addresses dnsName
+// resolves to. Target groups of type ip only take IPv4 addresses.
+func resolveLbTargetGroupAttachmentDns(dnsName string) ([]string, error) {
+	addrs, err := lbTargetGroupAttachmentDnsLookupIP(dnsName)
+	if err != nil {
+		return nil, fmt.Errorf("Error resolving %s: %s", dnsName, err)
+	}
+
+	seen := make(map[string]bool)
+	var ips []string
+	for _, addr := range addrs {
+		ip := addr.To4()
+		if ip == nil || seen[ip.String()] {
+			continue
+		}
+		seen[ip.String()] = true
+		ips = append(ips, ip.String())
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("Error resolving %s: no IPv4 addresses found", dnsName)
+	}
+	sort.Strings(ips)
+
+	return ips, nil
+}
+
+// lbTargetGroupAttachmentDnsRefreshDue reports whether the DNS name resolved
+// at resolvedAt is to be resolved again at now. An empty interval resolves it
+// on every refresh.
+func lbTargetGroupAttachmentDnsRefreshDue(interval, resolvedAt string, now time.Time) bool {
+	if interval == "" || resolvedAt == "" {
+		return true
+	}
+
+	every, err := time.ParseDuration(interval)
+	if err != nil {
+		return true
+	}
+	at, err := time.Parse(time.RFC3339, resolvedAt)
+	if err != nil {
+		log.Printf("[WARN] Unexpected resolution time %q: %s", resolvedAt, err)
+		return true
+	}
+
+	return !now.Before(at.Add(every))
+}
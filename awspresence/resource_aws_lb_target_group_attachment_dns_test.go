@@ -0,0 +1,177 @@
+package awspresence
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestResolveLbTargetGroupAttachmentDns(t *testing.T) {
+	defer func(lookup func(string) ([]net.IP, error)) { lbTargetGroupAttachmentDnsLookupIP = lookup }(lbTargetGroupAttachmentDnsLookupIP)
+
+	lbTargetGroupAttachmentDnsLookupIP = func(host string) ([]net.IP, error) {
+		switch host {
+		case "backend.example.com":
+			return []net.IP{
+				net.ParseIP("192.168.0.20"),
+				net.ParseIP("2001:db8::1"),
+				net.ParseIP("192.168.0.3"),
+				net.ParseIP("192.168.0.20"),
+			}, nil
+		case "v6.example.com":
+			return []net.IP{net.ParseIP("2001:db8::1")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	ips, err := resolveLbTargetGroupAttachmentDns("backend.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := []string{"192.168.0.20", "192.168.0.3"}; !reflect.DeepEqual(ips, expected) {
+		t.Fatalf("expected %v, got %v", expected, ips)
+	}
+
+	if _, err := resolveLbTargetGroupAttachmentDns("v6.example.com"); err == nil {
+		t.Fatal("expected an error without IPv4 addresses")
+	}
+	if _, err := resolveLbTargetGroupAttachmentDns("missing.example.com"); err == nil {
+		t.Fatal("expected an error for an unknown host")
+	}
+}
+
+func TestLbTargetGroupAttachmentDnsRefreshDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		interval   string
+		resolvedAt string
+		expected   bool
+	}{
+		{"", "2026-03-01T11:59:00Z", true},
+		{"15m", "", true},
+		{"15m", "2026-03-01T11:50:00Z", false},
+		{"15m", "2026-03-01T11:45:00Z", true},
+		{"0s", "2026-03-01T12:00:00Z", true},
+		{"15m", "not a time", true},
+	}
+
+	for _, tc := range cases {
+		actual := lbTargetGroupAttachmentDnsRefreshDue(tc.interval, tc.resolvedAt, now)
+		if actual != tc.expected {
+			t.Errorf("refresh_interval %q, resolved_at %q: expected %t, got %t", tc.interval, tc.resolvedAt, tc.expected, actual)
+		}
+	}
+}
+
+func TestAccAWSLBTargetGroupAttachmentDns_basic(t *testing.T) {
+	targetGroupName := fmt.Sprintf("test-target-group-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	resourceName := "aws_lb_target_group_attachment_dns.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBTargetGroupAttachmentDnsDestroy,
+		Steps: []resource.TestStep{
+			{
+				// An IP address literal resolves to itself, which keeps the
+				// test independent of DNS records.
+				Config: testAccAWSLBTargetGroupAttachmentDnsConfig(targetGroupName, "10.0.1.10"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBTargetGroupAttachmentDnsRegistered(resourceName, "10.0.1.10"),
+					resource.TestCheckResourceAttr(resourceName, "ip_addresses.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "resolved_ip_addresses.#", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "resolved_at"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSLBTargetGroupAttachmentDnsRegistered(n, ip string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).elbv2conn
+
+		describe, err := conn.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(rs.Primary.Attributes["target_group_arn"]),
+			Targets:        []*elbv2.TargetDescription{{Id: aws.String(ip), Port: aws.Int64(443)}},
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(describe.TargetHealthDescriptions) != 1 {
+			return fmt.Errorf("Target %s not registered", ip)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckAWSLBTargetGroupAttachmentDnsDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).elbv2conn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_lb_target_group_attachment_dns" {
+			continue
+		}
+
+		describe, err := conn.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(rs.Primary.Attributes["target_group_arn"]),
+		})
+		if isAWSErrCode(err, elbv2.ErrCodeTargetGroupNotFoundException) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Unexpected error checking LB destroyed: %s", err)
+		}
+
+		for _, description := range describe.TargetHealthDescriptions {
+			if aws.StringValue(description.TargetHealth.State) != elbv2.TargetHealthStateEnumDraining {
+				return fmt.Errorf("Target Group DNS Attachment %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccAWSLBTargetGroupAttachmentDnsConfig(targetGroupName, dnsName string) string {
+	return fmt.Sprintf(`
+resource "aws_lb_target_group_attachment_dns" "test" {
+  target_group_arn = "${aws_lb_target_group.test.arn}"
+  dns_name         = %[2]q
+  refresh_interval = "15m"
+}
+
+resource "aws_lb_target_group" "test" {
+  name        = %[1]q
+  port        = 443
+  protocol    = "TCP"
+  vpc_id      = "${aws_vpc.test.id}"
+  target_type = "ip"
+}
+
+resource "aws_vpc" "test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-target-group-attachment-dns"
+  }
+}
+`, targetGroupName, dnsName)
+}
@@ -32,6 +32,37 @@ func TestAccAWSLBTargetGroupAttachment_basic(t *testing.T) {
 	})
 }
 
+func TestAccAWSLBTargetGroupAttachment_tags(t *testing.T) {
+	targetGroupName := fmt.Sprintf("test-target-group-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+	resourceName := "aws_lb_target_group_attachment.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBTargetGroupAttachmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBTargetGroupAttachmentConfigTags(targetGroupName, "payments"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBTargetGroupAttachmentExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "tags.Owner", "payments"),
+					resource.TestCheckResourceAttr(resourceName, "tags.Purpose", "canary"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "tags.%", "1"),
+				),
+			},
+			{
+				Config: testAccAWSLBTargetGroupAttachmentConfigTags(targetGroupName, "checkout"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "tags.Owner", "checkout"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "tags.%", "1"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccAWSLBTargetGroupAttachmentBackwardsCompatibility(t *testing.T) {
 	targetGroupName := fmt.Sprintf("test-target-group-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
 
@@ -108,6 +139,23 @@ func TestAccAWSALBTargetGroupAttachment_lambda(t *testing.T) {
 	})
 }
 
+func TestFilterLbTargetHealthDescriptionsByPort(t *testing.T) {
+	descriptions := []*elbv2.TargetHealthDescription{
+		{Target: &elbv2.TargetDescription{Id: aws.String("i-0123456789abcdef0"), Port: aws.Int64(80)}},
+		{Target: &elbv2.TargetDescription{Id: aws.String("i-0123456789abcdef0"), Port: aws.Int64(32768)}},
+		{Target: &elbv2.TargetDescription{Id: aws.String("i-0123456789abcdef0"), Port: aws.Int64(32771)}},
+	}
+
+	filtered := filterLbTargetHealthDescriptionsByPort(descriptions, 80)
+	if len(filtered) != 1 || aws.Int64Value(filtered[0].Target.Port) != 80 {
+		t.Fatalf("expected the registration on port 80, got %v", filtered)
+	}
+
+	if filtered := filterLbTargetHealthDescriptionsByPort(descriptions, 8080); len(filtered) != 0 {
+		t.Fatalf("expected no registrations on port 8080, got %v", filtered)
+	}
+}
+
 func testAccCheckAWSLBTargetGroupAttachmentExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -179,7 +227,7 @@ func testAccCheckAWSLBTargetGroupAttachmentDestroy(s *terraform.State) error {
 		}
 
 		// Verify the error
-		if isAWSErr(err, elbv2.ErrCodeTargetGroupNotFoundException, "") || isAWSErr(err, elbv2.ErrCodeInvalidTargetException, "") {
+		if isAWSErrCode(err, elbv2.ErrCodeTargetGroupNotFoundException, elbv2.ErrCodeInvalidTargetException) {
 			return nil
 		} else {
 			return fmt.Errorf("Unexpected error checking LB destroyed: %s", err)
@@ -302,6 +350,55 @@ resource "aws_vpc" "test" {
 `, targetGroupName)
 }
 
+func testAccAWSLBTargetGroupAttachmentConfigTags(targetGroupName, owner string) string {
+	return fmt.Sprintf(`
+resource "aws_lb_target_group_attachment" "test" {
+  target_group_arn = "${aws_lb_target_group.test.arn}"
+  target_id        = "${aws_instance.test.id}"
+  port             = 80
+
+  tags = {
+    Owner   = %[2]q
+    Purpose = "canary"
+  }
+}
+
+resource "aws_instance" "test" {
+  ami           = "ami-f701cb97"
+  instance_type = "t2.micro"
+  subnet_id     = "${aws_subnet.subnet.id}"
+}
+
+resource "aws_lb_target_group" "test" {
+  name     = %[1]q
+  port     = 443
+  protocol = "HTTPS"
+  vpc_id   = "${aws_vpc.test.id}"
+
+  tags = {
+    Name = %[1]q
+  }
+}
+
+resource "aws_subnet" "subnet" {
+  cidr_block = "10.0.1.0/24"
+  vpc_id     = "${aws_vpc.test.id}"
+
+  tags = {
+    Name = "tf-acc-lb-target-group-attachment-tags"
+  }
+}
+
+resource "aws_vpc" "test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-target-group-attachment-tags"
+  }
+}
+`, targetGroupName, owner)
+}
+
 func testAccAWSLBTargetGroupAttachmentConfigBackwardsCompatibility(targetGroupName string) string {
 	return fmt.Sprintf(`
 resource "aws_alb_target_group_attachment" "test" {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"regexp"
 	"testing"
 
@@ -91,6 +92,124 @@ func TestLBTargetGroupCloudwatchSuffixFromARN(t *testing.T) {
 	}
 }
 
+func TestLBTargetGroupNameFromARN(t *testing.T) {
+	cases := map[string]string{
+		"arn:aws:elasticloadbalancing:us-east-1:123456:targetgroup/my-targets/73e2d6bc24d8a067": "my-targets",
+		"arn:aws:elasticloadbalancing:us-east-1:123456:targetgroup":                             "",
+		"": "",
+	}
+
+	for arn, expected := range cases {
+		if actual := lbTargetGroupNameFromARN(arn); actual != expected {
+			t.Fatalf("%q: expected %q, got %q", arn, expected, actual)
+		}
+	}
+}
+
+func TestExpandLbTargetGroupHealthCheckChanges(t *testing.T) {
+	healthCheck := func(protocol, path string, interval int) []interface{} {
+		return []interface{}{
+			map[string]interface{}{
+				"enabled":             true,
+				"interval":            interval,
+				"path":                path,
+				"port":                "traffic-port",
+				"protocol":            protocol,
+				"timeout":             5,
+				"healthy_threshold":   3,
+				"matcher":             "200",
+				"unhealthy_threshold": 3,
+			},
+		}
+	}
+
+	if params := expandLbTargetGroupHealthCheckChanges(healthCheck("HTTP", "/", 30), healthCheck("HTTP", "/", 30), elbv2.TargetTypeEnumInstance); params != nil {
+		t.Fatalf("expected no modification, got %s", params)
+	}
+
+	params := expandLbTargetGroupHealthCheckChanges(healthCheck("HTTP", "/", 30), healthCheck("https", "/", 30), elbv2.TargetTypeEnumInstance)
+	expected := &elbv2.ModifyTargetGroupInput{
+		HealthCheckProtocol: aws.String(elbv2.ProtocolEnumHttps),
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("expected %s, got %s", expected, params)
+	}
+
+	params = expandLbTargetGroupHealthCheckChanges(healthCheck("HTTP", "/", 30), healthCheck("HTTP", "/health", 10), elbv2.TargetTypeEnumInstance)
+	expected = &elbv2.ModifyTargetGroupInput{
+		HealthCheckIntervalSeconds: aws.Int64(10),
+		HealthCheckPath:            aws.String("/health"),
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("expected %s, got %s", expected, params)
+	}
+
+	// Lambda target groups have no health check port or protocol.
+	if params := expandLbTargetGroupHealthCheckChanges(healthCheck("HTTP", "/", 30), healthCheck("HTTPS", "/", 30), elbv2.TargetTypeEnumLambda); params != nil {
+		t.Fatalf("expected no modification for lambda target group, got %s", params)
+	}
+}
+
+func TestFlattenLbTargetGroupSecurityGroupIngressRequirements(t *testing.T) {
+	cases := []struct {
+		TargetGroup *elbv2.TargetGroup
+		Expected    []interface{}
+	}{
+		{
+			TargetGroup: &elbv2.TargetGroup{
+				TargetType:         aws.String(elbv2.TargetTypeEnumInstance),
+				Port:               aws.Int64(443),
+				Protocol:           aws.String(elbv2.ProtocolEnumHttps),
+				HealthCheckEnabled: aws.Bool(true),
+				HealthCheckPort:    aws.String("traffic-port"),
+			},
+			Expected: []interface{}{
+				map[string]interface{}{"port": 443, "protocol": "tcp", "traffic": true, "health_check": true},
+			},
+		},
+		{
+			TargetGroup: &elbv2.TargetGroup{
+				TargetType:         aws.String(elbv2.TargetTypeEnumIp),
+				Port:               aws.Int64(514),
+				Protocol:           aws.String(elbv2.ProtocolEnumTcpUdp),
+				HealthCheckEnabled: aws.Bool(true),
+				HealthCheckPort:    aws.String("8081"),
+			},
+			Expected: []interface{}{
+				map[string]interface{}{"port": 514, "protocol": "tcp", "traffic": true, "health_check": false},
+				map[string]interface{}{"port": 514, "protocol": "udp", "traffic": true, "health_check": false},
+				map[string]interface{}{"port": 8081, "protocol": "tcp", "traffic": false, "health_check": true},
+			},
+		},
+		{
+			TargetGroup: &elbv2.TargetGroup{
+				TargetType:         aws.String(elbv2.TargetTypeEnumInstance),
+				Port:               aws.Int64(80),
+				Protocol:           aws.String(elbv2.ProtocolEnumHttp),
+				HealthCheckEnabled: aws.Bool(false),
+				HealthCheckPort:    aws.String("8081"),
+			},
+			Expected: []interface{}{
+				map[string]interface{}{"port": 80, "protocol": "tcp", "traffic": true, "health_check": false},
+			},
+		},
+		{
+			TargetGroup: &elbv2.TargetGroup{
+				TargetType:         aws.String(elbv2.TargetTypeEnumLambda),
+				HealthCheckEnabled: aws.Bool(true),
+			},
+			Expected: []interface{}{},
+		},
+	}
+
+	for i, tc := range cases {
+		actual := flattenLbTargetGroupSecurityGroupIngressRequirements(tc.TargetGroup)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Fatalf("case %d: expected %v, got %v", i, tc.Expected, actual)
+		}
+	}
+}
+
 func TestAccAWSLBTargetGroup_basic(t *testing.T) {
 	var conf elbv2.TargetGroup
 	targetGroupName := fmt.Sprintf("test-target-group-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
@@ -126,6 +245,11 @@ func TestAccAWSLBTargetGroup_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("aws_lb_target_group.test", "health_check.0.healthy_threshold", "3"),
 					resource.TestCheckResourceAttr("aws_lb_target_group.test", "health_check.0.unhealthy_threshold", "3"),
 					resource.TestCheckResourceAttr("aws_lb_target_group.test", "health_check.0.matcher", "200-299"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "security_group_ingress_requirements.#", "2"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "security_group_ingress_requirements.0.port", "443"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "security_group_ingress_requirements.0.protocol", "tcp"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "security_group_ingress_requirements.1.port", "8081"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "security_group_ingress_requirements.1.health_check", "true"),
 					resource.TestCheckResourceAttr("aws_lb_target_group.test", "tags.%", "1"),
 					resource.TestCheckResourceAttr("aws_lb_target_group.test", "tags.TestName", "TestAccAWSLBTargetGroup_basic"),
 				),
@@ -711,6 +835,44 @@ func TestAccAWSLBTargetGroup_updateHealthCheck(t *testing.T) {
 	})
 }
 
+func TestAccAWSLBTargetGroup_updateHealthCheckProtocol(t *testing.T) {
+	var pre, post elbv2.TargetGroup
+	targetGroupName := fmt.Sprintf("test-target-group-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBTargetGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBTargetGroupConfig_healthCheckProtocol(targetGroupName, "HTTP"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBTargetGroupExists("aws_lb_target_group.test", &pre),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "health_check.0.protocol", "HTTP"),
+				),
+			},
+			{
+				Config: testAccAWSLBTargetGroupConfig_healthCheckProtocol(targetGroupName, "HTTPS"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBTargetGroupExists("aws_lb_target_group.test", &post),
+					testAccCheckAWSLBTargetGroupARNs(&pre, &post),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "health_check.0.protocol", "HTTPS"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "health_check.0.path", "/health"),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "health_check.0.matcher", "200-299"),
+				),
+			},
+			{
+				Config: testAccAWSLBTargetGroupConfig_healthCheckProtocol(targetGroupName, "HTTP"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBTargetGroupExists("aws_lb_target_group.test", &post),
+					testAccCheckAWSLBTargetGroupARNs(&pre, &post),
+					resource.TestCheckResourceAttr("aws_lb_target_group.test", "health_check.0.protocol", "HTTP"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccAWSLBTargetGroup_updateSticknessEnabled(t *testing.T) {
 	var conf elbv2.TargetGroup
 	targetGroupName := fmt.Sprintf("test-target-group-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
@@ -941,6 +1103,16 @@ func TestAccAWSLBTargetGroup_stickinessWithTCPEnabledShouldError(t *testing.T) {
 	})
 }
 
+func testAccCheckAWSLBTargetGroupARNs(pre, post *elbv2.TargetGroup) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if aws.StringValue(pre.TargetGroupArn) != aws.StringValue(post.TargetGroupArn) {
+			return errors.New("LB Target Group has been recreated. ARNs are different")
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckAWSLBTargetGroupExists(n string, res *elbv2.TargetGroup) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -1062,7 +1234,7 @@ func testAccCheckAWSLBTargetGroupDestroy(s *terraform.State) error {
 		}
 
 		// Verify the error
-		if isAWSErr(err, elbv2.ErrCodeTargetGroupNotFoundException, "") {
+		if isAWSErrCode(err, elbv2.ErrCodeTargetGroupNotFoundException) {
 			return nil
 		} else {
 			return fmt.Errorf("Unexpected error checking ALB destroyed: %s", err)
@@ -1179,6 +1351,33 @@ resource "aws_vpc" "test" {
 }`, targetGroupName)
 }
 
+func testAccAWSLBTargetGroupConfig_healthCheckProtocol(targetGroupName, healthCheckProtocol string) string {
+	return fmt.Sprintf(`resource "aws_lb_target_group" "test" {
+  name = "%s"
+  port = 443
+  protocol = "HTTPS"
+  vpc_id = "${aws_vpc.test.id}"
+
+  health_check {
+    path = "/health"
+    protocol = "%s"
+    matcher = "200-299"
+  }
+
+  tags = {
+    TestName = "TestAccAWSLBTargetGroup_updateHealthCheckProtocol"
+  }
+}
+
+resource "aws_vpc" "test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    TestName = "terraform-testacc-lb-target-group-health-check-protocol"
+  }
+}`, targetGroupName, healthCheckProtocol)
+}
+
 func testAccAWSLBTargetGroupConfig_basicUdp(targetGroupName string) string {
 	return fmt.Sprintf(`resource "aws_lb_target_group" "test" {
   name = "%s"
@@ -4,13 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"regexp"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -129,6 +132,61 @@ func TestAccAWSLB_ALB_basic(t *testing.T) {
 	})
 }
 
+func TestFlattenLbAvailabilityZoneAddresses(t *testing.T) {
+	lb := &elbv2.LoadBalancer{
+		DNSName: aws.String("my-nlb-abc123.elb.us-west-2.amazonaws.com"),
+		AvailabilityZones: []*elbv2.AvailabilityZone{
+			{ZoneName: aws.String("us-west-2b"), SubnetId: aws.String("subnet-b")},
+			{ZoneName: aws.String("us-west-2a"), SubnetId: aws.String("subnet-a")},
+			{ZoneName: aws.String("us-west-2c"), SubnetId: aws.String("subnet-c")},
+		},
+	}
+	enis := []*ec2.NetworkInterface{
+		{
+			NetworkInterfaceId: aws.String("eni-a"),
+			SubnetId:           aws.String("subnet-a"),
+			PrivateIpAddress:   aws.String("10.0.0.10"),
+			Association:        &ec2.NetworkInterfaceAssociation{PublicIp: aws.String("203.0.113.10")},
+		},
+		{
+			NetworkInterfaceId: aws.String("eni-b"),
+			SubnetId:           aws.String("subnet-b"),
+			PrivateIpAddress:   aws.String("10.0.1.10"),
+		},
+	}
+
+	expected := []map[string]interface{}{
+		{
+			"availability_zone":    "us-west-2a",
+			"subnet_id":            "subnet-a",
+			"dns_name":             "us-west-2a.my-nlb-abc123.elb.us-west-2.amazonaws.com",
+			"network_interface_id": "eni-a",
+			"private_ipv4_address": "10.0.0.10",
+			"public_ipv4_address":  "203.0.113.10",
+		},
+		{
+			"availability_zone":    "us-west-2b",
+			"subnet_id":            "subnet-b",
+			"dns_name":             "us-west-2b.my-nlb-abc123.elb.us-west-2.amazonaws.com",
+			"network_interface_id": "eni-b",
+			"private_ipv4_address": "10.0.1.10",
+			"public_ipv4_address":  "",
+		},
+		{
+			"availability_zone":    "us-west-2c",
+			"subnet_id":            "subnet-c",
+			"dns_name":             "us-west-2c.my-nlb-abc123.elb.us-west-2.amazonaws.com",
+			"network_interface_id": "",
+			"private_ipv4_address": "",
+			"public_ipv4_address":  "",
+		},
+	}
+
+	if got := flattenLbAvailabilityZoneAddresses(lb, enis); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, got)
+	}
+}
+
 func TestAccAWSLB_NLB_basic(t *testing.T) {
 	var conf elbv2.LoadBalancer
 	lbName := fmt.Sprintf("testaccawslb-basic-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
@@ -156,6 +214,9 @@ func TestAccAWSLB_NLB_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
 					resource.TestCheckResourceAttr(resourceName, "tags.Name", "TestAccAWSALB_basic"),
 					resource.TestCheckResourceAttrSet(resourceName, "zone_id"),
+					resource.TestCheckResourceAttr(resourceName, "availability_zone_addresses.#", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "availability_zone_addresses.0.network_interface_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "availability_zone_addresses.0.private_ipv4_address"),
 				),
 			},
 		},
@@ -184,6 +245,9 @@ func TestAccAWSLB_networkLoadbalancerEIP(t *testing.T) {
 					resource.TestCheckResourceAttr("aws_lb.lb_test", "load_balancer_type", "network"),
 					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_deletion_protection", "false"),
 					resource.TestCheckResourceAttr("aws_lb.lb_test", "subnet_mapping.#", "2"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "availability_zone_addresses.#", "2"),
+					resource.TestCheckResourceAttrSet("aws_lb.lb_test", "availability_zone_addresses.0.public_ipv4_address"),
+					resource.TestCheckResourceAttrSet("aws_lb.lb_test", "availability_zone_addresses.1.public_ipv4_address"),
 				),
 			},
 		},
@@ -357,6 +421,148 @@ func TestAccAWSLB_networkLoadbalancer_updateCrossZone(t *testing.T) {
 	})
 }
 
+func TestAccAWSLB_networkLoadbalancer_updateZonalShift(t *testing.T) {
+	var pre, mid, post elbv2.LoadBalancer
+	lbName := fmt.Sprintf("testaccawslb-nlbzs-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:      func() { testAccPreCheck(t) },
+		IDRefreshName: "aws_lb.lb_test",
+		Providers:     testAccProviders,
+		CheckDestroy:  testAccCheckAWSLBDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBConfig_networkLoadbalancerZonalShift(lbName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &pre),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "zonal_shift.config.enabled", "true"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_zonal_shift", "true"),
+				),
+			},
+			{
+				Config: testAccAWSLBConfig_networkLoadbalancerZonalShift(lbName, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &mid),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "zonal_shift.config.enabled", "false"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_zonal_shift", "false"),
+					testAccCheckAWSlbARNs(&pre, &mid),
+				),
+			},
+			{
+				Config: testAccAWSLBConfig_networkLoadbalancerZonalShift(lbName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &post),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "zonal_shift.config.enabled", "true"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_zonal_shift", "true"),
+					testAccCheckAWSlbARNs(&mid, &post),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSLB_networkLoadbalancer_region(t *testing.T) {
+	var providers []*schema.Provider
+	lbName := fmt.Sprintf("testaccawslb-region-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories(&providers),
+		CheckDestroy:      testAccCheckAWSLBDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBConfig_networkLoadbalancerRegion(lbName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "region", "us-east-1"),
+					resource.TestMatchResourceAttr("aws_lb.lb_test", "arn", regexp.MustCompile(`^arn:[^:]+:elasticloadbalancing:us-east-1:`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSLB_applicationLoadBalancer_updateHeaderAttributes(t *testing.T) {
+	var pre, mid, post elbv2.LoadBalancer
+	lbName := fmt.Sprintf("testaccawsalb-xff-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:      func() { testAccPreCheck(t) },
+		IDRefreshName: "aws_lb.lb_test",
+		Providers:     testAccProviders,
+		CheckDestroy:  testAccCheckAWSLBDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBConfig_headerAttributes(lbName, false, false, "append"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &pre),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.preserve_host_header.enabled", "false"),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.xff_client_port.enabled", "false"),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.xff_header_processing.mode", "append"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "preserve_host_header", "false"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_xff_client_port", "false"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "xff_header_processing_mode", "append"),
+				),
+			},
+			{
+				Config: testAccAWSLBConfig_headerAttributes(lbName, true, true, "preserve"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &mid),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.preserve_host_header.enabled", "true"),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.xff_client_port.enabled", "true"),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.xff_header_processing.mode", "preserve"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "preserve_host_header", "true"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_xff_client_port", "true"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "xff_header_processing_mode", "preserve"),
+					testAccCheckAWSlbARNs(&pre, &mid),
+				),
+			},
+			{
+				Config: testAccAWSLBConfig_headerAttributes(lbName, true, false, "remove"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &post),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.preserve_host_header.enabled", "true"),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.xff_client_port.enabled", "false"),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.xff_header_processing.mode", "remove"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_xff_client_port", "false"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "xff_header_processing_mode", "remove"),
+					testAccCheckAWSlbARNs(&mid, &post),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAWSLB_applicationLoadBalancer_updateTlsVersionAndCipherSuiteHeaders(t *testing.T) {
+	var pre, post elbv2.LoadBalancer
+	lbName := fmt.Sprintf("testaccawsalb-tls-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:      func() { testAccPreCheck(t) },
+		IDRefreshName: "aws_lb.lb_test",
+		Providers:     testAccProviders,
+		CheckDestroy:  testAccCheckAWSLBDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBConfig_tlsVersionAndCipherSuiteHeaders(lbName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &pre),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.x_amzn_tls_version_and_cipher_suite.enabled", "true"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_tls_version_and_cipher_suite_headers", "true"),
+				),
+			},
+			{
+				Config: testAccAWSLBConfig_tlsVersionAndCipherSuiteHeaders(lbName, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &post),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "routing.http.x_amzn_tls_version_and_cipher_suite.enabled", "false"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_tls_version_and_cipher_suite_headers", "false"),
+					testAccCheckAWSlbARNs(&pre, &post),
+				),
+			},
+		},
+	})
+}
+
 func TestAccAWSLB_applicationLoadBalancer_updateHttp2(t *testing.T) {
 	var pre, mid, post elbv2.LoadBalancer
 	lbName := fmt.Sprintf("testaccawsalb-http2-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
@@ -437,6 +643,44 @@ func TestAccAWSLB_applicationLoadBalancer_updateDeletionProtection(t *testing.T)
 	})
 }
 
+func TestAccAWSLB_applicationLoadBalancer_enforceDeletionProtection(t *testing.T) {
+	var lb elbv2.LoadBalancer
+	lbName := fmt.Sprintf("testaccawsalb-basic-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSLBDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSLBConfig_enforceDeletionProtection(lbName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBExists("aws_lb.lb_test", &lb),
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "deletion_protection.enabled", "true"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enforce_deletion_protection", "true"),
+					testAccCheckAWSLBDisableDeletionProtection(&lb),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccAWSLBConfig_enforceDeletionProtection(lbName, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "deletion_protection.enabled", "true"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enable_deletion_protection", "true"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enforce_deletion_protection", "true"),
+				),
+			},
+			{
+				Config: testAccAWSLBConfig_enforceDeletionProtection(lbName, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSLBAttribute("aws_lb.lb_test", "deletion_protection.enabled", "false"),
+					resource.TestCheckResourceAttr("aws_lb.lb_test", "enforce_deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccAWSLB_updatedSecurityGroups(t *testing.T) {
 	var pre, post elbv2.LoadBalancer
 	lbName := fmt.Sprintf("testaccawslb-basic-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
@@ -939,6 +1183,24 @@ func testAccCheckAWSLBExists(n string, res *elbv2.LoadBalancer) resource.TestChe
 	}
 }
 
+func testAccCheckAWSLBDisableDeletionProtection(lb *elbv2.LoadBalancer) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*AWSClient).elbv2conn
+
+		_, err := conn.ModifyLoadBalancerAttributes(&elbv2.ModifyLoadBalancerAttributesInput{
+			LoadBalancerArn: lb.LoadBalancerArn,
+			Attributes: []*elbv2.LoadBalancerAttribute{
+				{
+					Key:   aws.String("deletion_protection.enabled"),
+					Value: aws.String("false"),
+				},
+			},
+		})
+
+		return err
+	}
+}
+
 func testAccCheckAWSLBAttribute(n, key, value string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -971,13 +1233,13 @@ func testAccCheckAWSLBAttribute(n, key, value string) resource.TestCheckFunc {
 }
 
 func testAccCheckAWSLBDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*AWSClient).elbv2conn
-
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "aws_lb" && rs.Type != "aws_alb" {
 			continue
 		}
 
+		conn := testAccProvider.Meta().(*AWSClient).regionalClient(rs.Primary.Attributes["region"]).elbv2conn
+
 		describe, err := conn.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
 			LoadBalancerArns: []*string{aws.String(rs.Primary.ID)},
 		})
@@ -1366,6 +1628,150 @@ resource "aws_security_group" "alb_test" {
 `, lbName, http2)
 }
 
+func testAccAWSLBConfig_tlsVersionAndCipherSuiteHeaders(lbName string, enabled bool) string {
+	return fmt.Sprintf(`
+resource "aws_lb" "lb_test" {
+  name            = "%s"
+  internal        = true
+  security_groups = ["${aws_security_group.alb_test.id}"]
+  subnets         = ["${aws_subnet.alb_test.*.id[0]}", "${aws_subnet.alb_test.*.id[1]}"]
+
+  idle_timeout               = 30
+  enable_deletion_protection = false
+
+  enable_tls_version_and_cipher_suite_headers = %t
+
+  tags = {
+    Name = "TestAccAWSALB_basic"
+  }
+}
+
+variable "subnets" {
+  default = ["10.0.1.0/24", "10.0.2.0/24"]
+  type    = "list"
+}
+
+data "aws_availability_zones" "available" {}
+
+resource "aws_vpc" "alb_test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-basic"
+  }
+}
+
+resource "aws_subnet" "alb_test" {
+  count                   = 2
+  vpc_id                  = "${aws_vpc.alb_test.id}"
+  cidr_block              = "${element(var.subnets, count.index)}"
+  map_public_ip_on_launch = true
+  availability_zone       = "${element(data.aws_availability_zones.available.names, count.index)}"
+
+  tags = {
+    Name = "tf-acc-lb-basic-${count.index}"
+  }
+}
+
+resource "aws_security_group" "alb_test" {
+  name        = "allow_all_alb_test"
+  description = "Used for ALB Testing"
+  vpc_id      = "${aws_vpc.alb_test.id}"
+
+  ingress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = {
+    Name = "TestAccAWSALB_basic"
+  }
+}
+`, lbName, enabled)
+}
+
+func testAccAWSLBConfig_headerAttributes(lbName string, preserveHostHeader, xffClientPort bool, xffMode string) string {
+	return fmt.Sprintf(`
+resource "aws_lb" "lb_test" {
+  name            = "%s"
+  internal        = true
+  security_groups = ["${aws_security_group.alb_test.id}"]
+  subnets         = ["${aws_subnet.alb_test.*.id[0]}", "${aws_subnet.alb_test.*.id[1]}"]
+
+  idle_timeout               = 30
+  enable_deletion_protection = false
+
+  preserve_host_header       = %t
+  enable_xff_client_port     = %t
+  xff_header_processing_mode = %q
+
+  tags = {
+    Name = "TestAccAWSALB_basic"
+  }
+}
+
+variable "subnets" {
+  default = ["10.0.1.0/24", "10.0.2.0/24"]
+  type    = "list"
+}
+
+data "aws_availability_zones" "available" {}
+
+resource "aws_vpc" "alb_test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-basic"
+  }
+}
+
+resource "aws_subnet" "alb_test" {
+  count                   = 2
+  vpc_id                  = "${aws_vpc.alb_test.id}"
+  cidr_block              = "${element(var.subnets, count.index)}"
+  map_public_ip_on_launch = true
+  availability_zone       = "${element(data.aws_availability_zones.available.names, count.index)}"
+
+  tags = {
+    Name = "tf-acc-lb-basic-${count.index}"
+  }
+}
+
+resource "aws_security_group" "alb_test" {
+  name        = "allow_all_alb_test"
+  description = "Used for ALB Testing"
+  vpc_id      = "${aws_vpc.alb_test.id}"
+
+  ingress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = {
+    Name = "TestAccAWSALB_basic"
+  }
+}
+`, lbName, preserveHostHeader, xffClientPort, xffMode)
+}
+
 func testAccAWSLBConfig_enableDeletionProtection(lbName string, deletion_protection bool) string {
 	return fmt.Sprintf(`
 resource "aws_lb" "lb_test" {
@@ -1435,6 +1841,75 @@ resource "aws_security_group" "alb_test" {
 `, lbName, deletion_protection)
 }
 
+func testAccAWSLBConfig_enforceDeletionProtection(lbName string, enforce bool) string {
+	return fmt.Sprintf(`
+resource "aws_lb" "lb_test" {
+  name            = "%s"
+  internal        = true
+  security_groups = ["${aws_security_group.alb_test.id}"]
+  subnets         = ["${aws_subnet.alb_test.*.id[0]}", "${aws_subnet.alb_test.*.id[1]}"]
+
+  idle_timeout                = 30
+  enforce_deletion_protection = %t
+
+  tags = {
+    Name = "TestAccAWSALB_basic"
+  }
+}
+
+variable "subnets" {
+  default = ["10.0.1.0/24", "10.0.2.0/24"]
+  type    = "list"
+}
+
+data "aws_availability_zones" "available" {}
+
+resource "aws_vpc" "alb_test" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-lb-basic"
+  }
+}
+
+resource "aws_subnet" "alb_test" {
+  count                   = 2
+  vpc_id                  = "${aws_vpc.alb_test.id}"
+  cidr_block              = "${element(var.subnets, count.index)}"
+  map_public_ip_on_launch = true
+  availability_zone       = "${element(data.aws_availability_zones.available.names, count.index)}"
+
+  tags = {
+    Name = "tf-acc-lb-basic-${count.index}"
+  }
+}
+
+resource "aws_security_group" "alb_test" {
+  name        = "allow_all_alb_test"
+  description = "Used for ALB Testing"
+  vpc_id      = "${aws_vpc.alb_test.id}"
+
+  ingress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+
+  tags = {
+    Name = "TestAccAWSALB_basic"
+  }
+}
+`, lbName, enforce)
+}
+
 func testAccAWSLBConfig_networkLoadbalancer_subnets(lbName string) string {
 	return fmt.Sprintf(`
 resource "aws_vpc" "alb_test" {
@@ -1537,6 +2012,90 @@ resource "aws_subnet" "alb_test" {
 `, lbName, cz)
 }
 
+func testAccAWSLBConfig_networkLoadbalancerZonalShift(lbName string, zonalShift bool) string {
+	return fmt.Sprintf(`
+resource "aws_lb" "lb_test" {
+  name               = "%s"
+  internal           = true
+  load_balancer_type = "network"
+
+  enable_deletion_protection = false
+  enable_zonal_shift         = %t
+
+  subnet_mapping {
+    subnet_id = "${aws_subnet.alb_test.id}"
+  }
+
+  tags = {
+    Name = "TestAccAWSALB_zonalShift"
+  }
+}
+
+resource "aws_vpc" "alb_test" {
+  cidr_block = "10.10.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-network-load-balancer-zonal-shift"
+  }
+}
+
+resource "aws_subnet" "alb_test" {
+  vpc_id                  = "${aws_vpc.alb_test.id}"
+  cidr_block              = "10.10.0.0/21"
+  map_public_ip_on_launch = true
+  availability_zone       = "us-west-2a"
+
+  tags = {
+    Name = "tf-acc-network-load-balancer-zonal-shift"
+  }
+}
+`, lbName, zonalShift)
+}
+
+func testAccAWSLBConfig_networkLoadbalancerRegion(lbName string) string {
+	return fmt.Sprintf(`
+provider "aws" {
+  alias  = "east"
+  region = "us-east-1"
+}
+
+resource "aws_lb" "lb_test" {
+  name               = "%s"
+  region             = "us-east-1"
+  internal           = true
+  load_balancer_type = "network"
+
+  subnet_mapping {
+    subnet_id = "${aws_subnet.alb_test.id}"
+  }
+
+  tags = {
+    Name = "TestAccAWSALB_region"
+  }
+}
+
+resource "aws_vpc" "alb_test" {
+  provider   = "aws.east"
+  cidr_block = "10.10.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-network-load-balancer-region"
+  }
+}
+
+resource "aws_subnet" "alb_test" {
+  provider          = "aws.east"
+  vpc_id            = "${aws_vpc.alb_test.id}"
+  cidr_block        = "10.10.0.0/21"
+  availability_zone = "us-east-1a"
+
+  tags = {
+    Name = "tf-acc-network-load-balancer-region"
+  }
+}
+`, lbName)
+}
+
 func testAccAWSLBConfig_networkLoadBalancerEIP(lbName string) string {
 	return fmt.Sprintf(`
 data "aws_availability_zones" "available" {}
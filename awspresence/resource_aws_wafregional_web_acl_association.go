@@ -0,0 +1,137 @@
+package awspresence
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/wafregional"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceAwsWafRegionalWebAclAssociation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAwsWafRegionalWebAclAssociationCreate,
+		Read:   resourceAwsWafRegionalWebAclAssociationRead,
+		Delete: resourceAwsWafRegionalWebAclAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAwsWafRegionalWebAclAssociationImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"web_acl_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"resource_arn": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"region": regionSchema(),
+		},
+	}
+}
+
+func resourceAwsWafRegionalWebAclAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).wafregionalconn
+	webAclId := d.Get("web_acl_id").(string)
+	resourceArn := d.Get("resource_arn").(string)
+
+	log.Printf("[INFO] Associating WAF Regional Web ACL %s with %s", webAclId, resourceArn)
+	params := &wafregional.AssociateWebACLInput{
+		WebACLId:    aws.String(webAclId),
+		ResourceArn: aws.String(resourceArn),
+	}
+
+	// A load balancer that was just created may not be known to WAF yet.
+	err := resource.Retry(2*time.Minute, func() *resource.RetryError {
+		_, err := conn.AssociateWebACL(params)
+		if isAWSErrCode(err, wafregional.ErrCodeWAFUnavailableEntityException) {
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating WAF Regional Web ACL association: %s", err)
+	}
+
+	d.SetId(webAclId + ":" + resourceArn)
+
+	return resourceAwsWafRegionalWebAclAssociationRead(d, meta)
+}
+
+func resourceAwsWafRegionalWebAclAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	client := resourceAWSClient(d, meta)
+	resourceArn := d.Get("resource_arn").(string)
+
+	resp, err := client.wafregionalconn.GetWebACLForResource(&wafregional.GetWebACLForResourceInput{
+		ResourceArn: aws.String(resourceArn),
+	})
+	if isAWSErrCode(err, wafregional.ErrCodeWAFNonexistentItemException) {
+		log.Printf("[WARN] WAF Regional Web ACL association %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading WAF Regional Web ACL association %s: %s", d.Id(), err)
+	}
+
+	if resp.WebACLSummary == nil {
+		log.Printf("[WARN] No WAF Regional Web ACL associated with %s, removing %s from state", resourceArn, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// Another Web ACL associated with the resource replaces this association.
+	d.Set("web_acl_id", resp.WebACLSummary.WebACLId)
+	d.Set("region", client.region)
+
+	return nil
+}
+
+func resourceAwsWafRegionalWebAclAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := resourceAWSClient(d, meta).wafregionalconn
+	resourceArn := d.Get("resource_arn").(string)
+
+	log.Printf("[INFO] Disassociating WAF Regional Web ACL %s from %s", d.Get("web_acl_id").(string), resourceArn)
+	_, err := conn.DisassociateWebACL(&wafregional.DisassociateWebACLInput{
+		ResourceArn: aws.String(resourceArn),
+	})
+	if err != nil && !isAWSErrCode(err, wafregional.ErrCodeWAFNonexistentItemException) {
+		return fmt.Errorf("Error deleting WAF Regional Web ACL association %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func resourceAwsWafRegionalWebAclAssociationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	webAclId, resourceArn, err := parseWafRegionalWebAclAssociationId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("web_acl_id", webAclId)
+	d.Set("resource_arn", resourceArn)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// parseWafRegionalWebAclAssociationId splits the ID of an association into the
+// ID of the Web ACL and the ARN of the resource.
+func parseWafRegionalWebAclAssociationId(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || !strings.HasPrefix(parts[1], "arn:") {
+		return "", "", fmt.Errorf("Unexpected format of ID (%q), expected WEB-ACL-ID:RESOURCE-ARN", id)
+	}
+
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,212 @@
+package awspresence
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/wafregional"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestParseWafRegionalWebAclAssociationId(t *testing.T) {
+	cases := []struct {
+		id          string
+		webAclId    string
+		resourceArn string
+		expectErr   bool
+	}{
+		{
+			id:          "f1e2d3c4-b5a6-4788-9900-aabbccddeeff:arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188",
+			webAclId:    "f1e2d3c4-b5a6-4788-9900-aabbccddeeff",
+			resourceArn: "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188",
+		},
+		{
+			id:        "f1e2d3c4-b5a6-4788-9900-aabbccddeeff",
+			expectErr: true,
+		},
+		{
+			id:        ":arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188",
+			expectErr: true,
+		},
+		{
+			id:        "f1e2d3c4-b5a6-4788-9900-aabbccddeeff:my-lb",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		webAclId, resourceArn, err := parseWafRegionalWebAclAssociationId(tc.id)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.id, err)
+			continue
+		}
+		if webAclId != tc.webAclId || resourceArn != tc.resourceArn {
+			t.Errorf("%s: expected %s and %s, got %s and %s", tc.id, tc.webAclId, tc.resourceArn, webAclId, resourceArn)
+		}
+	}
+}
+
+func TestAccAWSWafRegionalWebAclAssociation_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSWafRegionalWebAclAssociationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSWafRegionalWebAclAssociationConfig(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckAWSWafRegionalWebAclAssociationExists("aws_wafregional_web_acl_association.test"),
+					resource.TestCheckResourceAttrPair("aws_wafregional_web_acl_association.test", "web_acl_id", "aws_wafregional_web_acl.test", "id"),
+					resource.TestCheckResourceAttrPair("aws_wafregional_web_acl_association.test", "resource_arn", "aws_lb.test", "arn"),
+				),
+			},
+			{
+				ResourceName:      "aws_wafregional_web_acl_association.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccAWSWafRegionalWebAclAssociation_disappears(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-%s", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSWafRegionalWebAclAssociationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAWSWafRegionalWebAclAssociationConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSWafRegionalWebAclAssociationExists("aws_wafregional_web_acl_association.test"),
+					testAccCheckAWSWafRegionalWebAclAssociationDisappears("aws_wafregional_web_acl_association.test"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccCheckAWSWafRegionalWebAclAssociationExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).wafregionalconn
+		resp, err := conn.GetWebACLForResource(&wafregional.GetWebACLForResourceInput{
+			ResourceArn: aws.String(rs.Primary.Attributes["resource_arn"]),
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.WebACLSummary == nil || aws.StringValue(resp.WebACLSummary.WebACLId) != rs.Primary.Attributes["web_acl_id"] {
+			return fmt.Errorf("WAF Regional Web ACL association %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckAWSWafRegionalWebAclAssociationDisappears(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).wafregionalconn
+		_, err := conn.DisassociateWebACL(&wafregional.DisassociateWebACLInput{
+			ResourceArn: aws.String(rs.Primary.Attributes["resource_arn"]),
+		})
+		return err
+	}
+}
+
+func testAccCheckAWSWafRegionalWebAclAssociationDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).wafregionalconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_wafregional_web_acl_association" {
+			continue
+		}
+
+		resp, err := conn.GetWebACLForResource(&wafregional.GetWebACLForResourceInput{
+			ResourceArn: aws.String(rs.Primary.Attributes["resource_arn"]),
+		})
+		if isAWSErrCode(err, wafregional.ErrCodeWAFNonexistentItemException) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if resp.WebACLSummary != nil && aws.StringValue(resp.WebACLSummary.WebACLId) == rs.Primary.Attributes["web_acl_id"] {
+			return fmt.Errorf("WAF Regional Web ACL association %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccAWSWafRegionalWebAclAssociationConfig(rName string) string {
+	return fmt.Sprintf(`
+resource "aws_wafregional_web_acl" "test" {
+  name        = %[1]q
+  metric_name = "tfaccwebacl"
+
+  default_action {
+    type = "ALLOW"
+  }
+}
+
+resource "aws_lb" "test" {
+  name     = %[1]q
+  internal = true
+  subnets  = ["${aws_subnet.test.*.id}"]
+
+  enable_deletion_protection = false
+}
+
+resource "aws_vpc" "test" {
+  cidr_block = "10.1.0.0/16"
+
+  tags = {
+    Name = "terraform-testacc-wafregional-web-acl-association"
+  }
+}
+
+data "aws_availability_zones" "available" {}
+
+resource "aws_subnet" "test" {
+  count             = 2
+  vpc_id            = "${aws_vpc.test.id}"
+  cidr_block        = "10.1.${count.index}.0/24"
+  availability_zone = "${data.aws_availability_zones.available.names[count.index]}"
+
+  tags = {
+    Name = "tf-acc-wafregional-web-acl-association-${count.index}"
+  }
+}
+
+resource "aws_wafregional_web_acl_association" "test" {
+  resource_arn = "${aws_lb.test.arn}"
+  web_acl_id   = "${aws_wafregional_web_acl.test.id}"
+}
+`, rName)
+}
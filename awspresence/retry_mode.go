@@ -0,0 +1,194 @@
+package awspresence
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	retryModeStandard = "standard"
+	retryModeAdaptive = "adaptive"
+
+	// adaptiveRetryBeta is the factor the send rate is cut by on throttling.
+	adaptiveRetryBeta = 0.7
+	// adaptiveRetryMinFillRate is the lowest send rate, in requests per
+	// second, the limiter throttles down to.
+	adaptiveRetryMinFillRate = 0.5
+	// adaptiveRetrySmoothing weighs the previous measured send rate against
+	// the rate of the window that just ended.
+	adaptiveRetrySmoothing  = 0.8
+	adaptiveRetryRateWindow = time.Second
+)
+
+// adaptiveRateLimiter is a client side token bucket in the spirit of the
+// adaptive retry mode of newer AWS SDKs. It lets requests through unhindered
+// until the service throttles one, then caps the send rate below the rate
+// that was measured at the time and slowly raises it again while requests
+// succeed.
+type adaptiveRateLimiter struct {
+	mu sync.Mutex
+
+	enabled    bool
+	fillRate   float64
+	maxRate    float64
+	tokens     float64
+	lastRefill time.Time
+
+	windowStart  time.Time
+	windowCount  int
+	measuredRate float64
+}
+
+// reserve takes a token for a request attempted at now and returns how long
+// the attempt must wait for it.
+func (l *adaptiveRateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.measure(now)
+	if !l.enabled {
+		return 0
+	}
+
+	l.refill(now)
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.fillRate * float64(time.Second))
+}
+
+// update adjusts the send rate after an attempt completed at now.
+func (l *adaptiveRateLimiter) update(now time.Time, throttled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if throttled {
+		rate := l.sendRate(now)
+		if l.enabled {
+			rate = math.Min(rate, l.fillRate)
+		}
+		l.maxRate = rate
+		l.fillRate = math.Max(rate*adaptiveRetryBeta, adaptiveRetryMinFillRate)
+
+		if !l.enabled {
+			l.enabled = true
+			l.tokens = 0
+			l.lastRefill = now
+		}
+		return
+	}
+
+	if l.enabled {
+		// Each success raises the rate by 1/fillRate, so it grows by about
+		// one request per second every second at full speed.
+		l.refill(now)
+		l.fillRate = math.Min(l.fillRate+1/l.fillRate, math.Max(2*l.maxRate, adaptiveRetryMinFillRate))
+	}
+}
+
+func (l *adaptiveRateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	l.tokens = math.Min(l.tokens+elapsed*l.fillRate, math.Max(l.fillRate, 1))
+	l.lastRefill = now
+}
+
+func (l *adaptiveRateLimiter) measure(now time.Time) {
+	if l.windowStart.IsZero() {
+		l.windowStart = now
+	}
+
+	if elapsed := now.Sub(l.windowStart); elapsed >= adaptiveRetryRateWindow {
+		rate := float64(l.windowCount) / elapsed.Seconds()
+		l.measuredRate = adaptiveRetrySmoothing*l.measuredRate + (1-adaptiveRetrySmoothing)*rate
+		l.windowStart = now
+		l.windowCount = 0
+	}
+
+	l.windowCount++
+}
+
+// sendRate returns the measured send rate, falling back to the rate of the
+// current window before a full window has been measured.
+func (l *adaptiveRateLimiter) sendRate(now time.Time) float64 {
+	if l.measuredRate > 0 {
+		return l.measuredRate
+	}
+
+	elapsed := math.Max(now.Sub(l.windowStart).Seconds(), adaptiveRetryRateWindow.Seconds())
+	return float64(l.windowCount) / elapsed
+}
+
+// adaptiveRetryLimiters holds a rate limiter per service and region, the
+// scope at which the APIs throttle.
+type adaptiveRetryLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*adaptiveRateLimiter
+}
+
+func (l *adaptiveRetryLimiters) get(r *request.Request) *adaptiveRateLimiter {
+	key := r.ClientInfo.ServiceName + "/" + aws.StringValue(r.Config.Region)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limiters == nil {
+		l.limiters = make(map[string]*adaptiveRateLimiter)
+	}
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = &adaptiveRateLimiter{}
+		l.limiters[key] = limiter
+	}
+
+	return limiter
+}
+
+// addAdaptiveRetryHandlers rate limits every attempt made with handlers,
+// including the retries of the SDK's default retryer.
+func addAdaptiveRetryHandlers(handlers *request.Handlers) {
+	limiters := &adaptiveRetryLimiters{}
+
+	handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: "awspresence.AdaptiveRetryAcquire",
+		Fn: func(r *request.Request) {
+			wait := limiters.get(r).reserve(time.Now())
+			if wait <= 0 {
+				return
+			}
+
+			log.Printf("[DEBUG] Rate limiting %s/%s for %s", r.ClientInfo.ServiceName, r.Operation.Name, wait)
+			if err := aws.SleepWithContext(r.Context(), wait); err != nil {
+				r.Error = err
+			}
+		},
+	})
+
+	handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{
+		Name: "awspresence.AdaptiveRetryUpdate",
+		Fn: func(r *request.Request) {
+			limiters.get(r).update(time.Now(), isAdaptiveRetryThrottle(r))
+		},
+	})
+}
+
+func isAdaptiveRetryThrottle(r *request.Request) bool {
+	if r.Error == nil {
+		return false
+	}
+	if r.HTTPResponse != nil && r.HTTPResponse.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return r.IsErrorThrottle()
+}
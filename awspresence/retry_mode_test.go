@@ -0,0 +1,113 @@
+package awspresence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+func TestAdaptiveRateLimiter_unthrottled(t *testing.T) {
+	l := &adaptiveRateLimiter{}
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 100; i++ {
+		if wait := l.reserve(now); wait != 0 {
+			t.Fatalf("expected no wait before throttling, got %s", wait)
+		}
+		l.update(now, false)
+		now = now.Add(10 * time.Millisecond)
+	}
+}
+
+func TestAdaptiveRateLimiter_throttled(t *testing.T) {
+	l := &adaptiveRateLimiter{}
+	now := time.Unix(0, 0)
+
+	// 20 requests per second for two seconds, the last one throttled.
+	for i := 0; i < 40; i++ {
+		l.reserve(now)
+		l.update(now, i == 39)
+		now = now.Add(50 * time.Millisecond)
+	}
+
+	if !l.enabled {
+		t.Fatal("expected throttling to enable the limiter")
+	}
+	if l.fillRate >= 20 {
+		t.Fatalf("expected fill rate below the measured 20/s, got %f", l.fillRate)
+	}
+	throttledRate := l.fillRate
+
+	// The bucket starts empty, so an immediate attempt waits for a token.
+	if wait := l.reserve(l.lastRefill); wait <= 0 {
+		t.Fatalf("expected a wait after throttling, got %s", wait)
+	}
+
+	for i := 0; i < 50; i++ {
+		now = now.Add(time.Second)
+		l.reserve(now)
+		l.update(now, false)
+	}
+	if l.fillRate <= throttledRate {
+		t.Fatalf("expected fill rate to recover above %f, got %f", throttledRate, l.fillRate)
+	}
+	if l.fillRate > 2*l.maxRate {
+		t.Fatalf("expected fill rate capped at %f, got %f", 2*l.maxRate, l.fillRate)
+	}
+
+	l.update(now, true)
+	if l.fillRate < adaptiveRetryMinFillRate {
+		t.Fatalf("expected fill rate of at least %f, got %f", adaptiveRetryMinFillRate, l.fillRate)
+	}
+}
+
+func TestAddAdaptiveRetryHandlers(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Region:      aws.String("us-west-2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addAdaptiveRetryHandlers(&sess.Handlers)
+
+	conn := elbv2.New(sess)
+	conn.Handlers.Send.Clear()
+	conn.Handlers.Unmarshal.Clear()
+	conn.Handlers.UnmarshalMeta.Clear()
+	conn.Handlers.ValidateResponse.Clear()
+	conn.Handlers.UnmarshalError.Clear()
+
+	attempts := 0
+	conn.Handlers.Send.PushBack(func(r *request.Request) {
+		attempts++
+		if attempts == 1 {
+			r.Error = awserr.New("Throttling", "Rate exceeded", nil)
+		}
+	})
+	conn.Handlers.AfterRetry.Clear()
+	conn.Handlers.AfterRetry.PushBack(func(r *request.Request) {
+		r.Retryable = aws.Bool(true)
+		r.RetryCount++
+		r.Error = nil
+	})
+
+	start := time.Now()
+	if _, err := conn.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+	// A single throttle within the first second drops the rate to the
+	// minimum, so the retry waits for its token.
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("expected the retry to be rate limited, took %s", elapsed)
+	}
+}
@@ -265,9 +265,10 @@ func tagIgnored(t *ec2.Tag) bool {
 	return false
 }
 
-// and for ELBv2 as well
+// and for ELBv2 as well, along with the tags target group attachments keep
+// on their target group
 func tagIgnoredELBv2(t *elbv2.Tag) bool {
-	filter := []string{"^aws:"}
+	filter := []string{"^aws:", "^" + lbTargetGroupAttachmentTagPrefix}
 	for _, v := range filter {
 		log.Printf("[DEBUG] Matching %v with %v\n", v, *t.Key)
 		r, _ := regexp.MatchString(v, *t.Key)
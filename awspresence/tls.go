@@ -0,0 +1,95 @@
+package awspresence
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	pemBlockTypeCertificate   = `CERTIFICATE`
+	pemBlockTypeRsaPrivateKey = `RSA PRIVATE KEY`
+)
+
+var tlsX509CertificateSerialNumberLimit = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// tlsRsaPrivateKeyPem generates a RSA private key PEM string.
+// Wrap with tlsPemEscapeNewlines() to allow simple fmt.Sprintf()
+// configurations such as: private_key_pem = "%[1]s"
+func tlsRsaPrivateKeyPem(bits int) string {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+
+	if err != nil {
+		panic(err)
+	}
+
+	block := &pem.Block{
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+		Type:  pemBlockTypeRsaPrivateKey,
+	}
+
+	return string(pem.EncodeToMemory(block))
+}
+
+// tlsRsaX509SelfSignedCertificatePem generates a x509 self-signed certificate
+// PEM string, valid for twelve hours from now.
+// Wrap with tlsPemEscapeNewlines() to allow simple fmt.Sprintf()
+// configurations such as: certificate_pem = "%[1]s"
+func tlsRsaX509SelfSignedCertificatePem(keyPem, commonName string) string {
+	keyBlock, _ := pem.Decode([]byte(keyPem))
+
+	if keyBlock == nil {
+		panic("unable to decode RSA private key PEM")
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+
+	if err != nil {
+		panic(err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, tlsX509CertificateSerialNumberLimit)
+
+	if err != nil {
+		panic(err)
+	}
+
+	notBefore := time.Now()
+	certificate := &x509.Certificate{
+		BasicConstraintsValid: true,
+		DNSNames:              []string{commonName},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		NotAfter:              notBefore.Add(12 * time.Hour),
+		NotBefore:             notBefore,
+		SerialNumber:          serialNumber,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{"ACME Examples, Inc"},
+		},
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, certificate, certificate, &key.PublicKey, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	certificateBlock := &pem.Block{
+		Bytes: certificateBytes,
+		Type:  pemBlockTypeCertificate,
+	}
+
+	return string(pem.EncodeToMemory(certificateBlock))
+}
+
+// tlsPemEscapeNewlines escapes the newlines of a PEM string so it can be
+// embedded in a quoted configuration string.
+func tlsPemEscapeNewlines(pem string) string {
+	return strings.Replace(pem, "\n", "\\n", -1)
+}
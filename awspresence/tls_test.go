@@ -0,0 +1,73 @@
+package awspresence
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTlsRsaPrivateKeyPem(t *testing.T) {
+	key := tlsRsaPrivateKeyPem(2048)
+
+	block, rest := pem.Decode([]byte(key))
+	if block == nil {
+		t.Fatalf("expected a PEM block, got %q", key)
+	}
+	if block.Type != pemBlockTypeRsaPrivateKey {
+		t.Fatalf("expected block type %q, got %q", pemBlockTypeRsaPrivateKey, block.Type)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("expected a single PEM block, got trailing data %q", rest)
+	}
+
+	parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("unable to parse private key: %s", err)
+	}
+	if got := parsed.N.BitLen(); got != 2048 {
+		t.Fatalf("expected a 2048 bit key, got %d bits", got)
+	}
+}
+
+func TestTlsRsaX509SelfSignedCertificatePem(t *testing.T) {
+	key := tlsRsaPrivateKeyPem(2048)
+	certificate := tlsRsaX509SelfSignedCertificatePem(key, "example.com")
+
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil {
+		t.Fatalf("expected a PEM block, got %q", certificate)
+	}
+	if block.Type != pemBlockTypeCertificate {
+		t.Fatalf("expected block type %q, got %q", pemBlockTypeCertificate, block.Type)
+	}
+
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %s", err)
+	}
+	if parsed.Subject.CommonName != "example.com" {
+		t.Fatalf("expected common name example.com, got %q", parsed.Subject.CommonName)
+	}
+	if err := parsed.VerifyHostname("example.com"); err != nil {
+		t.Fatalf("expected certificate to be valid for example.com: %s", err)
+	}
+	if err := parsed.CheckSignature(parsed.SignatureAlgorithm, parsed.RawTBSCertificate, parsed.Signature); err != nil {
+		t.Fatalf("expected certificate to be self-signed: %s", err)
+	}
+	if now := time.Now(); now.Before(parsed.NotBefore) || now.After(parsed.NotAfter) {
+		t.Fatalf("expected certificate to be currently valid, got %s - %s", parsed.NotBefore, parsed.NotAfter)
+	}
+}
+
+func TestTlsPemEscapeNewlines(t *testing.T) {
+	escaped := tlsPemEscapeNewlines(tlsRsaPrivateKeyPem(1024))
+
+	if strings.Contains(escaped, "\n") {
+		t.Fatalf("expected no newlines, got %q", escaped)
+	}
+	if !strings.HasSuffix(escaped, "-----END RSA PRIVATE KEY-----\\n") {
+		t.Fatalf("expected escaped trailing newline, got %q", escaped)
+	}
+}
@@ -0,0 +1,48 @@
+package awspresence
+
+import (
+	"strings"
+
+	awsbase "github.com/hashicorp/aws-sdk-go-base"
+)
+
+const (
+	userAgentModuleProductName    = "tf-module"
+	userAgentWorkspaceProductName = "tf-workspace"
+)
+
+// userAgentModuleProducts returns the User-Agent products identifying the
+// Terraform module and workspace the provider runs for, so that CloudTrail
+// can attribute API calls, e.g. rule changes, to them. Empty identifiers are
+// left out.
+func userAgentModuleProducts(module, workspace string) []*awsbase.UserAgentProduct {
+	var products []*awsbase.UserAgentProduct
+	if module != "" {
+		products = append(products, &awsbase.UserAgentProduct{
+			Name:    userAgentModuleProductName,
+			Version: userAgentToken(module),
+		})
+	}
+	if workspace != "" {
+		products = append(products, &awsbase.UserAgentProduct{
+			Name:    userAgentWorkspaceProductName,
+			Version: userAgentToken(workspace),
+		})
+	}
+	return products
+}
+
+// userAgentToken replaces the characters of s that are not allowed in a
+// User-Agent product token (RFC 7230), such as the "/" of module paths, with
+// "_".
+func userAgentToken(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+			return r
+		}
+		return '_'
+	}, s)
+}
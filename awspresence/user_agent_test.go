@@ -0,0 +1,31 @@
+package awspresence
+
+import (
+	"testing"
+)
+
+func TestUserAgentModuleProducts(t *testing.T) {
+	cases := []struct {
+		Module    string
+		Workspace string
+		Expected  []string
+	}{
+		{"", "", nil},
+		{"module.edge.module.lb", "", []string{"tf-module/module.edge.module.lb"}},
+		{"", "prod", []string{"tf-workspace/prod"}},
+		{"modules/lb rules", "prod-eu", []string{"tf-module/modules_lb_rules", "tf-workspace/prod-eu"}},
+	}
+
+	for _, tc := range cases {
+		products := userAgentModuleProducts(tc.Module, tc.Workspace)
+		if len(products) != len(tc.Expected) {
+			t.Errorf("%q, %q: expected %d products, got %d", tc.Module, tc.Workspace, len(tc.Expected), len(products))
+			continue
+		}
+		for i, product := range products {
+			if got := product.Name + "/" + product.Version; got != tc.Expected[i] {
+				t.Errorf("%q, %q: expected %q, got %q", tc.Module, tc.Workspace, tc.Expected[i], got)
+			}
+		}
+	}
+}
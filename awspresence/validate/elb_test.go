@@ -0,0 +1,134 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestElbName(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "", ErrCount: 0},
+		{Value: "tf-test-elb", ErrCount: 0},
+		{Value: "Testing123", ErrCount: 0},
+		{Value: strings.Repeat("a", 32), ErrCount: 0},
+		{Value: "tf.test.elb.1", ErrCount: 1},
+		{Value: "Testing123%%", ErrCount: 1},
+		{Value: "tf-test-elb-tf-test-elb-tf-test-elb", ErrCount: 1},
+		{Value: "Testing123dddddddddddddddddddvvvv", ErrCount: 1},
+		{Value: "-tf-test-elb", ErrCount: 1},
+		{Value: "-Testing123", ErrCount: 1},
+		{Value: "tf-test-elb-", ErrCount: 1},
+		{Value: "Testing123-", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := ElbName(tc.Value, "name")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestElbNamePrefix(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "test-", ErrCount: 0},
+		{Value: "tf", ErrCount: 0},
+		{Value: "tf.test.elb.", ErrCount: 2},
+		{Value: "tf-test", ErrCount: 1},
+		{Value: "-test", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := ElbNamePrefix(tc.Value, "name_prefix")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestElbAccessLogsInterval(t *testing.T) {
+	cases := []struct {
+		Value    int
+		ErrCount int
+	}{
+		{Value: 5, ErrCount: 0},
+		{Value: 60, ErrCount: 0},
+		{Value: 0, ErrCount: 1},
+		{Value: 10, ErrCount: 1},
+		{Value: -1, ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := ElbAccessLogsInterval(tc.Value, "interval")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%d: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestElbHealthCheckTarget(t *testing.T) {
+	// A complete set of modern Katakana characters, to check the path length
+	// is counted in characters rather than bytes.
+	katakana := "アイウエオ" +
+		"カキクケコガギグゲゴサシスセソザジズゼゾ" +
+		"タチツテトダヂヅデドナニヌネノハヒフヘホ" +
+		"バビブベボパピプペポマミムメモヤユヨラリ" +
+		"ルレロワヰヱヲン"
+
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "TCP:1234", ErrCount: 0},
+		{Value: "http:80/test", ErrCount: 0},
+		{Value: "HTTPS:443/health?full=1", ErrCount: 0},
+		{Value: fmt.Sprintf("HTTP:8080/%s", katakana[:15]), ErrCount: 0},
+		{Value: fmt.Sprintf("HTTP:8080/%s", strings.Repeat("ア", 1023)), ErrCount: 0},
+		{Value: "SSL:8080", ErrCount: 0},
+		{Value: "", ErrCount: 1},
+		{Value: "TCP:", ErrCount: 1},
+		{Value: "TCP:1234/", ErrCount: 1},
+		{Value: "SSL:8080/", ErrCount: 1},
+		{Value: "HTTP:8080", ErrCount: 1},
+		{Value: "incorrect-value", ErrCount: 1},
+		{Value: "TCP:123456", ErrCount: 1},
+		{Value: "TCP:0", ErrCount: 1},
+		{Value: "incorrect:80/", ErrCount: 1},
+		{Value: fmt.Sprintf("HTTP:8080/%s%s", strings.Repeat("a", 512), strings.Repeat(katakana, 10)), ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := ElbHealthCheckTarget(tc.Value, "target")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestElbListenerProtocol(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "HTTP", ErrCount: 0},
+		{Value: "https", ErrCount: 0},
+		{Value: "SSL", ErrCount: 0},
+		{Value: "tcp", ErrCount: 0},
+		{Value: "UDP", ErrCount: 1},
+		{Value: "", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := ElbListenerProtocol()(tc.Value, "lb_protocol")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IamServerCertificatePath validates the path of an IAM server certificate.
+func IamServerCertificatePath(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if len(value) > 512 {
+		errors = append(errors, fmt.Errorf("%q cannot be longer than 512 characters: %q", k, value))
+	}
+	if !strings.HasPrefix(value, "/") || !strings.HasSuffix(value, "/") {
+		errors = append(errors, fmt.Errorf("%q must begin and end with a forward slash: %q", k, value))
+	}
+	for _, r := range value {
+		if r < 0x21 || r > 0x7E {
+			errors = append(errors, fmt.Errorf("%q can only contain printable ASCII characters other than spaces: %q", k, value))
+			break
+		}
+	}
+
+	return
+}
@@ -0,0 +1,31 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIamServerCertificatePath(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "/", ErrCount: 0},
+		{Value: "/cloudfront/", ErrCount: 0},
+		{Value: "/cloudfront/example/", ErrCount: 0},
+		{Value: "", ErrCount: 1},
+		{Value: "cloudfront", ErrCount: 1},
+		{Value: "/cloudfront", ErrCount: 1},
+		{Value: "cloudfront/", ErrCount: 1},
+		{Value: "/cloud front/", ErrCount: 1},
+		{Value: "/cloudfront/é/", ErrCount: 1},
+		{Value: "/" + strings.Repeat("a", 512) + "/", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := IamServerCertificatePath(tc.Value, "path")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// LbListenerRulePriority validates the priority of a listener rule: 1-50000
+// for a normal rule, or 99999 for the default rule.
+func LbListenerRulePriority(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(int)
+	if value < 1 || (value > 50000 && value != 99999) {
+		errors = append(errors, fmt.Errorf("%q must be in the range 1-50000 for normal rule or 99999 for default rule", k))
+	}
+	return
+}
+
+// LbTargetGroupName validates the name of a target group.
+func LbTargetGroupName(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if len(value) > 32 {
+		errors = append(errors, fmt.Errorf(
+			"%q cannot be longer than 32 characters", k))
+	}
+	if !regexp.MustCompile(`^[0-9A-Za-z-]+$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"only alphanumeric characters and hyphens allowed in %q", k))
+	}
+	if regexp.MustCompile(`^-`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q cannot begin with a hyphen", k))
+	}
+	if regexp.MustCompile(`-$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q cannot end with a hyphen", k))
+	}
+	return
+}
+
+// LbTargetGroupNamePrefix validates the name_prefix of a target group, which
+// leaves room for the unique suffix within the 32 characters of a name.
+func LbTargetGroupNamePrefix(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	prefixMaxLength := 32 - resource.UniqueIDSuffixLength
+	if len(value) > prefixMaxLength {
+		errors = append(errors, fmt.Errorf(
+			"%q cannot be longer than %d characters", k, prefixMaxLength))
+	}
+	if !regexp.MustCompile(`^[0-9A-Za-z-]+$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"only alphanumeric characters and hyphens allowed in %q", k))
+	}
+	if regexp.MustCompile(`^-`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q cannot begin with a hyphen", k))
+	}
+	return
+}
+
+// LbTargetGroupHealthCheckPath validates the path of the health check of a
+// target group.
+func LbTargetGroupHealthCheckPath(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if len(value) > 1024 {
+		errors = append(errors, fmt.Errorf(
+			"%q cannot be longer than 1024 characters: %q", k, value))
+	}
+	if len(value) > 0 && !strings.HasPrefix(value, "/") {
+		errors = append(errors, fmt.Errorf(
+			"%q must begin with a '/' character: %q", k, value))
+	}
+	return
+}
+
+// LbTargetGroupHealthCheckPort validates the port of the health check of a
+// target group: a port number, or "traffic-port".
+func LbTargetGroupHealthCheckPort(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if value == "traffic-port" {
+		return
+	}
+
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		errors = append(errors, fmt.Errorf("%q must be a valid port number (1-65535) or %q", k, "traffic-port"))
+	}
+
+	return
+}
+
+// LbTargetGroupSlowStart validates the slow start duration of a target group,
+// in seconds.
+func LbTargetGroupSlowStart(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(int)
+
+	// Check if the value is between 30-900 or 0 (seconds).
+	if value != 0 && !(value >= 30 && value <= 900) {
+		errors = append(errors, fmt.Errorf(
+			"%q contains an invalid Slow Start Duration \"%d\". "+
+				"Valid intervals are 30-900 or 0 to disable.",
+			k, value))
+	}
+	return
+}
+
+const (
+	// lbPathPatternMaxLength and lbPathPatternMaxWildcards are the limits
+	// the ELB API enforces on each value of a path-pattern condition.
+	lbPathPatternMaxLength    = 128
+	lbPathPatternMaxWildcards = 3
+)
+
+var (
+	lbPathPatternCharsRegexp    = regexp.MustCompile(`^[A-Za-z0-9_\-.$/~"'@:+&*?]*$`)
+	lbPathPatternStarRunsRegexp = regexp.MustCompile(`\*{2,}`)
+)
+
+// NormalizeLbPathPattern trims the whitespace around a path pattern and
+// collapses runs of "*", which the ELB API matches like a single "*".
+func NormalizeLbPathPattern(pattern string) string {
+	return lbPathPatternStarRunsRegexp.ReplaceAllString(strings.TrimSpace(pattern), "*")
+}
+
+// LbPathPatternWildcardCount counts the "*" and "?" wildcards of a path
+// pattern, which the ELB API limits per pattern and per rule.
+func LbPathPatternWildcardCount(pattern string) int {
+	return strings.Count(pattern, "*") + strings.Count(pattern, "?")
+}
+
+// LbPathPattern validates a value of a path-pattern condition against the
+// limits of the ELB API. The pattern is checked once normalized by
+// NormalizeLbPathPattern.
+func LbPathPattern(v interface{}, k string) (ws []string, errors []error) {
+	pattern := NormalizeLbPathPattern(v.(string))
+
+	switch {
+	case pattern == "":
+		errors = append(errors, fmt.Errorf("%q: path pattern must not be empty", k))
+	case len(pattern) > lbPathPatternMaxLength:
+		errors = append(errors, fmt.Errorf("%q: path pattern must be at most %d characters long, got %d", k, lbPathPatternMaxLength, len(pattern)))
+	case !lbPathPatternCharsRegexp.MatchString(pattern):
+		errors = append(errors, fmt.Errorf(`%q: path pattern contains illegal characters, only A-Z, a-z, 0-9, _ - . $ / ~ " ' @ : + & * and ? are allowed`, k))
+	default:
+		if n := LbPathPatternWildcardCount(pattern); n > lbPathPatternMaxWildcards {
+			errors = append(errors, fmt.Errorf("%q: path pattern must contain at most %d wildcards, got %d", k, lbPathPatternMaxWildcards, n))
+		}
+	}
+	return
+}
+
+// LbListenerRuleConditionsSpec validates conditions written as in the import
+// ID of a listener rule: either "fingerprint=<hex>", or "&"-separated
+// "<field>=<value>[,<value>...]" pairs, e.g.
+// "host-header=example.com&path-pattern=/api/*". HTTP header conditions are
+// written as "http-header.<name>".
+func LbListenerRuleConditionsSpec(v interface{}, k string) (ws []string, errors []error) {
+	spec := v.(string)
+	if strings.HasPrefix(spec, "fingerprint=") {
+		if strings.TrimPrefix(spec, "fingerprint=") == "" {
+			errors = append(errors, fmt.Errorf("%q: fingerprint must not be empty", k))
+		}
+		return
+	}
+
+	for _, pair := range strings.Split(spec, "&") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			errors = append(errors, fmt.Errorf("%q: expected <field>=<values>, got %q", k, pair))
+			continue
+		}
+
+		switch field := kv[0]; field {
+		case "host-header", "http-request-method", "path-pattern", "query-string", "source-ip":
+		default:
+			if !strings.HasPrefix(field, "http-header.") || field == "http-header." {
+				errors = append(errors, fmt.Errorf("%q: unsupported condition field %q", k, field))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,220 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLbListenerRulePriority(t *testing.T) {
+	cases := []struct {
+		Value    int
+		ErrCount int
+	}{
+		{Value: 1, ErrCount: 0},
+		{Value: 100, ErrCount: 0},
+		{Value: 50000, ErrCount: 0},
+		{Value: 99999, ErrCount: 0},
+		{Value: 0, ErrCount: 1},
+		{Value: -1, ErrCount: 1},
+		{Value: 50001, ErrCount: 1},
+		{Value: 99998, ErrCount: 1},
+		{Value: 100000, ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := LbListenerRulePriority(tc.Value, "priority")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%d: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestLbTargetGroupName(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "tf-test-target", ErrCount: 0},
+		{Value: strings.Repeat("a", 32), ErrCount: 0},
+		{Value: "tf.test.elb.target.1", ErrCount: 1},
+		{Value: "-tf-test-target", ErrCount: 1},
+		{Value: "tf-test-target-", ErrCount: 1},
+		{Value: strings.Repeat("a", 33), ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := LbTargetGroupName(tc.Value, "name")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestLbTargetGroupNamePrefix(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "tf-lb-", ErrCount: 0},
+		{Value: "tf", ErrCount: 0},
+		{Value: strings.Repeat("a", 6), ErrCount: 0},
+		{Value: "tf.lb", ErrCount: 1},
+		{Value: "-tf-lb", ErrCount: 1},
+		{Value: strings.Repeat("a", 7), ErrCount: 1},
+		{Value: strings.Repeat("a", 32), ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := LbTargetGroupNamePrefix(tc.Value, "name_prefix")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestLbTargetGroupHealthCheckPath(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "", ErrCount: 0},
+		{Value: "/", ErrCount: 0},
+		{Value: "/health", ErrCount: 0},
+		{Value: "/" + strings.Repeat("a", 1023), ErrCount: 0},
+		{Value: "health", ErrCount: 1},
+		{Value: "/" + strings.Repeat("a", 1024), ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := LbTargetGroupHealthCheckPath(tc.Value, "path")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestLbTargetGroupHealthCheckPort(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "traffic-port", ErrCount: 0},
+		{Value: "1", ErrCount: 0},
+		{Value: "8081", ErrCount: 0},
+		{Value: "65535", ErrCount: 0},
+		{Value: "0", ErrCount: 1},
+		{Value: "65536", ErrCount: 1},
+		{Value: "http", ErrCount: 1},
+		{Value: "", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := LbTargetGroupHealthCheckPort(tc.Value, "port")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestLbTargetGroupSlowStart(t *testing.T) {
+	cases := []struct {
+		Value    int
+		ErrCount int
+	}{
+		{Value: 0, ErrCount: 0},
+		{Value: 30, ErrCount: 0},
+		{Value: 900, ErrCount: 0},
+		{Value: 29, ErrCount: 1},
+		{Value: 901, ErrCount: 1},
+		{Value: -1, ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := LbTargetGroupSlowStart(tc.Value, "slow_start")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%d: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestNormalizeLbPathPattern(t *testing.T) {
+	cases := map[string]string{
+		"/static/*":       "/static/*",
+		" /api/v?/* ":     "/api/v?/*",
+		"/img/**/***.png": "/img/*/*.png",
+	}
+
+	for pattern, expected := range cases {
+		if got := NormalizeLbPathPattern(pattern); got != expected {
+			t.Errorf("%q: expected %q, got %q", pattern, expected, got)
+		}
+	}
+}
+
+func TestLbPathPatternWildcardCount(t *testing.T) {
+	cases := map[string]int{
+		"/api":      0,
+		"/static/*": 1,
+		"/api/v?/*": 2,
+	}
+
+	for pattern, expected := range cases {
+		if got := LbPathPatternWildcardCount(pattern); got != expected {
+			t.Errorf("%q: expected %d wildcards, got %d", pattern, expected, got)
+		}
+	}
+}
+
+func TestLbPathPattern(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "/static/*", ErrCount: 0},
+		{Value: " /api/v?/* ", ErrCount: 0},
+		{Value: "/img/**/*.png", ErrCount: 0},
+		{Value: "/a/*/b/*/c/*", ErrCount: 0},
+		{Value: "/a/*/b/*/c/*/d?", ErrCount: 1},
+		{Value: "", ErrCount: 1},
+		{Value: "   ", ErrCount: 1},
+		{Value: "/search?q=a b", ErrCount: 1},
+		{Value: "/path#fragment", ErrCount: 1},
+		{Value: "/users/{id}", ErrCount: 1},
+		{Value: "/" + strings.Repeat("a", 127), ErrCount: 0},
+		{Value: "/" + strings.Repeat("a", 128), ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := LbPathPattern(tc.Value, "patterns.0")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestLbListenerRuleConditionsSpec(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "path-pattern=/static/*", ErrCount: 0},
+		{Value: "host-header=example.com&path-pattern=/api/*,/v2/*", ErrCount: 0},
+		{Value: "http-header.X-Env=staging", ErrCount: 0},
+		{Value: "query-string=version:v2,beta", ErrCount: 0},
+		{Value: "fingerprint=0123456789abcdef", ErrCount: 0},
+		{Value: "fingerprint=", ErrCount: 1},
+		{Value: "", ErrCount: 1},
+		{Value: "path-pattern", ErrCount: 1},
+		{Value: "path-pattern=", ErrCount: 1},
+		{Value: "http-header.=staging", ErrCount: 1},
+		{Value: "unknown-field=value", ErrCount: 1},
+		{Value: "unknown-field=value&path-pattern", ErrCount: 2},
+	}
+
+	for _, tc := range cases {
+		_, errors := LbListenerRuleConditionsSpec(tc.Value, "conditions")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package validate
+
+import (
+	"fmt"
+)
+
+// SecretsManagerRecoveryWindow validates the number of days Secrets Manager
+// waits before it deletes a secret: 0 to delete it immediately, or 7-30.
+func SecretsManagerRecoveryWindow(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(int)
+	if value != 0 && (value < 7 || value > 30) {
+		errors = append(errors, fmt.Errorf("%q must be 0 or between 7 and 30, got %d", k, value))
+	}
+	return
+}
@@ -0,0 +1,27 @@
+package validate
+
+import (
+	"testing"
+)
+
+func TestSecretsManagerRecoveryWindow(t *testing.T) {
+	cases := []struct {
+		Value    int
+		ErrCount int
+	}{
+		{Value: 0, ErrCount: 0},
+		{Value: 7, ErrCount: 0},
+		{Value: 30, ErrCount: 0},
+		{Value: 1, ErrCount: 1},
+		{Value: 6, ErrCount: 1},
+		{Value: 31, ErrCount: 1},
+		{Value: -7, ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := SecretsManagerRecoveryWindow(tc.Value, "recovery_window_in_days")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%d: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
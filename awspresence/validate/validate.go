@@ -0,0 +1,77 @@
+// Package validate provides the schema validation functions shared by the
+// resources and data sources of the provider.
+package validate
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Arn validates that a string is an ARN. The empty string is accepted.
+func Arn(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if value == "" {
+		return
+	}
+
+	// http://docs.aws.amazon.com/lambda/latest/dg/API_AddPermission.html
+	pattern := `^arn:[\w-]+:([a-zA-Z0-9\-])+:([a-z]{2}-(gov-)?[a-z]+-\d{1})?:(\d{12})?:(.*)$`
+	if !regexp.MustCompile(pattern).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q doesn't look like a valid ARN (%q): %q",
+			k, pattern, value))
+	}
+
+	return
+}
+
+// CIDRNetworkAddress validates that a string is a CIDR that represents a
+// network address, e.g. 10.0.1.0/24 rather than 10.0.1.1/24.
+func CIDRNetworkAddress(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	_, ipnet, err := net.ParseCIDR(value)
+	if err != nil {
+		errors = append(errors, fmt.Errorf(
+			"%q must contain a valid CIDR, got error parsing: %s", k, err))
+		return
+	}
+
+	if ipnet == nil || value != ipnet.String() {
+		errors = append(errors, fmt.Errorf(
+			"%q must contain a valid network CIDR, got %q", k, value))
+	}
+
+	return
+}
+
+// PrefixListId validates that a string is the ID of a managed prefix list.
+func PrefixListId(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if !regexp.MustCompile(`^pl-[0-9a-f]+$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q must be a prefix list ID such as pl-3b927c52, got %q", k, value))
+	}
+	return
+}
+
+// Duration returns a function that validates that a string is a duration as
+// accepted by time.ParseDuration, e.g. "15m" or "720h", of at least min.
+func Duration(min time.Duration) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(string)
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%q must be a duration, e.g. \"15m\" or \"720h\": %s", k, err))
+			return
+		}
+		if d < min {
+			errors = append(errors, fmt.Errorf("%q must be at least %s, got %s", k, min, value))
+		}
+		return
+	}
+}
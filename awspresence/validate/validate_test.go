@@ -0,0 +1,122 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArn(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "", ErrCount: 0},
+		{Value: "arn:aws:elasticbeanstalk:us-east-1:123456789012:environment/My App/MyEnvironment", ErrCount: 0}, // Beanstalk
+		{Value: "arn:aws:iam::123456789012:user/David", ErrCount: 0},                                             // IAM User
+		{Value: "arn:aws:rds:eu-west-1:123456789012:db:mysql-db", ErrCount: 0},                                   // RDS
+		{Value: "arn:aws:s3:::my_corporate_bucket/exampleobject.png", ErrCount: 0},                               // S3 object
+		{Value: "arn:aws:events:us-east-1:319201112229:rule/rule_name", ErrCount: 0},                             // CloudWatch Rule
+		{Value: "arn:aws:lambda:eu-west-1:319201112229:function:myCustomFunction", ErrCount: 0},                  // Lambda function
+		{Value: "arn:aws:lambda:eu-west-1:319201112229:function:myCustomFunction:Qualifier", ErrCount: 0},        // Lambda func qualifier
+		{Value: "arn:aws-us-gov:s3:::corp_bucket/object.png", ErrCount: 0},                                       // GovCloud ARN
+		{Value: "arn:aws-us-gov:kms:us-gov-west-1:123456789012:key/some-uuid-abc123", ErrCount: 0},               // GovCloud KMS ARN
+		{Value: "arn", ErrCount: 1},
+		{Value: "123456789012", ErrCount: 1},
+		{Value: "arn:aws", ErrCount: 1},
+		{Value: "arn:aws:logs", ErrCount: 1},
+		{Value: "arn:aws:logs:region:*:*", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := Arn(tc.Value, "arn")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestCIDRNetworkAddress(t *testing.T) {
+	cases := []struct {
+		Value             string
+		ExpectedErrSubstr string
+	}{
+		{"notacidr", `must contain a valid CIDR`},
+		{"10.0.1.0/16", `must contain a valid network CIDR`},
+		{"10.0.1.1/32", ``},
+		{"10.0.1.0/24", ``},
+		{"0.0.0.0/0", ``},
+		{"2001:db8::/32", ``},
+		{"2001:db8::1/32", `must contain a valid network CIDR`},
+		{"10.0.1.0", `must contain a valid CIDR`},
+	}
+
+	for _, tc := range cases {
+		_, errors := CIDRNetworkAddress(tc.Value, "cidr")
+		if tc.ExpectedErrSubstr == "" {
+			if len(errors) != 0 {
+				t.Errorf("%q: expected no errors, got %q", tc.Value, errors)
+			}
+			continue
+		}
+		if len(errors) != 1 {
+			t.Errorf("%q: expected 1 error containing %q, got %q", tc.Value, tc.ExpectedErrSubstr, errors)
+			continue
+		}
+		if !strings.Contains(errors[0].Error(), tc.ExpectedErrSubstr) {
+			t.Errorf("%q: expected error %q to include %q", tc.Value, errors[0], tc.ExpectedErrSubstr)
+		}
+	}
+}
+
+func TestPrefixListId(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "pl-3b927c52", ErrCount: 0},
+		{Value: "pl-0123456789abcdef0", ErrCount: 0},
+		{Value: "", ErrCount: 1},
+		{Value: "pl-", ErrCount: 1},
+		{Value: "pl-XYZ", ErrCount: 1},
+		{Value: "sg-3b927c52", ErrCount: 1},
+		{Value: "com.amazonaws.global.cloudfront.origin-facing", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := PrefixListId(tc.Value, "prefix_list_id")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q: expected %d errors, got %d: %q", tc.Value, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	cases := []struct {
+		Min      time.Duration
+		Value    string
+		ErrCount int
+	}{
+		{Min: 0, Value: "0s", ErrCount: 0},
+		{Min: 0, Value: "30s", ErrCount: 0},
+		{Min: 0, Value: "15m", ErrCount: 0},
+		{Min: 0, Value: "1h30m", ErrCount: 0},
+		{Min: 0, Value: "-5m", ErrCount: 1},
+		{Min: 0, Value: "", ErrCount: 1},
+		{Min: 0, Value: "15", ErrCount: 1},
+		{Min: 0, Value: "1d", ErrCount: 1},
+		{Min: time.Hour, Value: "1h", ErrCount: 0},
+		{Min: time.Hour, Value: "720h", ErrCount: 0},
+		{Min: time.Hour, Value: "2160h30m", ErrCount: 0},
+		{Min: time.Hour, Value: "59m", ErrCount: 1},
+		{Min: time.Hour, Value: "-720h", ErrCount: 1},
+		{Min: time.Hour, Value: "30d", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := Duration(tc.Min)(tc.Value, "duration")
+		if len(errors) != tc.ErrCount {
+			t.Errorf("%q (at least %s): expected %d errors, got %d: %q", tc.Value, tc.Min, tc.ErrCount, len(errors), errors)
+		}
+	}
+}
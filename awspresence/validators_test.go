@@ -309,46 +309,6 @@ func TestValidateAwsAccountId(t *testing.T) {
 	}
 }
 
-func TestValidateArn(t *testing.T) {
-	v := ""
-	_, errors := validateArn(v, "arn")
-	if len(errors) != 0 {
-		t.Fatalf("%q should not be validated as an ARN: %q", v, errors)
-	}
-
-	validNames := []string{
-		"arn:aws:elasticbeanstalk:us-east-1:123456789012:environment/My App/MyEnvironment", // Beanstalk
-		"arn:aws:iam::123456789012:user/David",                                             // IAM User
-		"arn:aws:rds:eu-west-1:123456789012:db:mysql-db",                                   // RDS
-		"arn:aws:s3:::my_corporate_bucket/exampleobject.png",                               // S3 object
-		"arn:aws:events:us-east-1:319201112229:rule/rule_name",                             // CloudWatch Rule
-		"arn:aws:lambda:eu-west-1:319201112229:function:myCustomFunction",                  // Lambda function
-		"arn:aws:lambda:eu-west-1:319201112229:function:myCustomFunction:Qualifier",        // Lambda func qualifier
-		"arn:aws-us-gov:s3:::corp_bucket/object.png",                                       // GovCloud ARN
-		"arn:aws-us-gov:kms:us-gov-west-1:123456789012:key/some-uuid-abc123",               // GovCloud KMS ARN
-	}
-	for _, v := range validNames {
-		_, errors := validateArn(v, "arn")
-		if len(errors) != 0 {
-			t.Fatalf("%q should be a valid ARN: %q", v, errors)
-		}
-	}
-
-	invalidNames := []string{
-		"arn",
-		"123456789012",
-		"arn:aws",
-		"arn:aws:logs",
-		"arn:aws:logs:region:*:*",
-	}
-	for _, v := range invalidNames {
-		_, errors := validateArn(v, "arn")
-		if len(errors) == 0 {
-			t.Fatalf("%q should be an invalid ARN", v)
-		}
-	}
-}
-
 func TestValidateEC2AutomateARN(t *testing.T) {
 	validNames := []string{
 		"arn:aws:automate:us-east-1:ec2:reboot",
@@ -411,36 +371,6 @@ func TestValidatePolicyStatementId(t *testing.T) {
 	}
 }
 
-func TestValidateCIDRNetworkAddress(t *testing.T) {
-	cases := []struct {
-		CIDR              string
-		ExpectedErrSubstr string
-	}{
-		{"notacidr", `must contain a valid CIDR`},
-		{"10.0.1.0/16", `must contain a valid network CIDR`},
-		{"10.0.1.0/24", ``},
-	}
-
-	for i, tc := range cases {
-		_, errs := validateCIDRNetworkAddress(tc.CIDR, "foo")
-		if tc.ExpectedErrSubstr == "" {
-			if len(errs) != 0 {
-				t.Fatalf("%d/%d: Expected no error, got errs: %#v",
-					i+1, len(cases), errs)
-			}
-		} else {
-			if len(errs) != 1 {
-				t.Fatalf("%d/%d: Expected 1 err containing %q, got %d errs",
-					i+1, len(cases), tc.ExpectedErrSubstr, len(errs))
-			}
-			if !strings.Contains(errs[0].Error(), tc.ExpectedErrSubstr) {
-				t.Fatalf("%d/%d: Expected err: %q, to include %q",
-					i+1, len(cases), errs[0], tc.ExpectedErrSubstr)
-			}
-		}
-	}
-}
-
 func TestValidateLogMetricFilterName(t *testing.T) {
 	validNames := []string{
 		"YadaHereAndThere",
@@ -1441,59 +1371,6 @@ func TestValidateApiGatewayUsagePlanQuotaSettings(t *testing.T) {
 	}
 }
 
-func TestValidateElbName(t *testing.T) {
-	validNames := []string{
-		"tf-test-elb",
-	}
-
-	for _, s := range validNames {
-		_, errors := validateElbName(s, "name")
-		if len(errors) > 0 {
-			t.Fatalf("%q should be a valid ELB name: %v", s, errors)
-		}
-	}
-
-	invalidNames := []string{
-		"tf.test.elb.1",
-		"tf-test-elb-tf-test-elb-tf-test-elb",
-		"-tf-test-elb",
-		"tf-test-elb-",
-	}
-
-	for _, s := range invalidNames {
-		_, errors := validateElbName(s, "name")
-		if len(errors) == 0 {
-			t.Fatalf("%q should not be a valid ELB name: %v", s, errors)
-		}
-	}
-}
-
-func TestValidateElbNamePrefix(t *testing.T) {
-	validNamePrefixes := []string{
-		"test-",
-	}
-
-	for _, s := range validNamePrefixes {
-		_, errors := validateElbNamePrefix(s, "name_prefix")
-		if len(errors) > 0 {
-			t.Fatalf("%q should be a valid ELB name prefix: %v", s, errors)
-		}
-	}
-
-	invalidNamePrefixes := []string{
-		"tf.test.elb.",
-		"tf-test",
-		"-test",
-	}
-
-	for _, s := range invalidNamePrefixes {
-		_, errors := validateElbNamePrefix(s, "name_prefix")
-		if len(errors) == 0 {
-			t.Fatalf("%q should not be a valid ELB name prefix: %v", s, errors)
-		}
-	}
-}
-
 func TestValidateNeptuneEventSubscriptionName(t *testing.T) {
 	cases := []struct {
 		Value    string
@@ -2747,62 +2624,6 @@ func TestValidateDxConnectionBandWidth(t *testing.T) {
 	}
 }
 
-func TestValidateLbTargetGroupName(t *testing.T) {
-	cases := []struct {
-		Value    string
-		ErrCount int
-	}{
-		{
-			Value:    "tf.test.elb.target.1",
-			ErrCount: 1,
-		},
-		{
-			Value:    "-tf-test-target",
-			ErrCount: 1,
-		},
-		{
-			Value:    "tf-test-target-",
-			ErrCount: 1,
-		},
-		{
-			Value:    randomString(33),
-			ErrCount: 1,
-		},
-	}
-	for _, tc := range cases {
-		_, errors := validateLbTargetGroupName(tc.Value, "aws_lb_target_group")
-		if len(errors) != tc.ErrCount {
-			t.Fatalf("Expected the AWS LB Target Group Name to trigger a validation error for %q", tc.Value)
-		}
-	}
-}
-
-func TestValidateLbTargetGroupNamePrefix(t *testing.T) {
-	cases := []struct {
-		Value    string
-		ErrCount int
-	}{
-		{
-			Value:    "tf.lb",
-			ErrCount: 1,
-		},
-		{
-			Value:    "-tf-lb",
-			ErrCount: 1,
-		},
-		{
-			Value:    randomString(32),
-			ErrCount: 1,
-		},
-	}
-	for _, tc := range cases {
-		_, errors := validateLbTargetGroupNamePrefix(tc.Value, "aws_lb_target_group")
-		if len(errors) != tc.ErrCount {
-			t.Fatalf("Expected the AWS LB Target Group Name to trigger a validation error for %q", tc.Value)
-		}
-	}
-}
-
 func TestValidateSecretManagerSecretName(t *testing.T) {
 	cases := []struct {
 		Value    string
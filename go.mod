@@ -22,7 +22,6 @@ require (
 	github.com/pquerna/otp v1.2.0
 	github.com/spf13/pflag v1.0.3 // indirect
 	github.com/terraform-providers/terraform-provider-template v2.1.2+incompatible
-	github.com/terraform-providers/terraform-provider-tls v2.0.1+incompatible
 	gopkg.in/yaml.v2 v2.2.2
 	k8s.io/apimachinery v0.0.0-20190204010555-a98ff070d70e // indirect
 	k8s.io/client-go v10.0.0+incompatible // indirect
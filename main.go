@@ -8,4 +8,7 @@ import (
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: awspresence.Provider})
+
+	awspresence.LogApiCallSummary()
+	awspresence.WriteLbMetricsFile()
 }